@@ -0,0 +1,179 @@
+// Package config reads devspace.yaml files without the devspace CLI. It
+// parses v2beta1 configs, applies profiles (replace, merge and patches,
+// including parent profiles) and resolves the variables that do not need a
+// cluster or user input, so project settings can be inspected even when the
+// cluster or credentials are unavailable.
+//
+// Runtime values such as ${runtime.images.api.image}, variables resolved by
+// a command and input variables without a remembered value are left in place
+// and reported as unresolved.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SupportedVersion is the config version this package understands
+const SupportedVersion = "v2beta1"
+
+// Config is a parsed devspace config before profiles and variables are applied
+type Config struct {
+	// Path is the file the config was loaded from, if any
+	Path string
+	// Doc is the raw document
+	Doc map[string]any
+}
+
+// Profile describes a profile defined in the config
+type Profile struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Parents     []string `json:"parents,omitempty"`
+}
+
+// Load reads and parses the config at path
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	c.Path = path
+	return c, nil
+}
+
+// Parse parses a devspace config document
+func Parse(data []byte) (*Config, error) {
+	var doc map[string]any
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("config is empty")
+	}
+
+	version, _ := doc["version"].(string)
+	switch version {
+	case SupportedVersion:
+	case "":
+		return nil, fmt.Errorf("version is missing: expected %s", SupportedVersion)
+	default:
+		return nil, fmt.Errorf("unsupported config version %s: expected %s", version, SupportedVersion)
+	}
+	return &Config{Doc: doc}, nil
+}
+
+// Name returns the name of the project
+func (c *Config) Name() string {
+	name, _ := c.Doc["name"].(string)
+	return name
+}
+
+// Profiles returns the profiles in the order they are defined
+func (c *Config) Profiles() []Profile {
+	var profiles []Profile
+	for _, p := range profileList(c.Doc) {
+		profile := Profile{Name: stringField(p, "name"), Description: stringField(p, "description")}
+		profile.Parents = profileParents(p)
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// Var describes a variable definition
+type Var struct {
+	Name string `json:"name"`
+	// Source is where the value comes from: all, env, input, command or none
+	Source   string `json:"source"`
+	Value    any    `json:"value,omitempty"`
+	Default  any    `json:"default,omitempty"`
+	Password bool   `json:"password,omitempty"`
+	// Command is the shell command printing the value, if any
+	Command string `json:"command,omitempty"`
+}
+
+// Vars returns the variable definitions of the raw config, sorted by name
+func (c *Config) Vars() []Var {
+	return varDefinitions(c.Doc)
+}
+
+// profileList returns the profile definitions of a document
+func profileList(doc map[string]any) []map[string]any {
+	list, _ := doc["profiles"].([]any)
+	profiles := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if p, ok := item.(map[string]any); ok {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
+// profileParents returns the parent profile names of a profile definition,
+// from the parents list and the deprecated parent field
+func profileParents(p map[string]any) []string {
+	var parents []string
+	if parent := stringField(p, "parent"); parent != "" {
+		parents = append(parents, parent)
+	}
+	list, _ := p["parents"].([]any)
+	for _, item := range list {
+		switch item := item.(type) {
+		case string:
+			parents = append(parents, item)
+		case map[string]any:
+			if name := stringField(item, "profile"); name != "" {
+				parents = append(parents, name)
+			}
+		}
+	}
+	return parents
+}
+
+// stringField returns a string field of a map, or "" if it is not a string
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// deepCopy copies a decoded YAML value so it can be modified
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[k] = deepCopy(item)
+		}
+		return m
+	case []any:
+		l := make([]any, len(v))
+		for i, item := range v {
+			l[i] = deepCopy(item)
+		}
+		return l
+	default:
+		return v
+	}
+}
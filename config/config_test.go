@@ -0,0 +1,242 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readExpected reads the 'devspace print --skip-info' output of a fixture
+func readExpected(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// envLookup returns a LookupEnv function backed by a map
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestResolveMatchesPrint(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		expected string
+		opts     Options
+	}{
+		{
+			name:     "variables",
+			fixture:  "basic",
+			expected: "print.yaml",
+			opts:     Options{LookupEnv: envLookup(map[string]string{"DEBUG": "true", "TAG": "v1.4.0"})},
+		},
+		{
+			name:     "profile with parents",
+			fixture:  "profiles",
+			expected: "print-production.yaml",
+			opts:     Options{Profiles: []string{"production"}, LookupEnv: envLookup(nil)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(filepath.Join("testdata", tt.fixture, "devspace.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resolved, err := c.Resolve(tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := readExpected(t, filepath.Join("testdata", tt.fixture, tt.expected))
+			if !reflect.DeepEqual(resolved.Config, want) {
+				got, _ := yaml.Marshal(resolved.Config)
+				t.Errorf("resolved config differs from devspace print:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestResolveVars(t *testing.T) {
+	c, err := Load(filepath.Join("testdata", "basic", "devspace.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := c.Resolve(Options{
+		Vars:      map[string]string{"REPLICAS": "5"},
+		LookupEnv: envLookup(nil),
+		Cache:     map[string]string{"TAG": "cached"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]ResolvedVar{
+		"API_VERSION": {Name: "API_VERSION", Unresolved: "set by a command"},
+		"DB_HOST":     {Name: "DB_HOST", Value: "postgres.dev", Source: "default"},
+		"DEBUG":       {Name: "DEBUG", Value: "false", Source: "default"},
+		"IMAGE":       {Name: "IMAGE", Value: "registry.example.com/api", Source: "value"},
+		"REPLICAS":    {Name: "REPLICAS", Value: "5", Source: "override"},
+		// Env-only vars ignore remembered input values
+		"TAG": {Name: "TAG", Value: "", Source: "default"},
+	}
+	for _, v := range resolved.Vars {
+		if expected, ok := want[v.Name]; ok && v != expected {
+			t.Errorf("var %s = %+v, want %+v", v.Name, v, expected)
+		}
+	}
+	if got := len(resolved.Vars); got != 7 {
+		t.Errorf("expected 7 vars, got %d: %+v", got, resolved.Vars)
+	}
+	values := resolved.Config["deployments"].(map[string]any)["api"].(map[string]any)["helm"].(map[string]any)["values"].(map[string]any)
+	if values["replicaCount"] != 5 {
+		t.Errorf("replicaCount = %#v, want 5", values["replicaCount"])
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	c, err := Load(filepath.Join("testdata", "profiles", "devspace.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles := c.Profiles()
+	if len(profiles) != 3 || profiles[2].Name != "production" || profiles[2].Description != "Production settings" {
+		t.Fatalf("unexpected profiles: %+v", profiles)
+	}
+	if strings.Join(profiles[2].Parents, ",") != "remote-registry,no-worker" {
+		t.Errorf("unexpected parents: %v", profiles[2].Parents)
+	}
+
+	// The raw config is not modified by resolving
+	if _, err := c.Resolve(Options{Profiles: []string{"no-worker"}}); err != nil {
+		t.Fatal(err)
+	}
+	if c.Doc["images"].(map[string]any)["worker"] == nil {
+		t.Error("resolving must not modify the loaded config")
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		name, config, profile, want string
+	}{
+		{"unknown profile", "version: v2beta1\n", "missing", `profile "missing" not found`},
+		{"parent cycle", "version: v2beta1\nprofiles:\n  - name: a\n    parent: b\n  - name: b\n    parent: a\n", "a", "a -> b -> a"},
+		{"missing path", "version: v2beta1\nprofiles:\n  - name: p\n    patches:\n      - op: replace\n        path: images.api\n        value: x\n", "p", "path images.api not found"},
+		{"bad op", "version: v2beta1\nimages: {}\nprofiles:\n  - name: p\n    patches:\n      - op: move\n        path: images\n", "p", `unknown op "move"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Parse([]byte(tt.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.Resolve(Options{Profiles: []string{tt.profile}})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for doc, want := range map[string]string{
+		"version: v1beta11\n": "unsupported config version v1beta11",
+		"name: api\n":         "version is missing",
+		"":                    "config is empty",
+	} {
+		if _, err := Parse([]byte(doc)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want %q", doc, err, want)
+		}
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name, op, path string
+		value          any
+		want           string
+	}{
+		{"json pointer index", opReplace, "/dev/ports/0/port", 9000, "ports:\n    - port: 9000\n    - port: 8081\n"},
+		{"insert at index", opAdd, "dev.ports[1]", map[string]any{"port": 7000}, "ports:\n    - port: 8080\n    - port: 7000\n    - port: 8081\n"},
+		{"append with dash", opAdd, "/dev/ports/-", map[string]any{"port": 7000}, "ports:\n    - port: 8080\n    - port: 8081\n    - port: 7000\n"},
+		{"remove by selector", opRemove, "dev.ports[port=8080]", nil, "ports:\n    - port: 8081\n"},
+		{"legacy selector", opReplace, "dev.ports.port=8081.port", 1, "ports:\n    - port: 8080\n    - port: 1\n"},
+		{"wildcard", opReplace, "dev.ports[*].port", 1, "ports:\n    - port: 1\n    - port: 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := map[string]any{"dev": map[string]any{"ports": []any{
+				map[string]any{"port": 8080},
+				map[string]any{"port": 8081},
+			}}}
+			if err := applyPatch(doc, tt.op, tt.path, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := yaml.Marshal(doc["dev"])
+			if string(got) != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInteractiveDevConfigs(t *testing.T) {
+	doc := `version: v2beta1
+dev:
+  api:
+    imageSelector: api
+    terminal:
+      command: ./devspace_start.sh
+  worker:
+    imageSelector: worker
+    terminal:
+      disabled: true
+    containers:
+      sidecar:
+        attach: {}
+  web:
+    imageSelector: web
+profiles:
+  - name: headless
+    patches:
+      - op: add
+        path: dev.api.terminal.disabled
+        value: true
+      - op: remove
+        path: dev.worker.containers.sidecar.attach
+`
+	c, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := c.Resolve(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(r.InteractiveDevConfigs(), ","); got != "dev.api,dev.worker.containers.sidecar" {
+		t.Errorf("InteractiveDevConfigs() = %s", got)
+	}
+
+	r, err = c.Resolve(Options{Profiles: []string{"headless"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.InteractiveDevConfigs(); len(got) != 0 {
+		t.Errorf("the headless profile disables all terminals, got %v", got)
+	}
+}
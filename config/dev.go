@@ -0,0 +1,35 @@
+package config
+
+// InteractiveDevConfigs returns the dev configs that open a terminal in or
+// attach to a dev container, as dev.NAME or dev.NAME.containers.CONTAINER.
+// devspace dev waits for user input on these and cannot run in the
+// background.
+func (r *Resolved) InteractiveDevConfigs() []string {
+	var interactive []string
+	dev, _ := r.Config["dev"].(map[string]any)
+	for _, name := range sortedKeys(dev) {
+		cfg, _ := dev[name].(map[string]any)
+		if opensTerminal(cfg) {
+			interactive = append(interactive, "dev."+name)
+		}
+		containers, _ := cfg["containers"].(map[string]any)
+		for _, container := range sortedKeys(containers) {
+			if c, _ := containers[container].(map[string]any); opensTerminal(c) {
+				interactive = append(interactive, "dev."+name+".containers."+container)
+			}
+		}
+	}
+	return interactive
+}
+
+// opensTerminal reports whether a dev config has an enabled terminal or
+// attach section
+func opensTerminal(cfg map[string]any) bool {
+	for _, key := range []string{"terminal", "attach"} {
+		section, ok := cfg[key].(map[string]any)
+		if ok && section["disabled"] != true {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,449 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Lint rules
+const (
+	RuleSyntax            = "syntax"
+	RuleSchema            = "schema"
+	RuleUndefinedImage    = "undefined-image"
+	RuleUnmatchedSelector = "unmatched-selector"
+	RuleDuplicatePort     = "duplicate-port"
+	RuleMissingSyncPath   = "missing-sync-path"
+	RuleUnusedVar         = "unused-var"
+	RuleInvalidPatch      = "invalid-patch"
+)
+
+// Issue is a problem found in a config
+type Issue struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	// Path is the location in the config, e.g. dev.api.ports[0]
+	Path    string `json:"path,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// runtimeImagePattern matches references to built images such as
+// ${runtime.images.api.image}
+var runtimeImagePattern = regexp.MustCompile(`runtime\.images\.([A-Za-z0-9_-]+)`)
+
+// referencePattern matches variable references in config values and, as
+// devspace exports vars to pipelines and commands, in shell scripts
+var referencePattern = regexp.MustCompile(`\$!?\{?\s*([A-Za-z_][A-Za-z0-9_]*)`)
+
+// yamlLinePattern finds the line number in YAML syntax errors
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// Lint validates the config at path against the v2beta1 schema and checks it
+// for semantic problems. Sync paths are checked relative to its directory.
+func Lint(path string) ([]Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return lint(data, filepath.Dir(path)), nil
+}
+
+// lint checks a config document. Issues are sorted by position.
+func lint(data []byte, dir string) []Issue {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		issue := Issue{Severity: SeverityError, Rule: RuleSyntax, Message: err.Error()}
+		if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+		}
+		return []Issue{issue}
+	}
+	if len(root.Content) == 0 {
+		return []Issue{{Severity: SeverityError, Rule: RuleSyntax, Message: "config is empty"}}
+	}
+	doc := root.Content[0]
+
+	var issues []Issue
+	if version := nodeAt(doc, "version"); version != nil && version.Kind == yaml.ScalarNode && version.Value != SupportedVersion {
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Rule:     RuleSchema,
+			Path:     "version",
+			Line:     version.Line,
+			Column:   version.Column,
+			Message:  fmt.Sprintf("unsupported config version %s: expected %s", version.Value, SupportedVersion),
+		})
+		return issues
+	}
+	v2beta1.validate(doc, nil, &issues)
+
+	var raw map[string]any
+	if err := doc.Decode(&raw); err == nil {
+		l := &linter{root: doc, doc: raw, dir: dir}
+		l.images()
+		l.selectors()
+		l.ports()
+		l.syncPaths()
+		l.unusedVars()
+		l.profiles()
+		issues = append(issues, l.issues...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
+
+// linter runs the semantic checks on a parsed config
+type linter struct {
+	root   *yaml.Node
+	doc    map[string]any
+	dir    string
+	issues []Issue
+}
+
+// add records an issue at the node of path, or the closest existing parent
+func (l *linter) add(severity, rule string, path []string, format string, args ...any) {
+	issue := Issue{Severity: severity, Rule: rule, Path: formatPath(path), Message: fmt.Sprintf(format, args...)}
+	if n := closestNode(l.root, path); n != nil {
+		issue.Line, issue.Column = n.Line, n.Column
+	}
+	l.issues = append(l.issues, issue)
+}
+
+// section returns a top level map section of the config
+func (l *linter) section(name string) map[string]any {
+	m, _ := l.doc[name].(map[string]any)
+	return m
+}
+
+// images reports references to images that are not defined in images
+func (l *linter) images() {
+	images := l.section("images")
+	walkStrings(l.root, nil, func(value string, path []string) {
+		for _, m := range runtimeImagePattern.FindAllStringSubmatch(value, -1) {
+			if _, ok := images[m[1]]; !ok {
+				l.add(SeverityError, RuleUndefinedImage, path, "image %q is not defined in images", m[1])
+			}
+		}
+	})
+}
+
+// selectors reports dev configurations whose image or label selector does
+// not match any deployment. Only deployments of the component chart are
+// compared: kubectl manifests and custom charts set labels that cannot be
+// known without rendering them.
+func (l *linter) selectors() {
+	var labels []map[string]string
+	var images []string
+	for name, item := range l.section("deployments") {
+		deployment, _ := item.(map[string]any)
+		helm, _ := deployment["helm"].(map[string]any)
+		if deployment["kubectl"] != nil || helm == nil || !usesComponentChart(helm) {
+			continue
+		}
+		release := name
+		if r := stringField(helm, "releaseName"); r != "" {
+			release = r
+		}
+		values, _ := helm["values"].(map[string]any)
+		deploymentLabels := map[string]string{"app.kubernetes.io/component": release}
+		if custom, ok := values["labels"].(map[string]any); ok {
+			for k, v := range custom {
+				deploymentLabels[k] = fmt.Sprint(v)
+			}
+		}
+		labels = append(labels, deploymentLabels)
+		collectStrings(values, func(s string) { images = append(images, s) })
+	}
+	if len(labels) == 0 {
+		return
+	}
+	for _, item := range l.section("images") {
+		if image, ok := item.(map[string]any); ok {
+			images = append(images, stringField(image, "image"))
+		}
+	}
+
+	for _, name := range sortedKeys(l.section("dev")) {
+		dev, _ := l.section("dev")[name].(map[string]any)
+		if selector := stringField(dev, "imageSelector"); selector != "" && !matchesImage(selector, images) {
+			l.add(SeverityWarning, RuleUnmatchedSelector, []string{"dev", name, "imageSelector"},
+				"image selector %s does not match the image of any deployment", selector)
+		}
+		if selector, ok := dev["labelSelector"].(map[string]any); ok && len(selector) > 0 && !matchesLabels(selector, labels) {
+			l.add(SeverityWarning, RuleUnmatchedSelector, []string{"dev", name, "labelSelector"},
+				"label selector does not match the pods of any deployment")
+		}
+	}
+}
+
+// usesComponentChart reports whether a helm deployment uses the DevSpace
+// component chart, which is the default when no chart is set
+func usesComponentChart(helm map[string]any) bool {
+	if helm["chart"] == nil {
+		return true
+	}
+	chart, _ := helm["chart"].(map[string]any)
+	return strings.HasSuffix(stringField(chart, "name"), "component-chart")
+}
+
+// matchesImage reports whether an image selector selects one of the images
+func matchesImage(selector string, images []string) bool {
+	// Variables, including references to built images, are resolved at
+	// runtime; undefined images are reported separately
+	if strings.Contains(selector, "${") {
+		return true
+	}
+	for _, image := range images {
+		if image == selector || strings.HasPrefix(image, selector+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesLabels reports whether all labels of a selector are set on one of
+// the deployments
+func matchesLabels(selector map[string]any, deployments []map[string]string) bool {
+	for _, labels := range deployments {
+		matched := true
+		for k, v := range selector {
+			value := fmt.Sprint(v)
+			if labels[k] != value && !strings.Contains(value, "${") {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ports reports local ports forwarded by more than one dev configuration
+func (l *linter) ports() {
+	seen := make(map[string]string)
+	dev := l.section("dev")
+	for _, name := range sortedKeys(dev) {
+		entry, _ := dev[name].(map[string]any)
+		ports, _ := entry["ports"].([]any)
+		for i, item := range ports {
+			port, _ := item.(map[string]any)
+			if port["port"] == nil {
+				continue
+			}
+			local, _, _ := strings.Cut(fmt.Sprint(port["port"]), ":")
+			path := []string{"dev", name, "ports", strconv.Itoa(i), "port"}
+			if first, ok := seen[local]; ok {
+				l.add(SeverityError, RuleDuplicatePort, path, "local port %s is already forwarded by %s", local, first)
+				continue
+			}
+			seen[local] = formatPath(path[:4])
+		}
+	}
+}
+
+// syncPaths reports sync configurations whose local path does not exist
+func (l *linter) syncPaths() {
+	dev := l.section("dev")
+	for _, name := range sortedKeys(dev) {
+		entry, _ := dev[name].(map[string]any)
+		l.checkSync(entry, []string{"dev", name})
+		containers, _ := entry["containers"].(map[string]any)
+		for _, container := range sortedKeys(containers) {
+			c, _ := containers[container].(map[string]any)
+			l.checkSync(c, []string{"dev", name, "containers", container})
+		}
+	}
+}
+
+// checkSync checks the sync paths of a dev configuration or container
+func (l *linter) checkSync(entry map[string]any, path []string) {
+	syncs, _ := entry["sync"].([]any)
+	for i, item := range syncs {
+		sync, _ := item.(map[string]any)
+		local, _, _ := strings.Cut(stringField(sync, "path"), ":")
+		if local == "" {
+			local = "."
+		}
+		if strings.Contains(local, "${") {
+			continue
+		}
+		if !filepath.IsAbs(local) {
+			local = filepath.Join(l.dir, local)
+		}
+		if _, err := os.Stat(local); errors.Is(err, os.ErrNotExist) {
+			l.add(SeverityWarning, RuleMissingSyncPath, append(path, "sync", strconv.Itoa(i), "path"),
+				"local sync path %s does not exist", local)
+		}
+	}
+}
+
+// unusedVars reports variables that are never referenced
+func (l *linter) unusedVars() {
+	referenced := make(map[string]bool)
+	walkStrings(l.root, nil, func(value string, path []string) {
+		for _, m := range referencePattern.FindAllStringSubmatch(value, -1) {
+			referenced[m[1]] = true
+		}
+	})
+	// varDefinitions sorts list-form vars by name, so look up the position
+	// of each var in the list
+	list, isList := l.doc["vars"].([]any)
+	index := make(map[string]int)
+	for i, item := range list {
+		if def, ok := item.(map[string]any); ok {
+			if name := stringField(def, "name"); name != "" {
+				if _, seen := index[name]; !seen {
+					index[name] = i
+				}
+			}
+		}
+	}
+	for _, v := range varDefinitions(l.doc) {
+		if referenced[v.Name] {
+			continue
+		}
+		path := []string{"vars", v.Name}
+		if isList {
+			path = []string{"vars", strconv.Itoa(index[v.Name])}
+		}
+		l.add(SeverityWarning, RuleUnusedVar, path, "variable %s is never used", v.Name)
+	}
+}
+
+// profiles applies every profile and reports patches that cannot be applied
+func (l *linter) profiles() {
+	definitions := profileList(l.doc)
+	reported := make(map[string]bool)
+	for i, p := range definitions {
+		name := stringField(p, "name")
+		if name == "" {
+			continue
+		}
+		_, err := applyProfiles(l.doc, []string{name})
+		if err == nil {
+			continue
+		}
+		path := []string{"profiles", strconv.Itoa(i)}
+		var patchErr *PatchError
+		if errors.As(err, &patchErr) {
+			for j, def := range definitions {
+				if stringField(def, "name") == patchErr.Profile {
+					path = []string{"profiles", strconv.Itoa(j), "patches", strconv.Itoa(patchErr.Index)}
+				}
+			}
+		}
+		// A broken parent fails every child the same way
+		if reported[err.Error()] {
+			continue
+		}
+		reported[err.Error()] = true
+		l.add(SeverityError, RuleInvalidPatch, path, "%v", err)
+	}
+}
+
+// nodeAt returns the value of a key in a mapping node
+func nodeAt(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// closestNode follows a path of keys and list indexes and returns the last
+// node found on the way
+func closestNode(n *yaml.Node, path []string) *yaml.Node {
+	for _, token := range path {
+		var next *yaml.Node
+		switch n.Kind {
+		case yaml.MappingNode:
+			next = nodeAt(n, token)
+		case yaml.SequenceNode:
+			if i, err := strconv.Atoi(token); err == nil && i >= 0 && i < len(n.Content) {
+				next = n.Content[i]
+			}
+		}
+		if next == nil {
+			return n
+		}
+		n = next
+	}
+	return n
+}
+
+// walkStrings calls fn for every string value below a node
+func walkStrings(n *yaml.Node, path []string, fn func(value string, path []string)) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Tag == "!!str" {
+			fn(n.Value, path)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			walkStrings(n.Content[i+1], append(path, n.Content[i].Value), fn)
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			walkStrings(item, append(path, strconv.Itoa(i)), fn)
+		}
+	}
+}
+
+// collectStrings calls fn for every string in a decoded YAML value
+func collectStrings(value any, fn func(string)) {
+	switch v := value.(type) {
+	case string:
+		fn(v)
+	case map[string]any:
+		for _, item := range v {
+			collectStrings(item, fn)
+		}
+	case []any:
+		for _, item := range v {
+			collectStrings(item, fn)
+		}
+	}
+}
+
+// formatPath renders path tokens as dev.api.ports[0]
+func formatPath(path []string) string {
+	var b strings.Builder
+	for _, token := range path {
+		if _, err := strconv.Atoi(token); err == nil {
+			fmt.Fprintf(&b, "[%s]", token)
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString(".")
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
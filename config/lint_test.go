@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintFixture(t *testing.T) {
+	issues, err := Lint(filepath.Join("testdata", "lint", "devspace.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, fmt.Sprintf("%d:%d %s %s %s", issue.Line, issue.Column, issue.Severity, issue.Rule, issue.Path))
+	}
+	want := []string{
+		"6:11 warning unused-var vars.UNUSED",
+		"11:5 error schema images.api",
+		"18:20 error undefined-image deployments.api.helm.values.containers[0].image",
+		"31:15 warning missing-sync-path dev.api.sync[1].path",
+		"33:20 warning unmatched-selector dev.worker.imageSelector",
+		"35:15 error duplicate-port dev.worker.ports[0].port",
+		"40:9 error invalid-patch profiles[0].patches[0]",
+		"45:9 error invalid-patch profiles[1].patches[0]",
+		"45:13 error schema profiles[1].patches[0].op",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if msg := issues[1].Message; msg != `unknown field "dockerfil", did you mean "dockerfile"?` {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
+
+func TestLintCleanConfig(t *testing.T) {
+	for _, fixture := range []string{"basic", "profiles"} {
+		issues, err := Lint(filepath.Join("testdata", fixture, "devspace.yaml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, issue := range issues {
+			t.Errorf("%s: unexpected issue %+v", fixture, issue)
+		}
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		name, config, want string
+	}{
+		{"syntax error", "version: v2beta1\nname: [a\n", "1:0 syntax"},
+		{"old version", "version: v1beta11\n", "1:10 schema unsupported config version v1beta11"},
+		{"missing name", "version: v2beta1\n", `1:1 schema missing required field "name"`},
+		{"wrong type", "version: v2beta1\nname: a\nimages: []\n", "3:9 schema expected an object, got a list"},
+		{"wrong scalar", "version: v2beta1\nname: a\nimages:\n  api:\n    image: a\n    skipPush: yes please\n", "6:15 schema expected a boolean, got a string"},
+		{"variable scalar", "version: v2beta1\nname: a\nvars:\n  PUSH: false\nimages:\n  api:\n    image: a\n    skipPush: ${PUSH}\n", ""},
+		{"invalid enum", "version: v2beta1\nname: ${A}\nvars:\n  A:\n    source: file\n", "5:13 schema invalid value \"file\""},
+		{"shell reference", "version: v2beta1\nname: a\nvars:\n  A: b\npipelines:\n  dev: echo $A\n", ""},
+		{"label selector", "version: v2beta1\nname: a\ndeployments:\n  api:\n    helm: {}\ndev:\n  web:\n    labelSelector:\n      app.kubernetes.io/component: web\n", "9:7 unmatched-selector label selector"},
+		{"kubectl next to component chart", "version: v2beta1\nname: a\ndeployments:\n  api:\n    helm: {}\n  db:\n    kubectl:\n      manifests: [k8s]\ndev:\n  web:\n    labelSelector:\n      app.kubernetes.io/component: web\n", "12:7 unmatched-selector label selector"},
+		{"custom helm chart", "version: v2beta1\nname: a\ndeployments:\n  api:\n    helm:\n      chart:\n        name: ./chart\ndev:\n  api:\n    labelSelector:\n      app: api\n", ""},
+		{"custom chart next to component chart", "version: v2beta1\nname: a\ndeployments:\n  api:\n    helm:\n      chart:\n        name: ./chart\n  web:\n    helm:\n      chart:\n        name: component-chart\n        repo: https://charts.devspace.sh\ndev:\n  web:\n    labelSelector:\n      app.kubernetes.io/component: web\n", ""},
+		{"kubectl deployments", "version: v2beta1\nname: a\ndeployments:\n  api:\n    kubectl:\n      manifests: [k8s]\ndev:\n  web:\n    imageSelector: web\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range lint([]byte(tt.config), t.TempDir()) {
+				got = append(got, fmt.Sprintf("%d:%d %s %s", issue.Line, issue.Column, issue.Rule, issue.Message))
+			}
+			if tt.want == "" {
+				if len(got) > 0 {
+					t.Errorf("expected no issues, got %v", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.HasPrefix(got[0], tt.want) {
+				t.Errorf("issues = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintUnusedListVar(t *testing.T) {
+	config := "version: v2beta1\nname: ${ZED}\nvars:\n  - name: ZED\n  - name: ALPHA\n"
+	issues := lint([]byte(config), t.TempDir())
+	if len(issues) != 1 {
+		t.Fatalf("issues = %+v, want one", issues)
+	}
+	if got := issues[0]; got.Rule != RuleUnusedVar || got.Path != "vars[1]" || got.Line != 5 || got.Message != "variable ALPHA is never used" {
+		t.Errorf("unexpected issue %+v", got)
+	}
+}
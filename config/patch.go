@@ -0,0 +1,229 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Patch operations
+const (
+	opAdd     = "add"
+	opReplace = "replace"
+	opRemove  = "remove"
+)
+
+// target is the container holding the value a patch path points to
+type target struct {
+	container any
+	// set replaces the container in its parent, needed when a list grows or
+	// shrinks
+	set   func(any)
+	token string
+}
+
+// applyPatch applies a profile patch to doc. Paths are JSON pointers such as
+// /images/api/image or dotted paths such as images.api.image, where list
+// items are addressed by index (ports[0]), by field (deployments[name=api]
+// or name=api) or all at once (*).
+func applyPatch(doc map[string]any, op, path string, value any) error {
+	switch op {
+	case opAdd, opReplace, opRemove:
+	case "":
+		return fmt.Errorf("op is missing")
+	default:
+		return fmt.Errorf("unknown op %q: must be add, replace or remove", op)
+	}
+	tokens, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	targets := findTargets(doc, func(any) {}, tokens, op == opAdd)
+	if len(targets) == 0 {
+		return fmt.Errorf("path %s not found", path)
+	}
+	for _, t := range targets {
+		if err := t.apply(op, value); err != nil {
+			return fmt.Errorf("path %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// parsePath splits a JSON pointer or dotted path into tokens
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is missing")
+	}
+	if strings.HasPrefix(path, "/") {
+		tokens := strings.Split(path[1:], "/")
+		for i, t := range tokens {
+			tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+		}
+		return tokens, nil
+	}
+
+	var tokens []string
+	var current strings.Builder
+	inBracket := false
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range path {
+		switch {
+		case r == '[' && !inBracket:
+			flush()
+			inBracket = true
+		case r == ']' && inBracket:
+			flush()
+			inBracket = false
+		case r == '.' && !inBracket:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if inBracket {
+		return nil, fmt.Errorf("unclosed [ in path %s", path)
+	}
+	flush()
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid path %s", path)
+	}
+	return tokens, nil
+}
+
+// findTargets returns the containers the last token of a path applies to.
+// With create, missing maps along the path are created.
+func findTargets(value any, set func(any), tokens []string, create bool) []target {
+	if len(tokens) == 1 {
+		switch value.(type) {
+		case map[string]any, []any:
+			return []target{{container: value, set: set, token: tokens[0]}}
+		}
+		return nil
+	}
+
+	token, rest := tokens[0], tokens[1:]
+	var targets []target
+	switch c := value.(type) {
+	case map[string]any:
+		if token == "*" {
+			for _, key := range sortedKeys(c) {
+				key := key
+				targets = append(targets, findTargets(c[key], func(v any) { c[key] = v }, rest, create)...)
+			}
+			return targets
+		}
+		child, ok := c[token]
+		if !ok || child == nil {
+			if !create {
+				return nil
+			}
+			child = map[string]any{}
+			c[token] = child
+		}
+		return findTargets(child, func(v any) { c[token] = v }, rest, create)
+	case []any:
+		for _, i := range listIndexes(c, token) {
+			i := i
+			targets = append(targets, findTargets(c[i], func(v any) { c[i] = v }, rest, create)...)
+		}
+	}
+	return targets
+}
+
+// listIndexes returns the indexes of the list items a token selects
+func listIndexes(list []any, token string) []int {
+	if token == "*" {
+		indexes := make([]int, len(list))
+		for i := range list {
+			indexes[i] = i
+		}
+		return indexes
+	}
+	if i, err := strconv.Atoi(token); err == nil {
+		if i >= 0 && i < len(list) {
+			return []int{i}
+		}
+		return nil
+	}
+	if key, want, ok := strings.Cut(token, "="); ok {
+		var indexes []int
+		for i, item := range list {
+			if m, ok := item.(map[string]any); ok && m[key] != nil && fmt.Sprint(m[key]) == want {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+	return nil
+}
+
+// apply performs a patch operation on the target
+func (t target) apply(op string, value any) error {
+	switch c := t.container.(type) {
+	case map[string]any:
+		existing, exists := c[t.token]
+		switch op {
+		case opAdd:
+			// Adding to a list appends, as devspace does
+			if list, ok := existing.([]any); ok {
+				if _, isList := value.([]any); !isList {
+					c[t.token] = append(list, deepCopy(value))
+					return nil
+				}
+			}
+			c[t.token] = deepCopy(value)
+		case opReplace:
+			if !exists {
+				return fmt.Errorf("%s not found", t.token)
+			}
+			c[t.token] = deepCopy(value)
+		case opRemove:
+			if !exists {
+				return fmt.Errorf("%s not found", t.token)
+			}
+			delete(c, t.token)
+		}
+		return nil
+
+	case []any:
+		if op == opAdd && t.token == "-" {
+			t.set(append(c, deepCopy(value)))
+			return nil
+		}
+		if op == opAdd {
+			i, err := strconv.Atoi(t.token)
+			if err != nil || i < 0 || i > len(c) {
+				return fmt.Errorf("invalid list index %s", t.token)
+			}
+			list := append(c[:i:i], deepCopy(value))
+			t.set(append(list, c[i:]...))
+			return nil
+		}
+
+		indexes := listIndexes(c, t.token)
+		if len(indexes) == 0 {
+			return fmt.Errorf("no list item matches %s", t.token)
+		}
+		if op == opReplace {
+			for _, i := range indexes {
+				c[i] = deepCopy(value)
+			}
+			return nil
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+		for _, i := range indexes {
+			c = append(c[:i], c[i+1:]...)
+		}
+		t.set(c)
+		return nil
+	}
+	return fmt.Errorf("%s is not an object or list", t.token)
+}
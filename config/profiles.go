@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// applyProfiles returns a copy of doc with the named profiles applied in order
+func applyProfiles(doc map[string]any, names []string) (map[string]any, error) {
+	result := deepCopy(doc).(map[string]any)
+	definitions := profileList(doc)
+	for _, name := range names {
+		if err := applyProfile(result, definitions, name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// applyProfile applies a profile after its parents. chain holds the profiles
+// being applied to detect parent cycles.
+func applyProfile(doc map[string]any, definitions []map[string]any, name string, chain []string) error {
+	if slices.Contains(chain, name) {
+		return fmt.Errorf("profile parent cycle: %s", strings.Join(append(chain, name), " -> "))
+	}
+	profile := findProfile(definitions, name)
+	if profile == nil {
+		return fmt.Errorf("profile %q not found; available profiles: %s", name, strings.Join(profileNames(definitions), ", "))
+	}
+
+	for _, parent := range profileParents(profile) {
+		if err := applyProfile(doc, definitions, parent, append(chain, name)); err != nil {
+			return err
+		}
+	}
+
+	// Sections are replaced first, then merged and finally patched
+	if replace, ok := profile["replace"].(map[string]any); ok {
+		for key, value := range replace {
+			doc[key] = deepCopy(value)
+		}
+	}
+	if merge, ok := profile["merge"].(map[string]any); ok {
+		mergeInto(doc, merge)
+	}
+	patches, _ := profile["patches"].([]any)
+	for i, item := range patches {
+		p, ok := item.(map[string]any)
+		if !ok {
+			return &PatchError{Profile: name, Index: i, Err: fmt.Errorf("not an object")}
+		}
+		if err := applyPatch(doc, stringField(p, "op"), stringField(p, "path"), p["value"]); err != nil {
+			return &PatchError{Profile: name, Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// PatchError is returned when a profile patch cannot be applied
+type PatchError struct {
+	Profile string
+	// Index is the position of the patch in the profile's patches list
+	Index int
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return fmt.Sprintf("profile %s: patch %d: %v", e.Profile, e.Index+1, e.Err)
+}
+
+func (e *PatchError) Unwrap() error {
+	return e.Err
+}
+
+// findProfile returns the profile definition with the given name
+func findProfile(definitions []map[string]any, name string) map[string]any {
+	for _, p := range definitions {
+		if stringField(p, "name") == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// profileNames returns the names of the profile definitions
+func profileNames(definitions []map[string]any) []string {
+	names := make([]string, 0, len(definitions))
+	for _, p := range definitions {
+		names = append(names, stringField(p, "name"))
+	}
+	return names
+}
+
+// mergeInto merges src into dst. Maps are merged recursively, other values
+// including lists are replaced and null values remove the key.
+func mergeInto(dst, src map[string]any) {
+	for key, value := range src {
+		if value == nil {
+			delete(dst, key)
+			continue
+		}
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeInto(dstMap, srcMap)
+			continue
+		}
+		dst[key] = deepCopy(value)
+	}
+}
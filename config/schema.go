@@ -0,0 +1,387 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kind is the type of value a schema accepts
+type kind int
+
+const (
+	kindAny kind = iota
+	kindString
+	kindBool
+	kindObject
+	kindMap
+	kindList
+)
+
+// schema describes the values allowed at a place in the config
+type schema struct {
+	kind kind
+	// fields are the known fields of an object
+	fields map[string]*schema
+	// open objects allow fields that are not listed
+	open     bool
+	required []string
+	// items is the schema of list items and map values
+	items *schema
+	enum  []string
+	// alt is tried when the value does not have the YAML kind of this schema,
+	// e.g. for fields that accept a string or an object
+	alt *schema
+}
+
+var (
+	anyValue = &schema{kind: kindAny}
+	str      = &schema{kind: kindString}
+	boolean  = &schema{kind: kindBool}
+	strList  = listOf(str)
+	strMap   = mapOf(str)
+)
+
+func object(fields map[string]*schema, required ...string) *schema {
+	return &schema{kind: kindObject, fields: fields, required: required}
+}
+
+// openObject is an object whose known fields are checked while other fields
+// are accepted, for sections that are passed through to other tools
+func openObject(fields map[string]*schema) *schema {
+	return &schema{kind: kindObject, fields: fields, open: true}
+}
+
+func listOf(items *schema) *schema { return &schema{kind: kindList, items: items} }
+func mapOf(items *schema) *schema  { return &schema{kind: kindMap, items: items} }
+func enum(values ...string) *schema {
+	return &schema{kind: kindString, enum: values}
+}
+
+// or returns a copy of s that also accepts values of alt
+func or(s, alt *schema) *schema {
+	c := *s
+	c.alt = alt
+	return &c
+}
+
+// v2beta1 is the schema of a v2beta1 devspace config
+var v2beta1 = func() *schema {
+	variable := or(str, object(map[string]*schema{
+		"name":              str,
+		"value":             anyValue,
+		"default":           anyValue,
+		"question":          str,
+		"options":           strList,
+		"password":          boolean,
+		"validationPattern": str,
+		"validationMessage": str,
+		"noCache":           boolean,
+		"alwaysResolve":     boolean,
+		"source":            enum(SourceAll, SourceEnv, SourceInput, SourceCommand, SourceNone),
+		"command":           str,
+		"args":              strList,
+		"commands":          listOf(openObject(map[string]*schema{"command": str, "args": strList, "os": str})),
+	}))
+
+	image := object(map[string]*schema{
+		"name":                         str,
+		"image":                        str,
+		"tags":                         strList,
+		"dockerfile":                   str,
+		"context":                      str,
+		"buildArgs":                    mapOf(anyValue),
+		"target":                       str,
+		"network":                      str,
+		"rebuildStrategy":              enum("default", "always", "ignoreContextChanges"),
+		"skipPush":                     boolean,
+		"createPullSecret":             boolean,
+		"injectRestartHelper":          boolean,
+		"restartHelperPath":            str,
+		"appendDockerfileInstructions": strList,
+		"entrypoint":                   strList,
+		"cmd":                          strList,
+		"docker":                       anyValue,
+		"kaniko":                       anyValue,
+		"buildKit":                     anyValue,
+		"custom":                       anyValue,
+		"localRegistry":                anyValue,
+	}, "image")
+
+	deployment := object(map[string]*schema{
+		"name":            str,
+		"namespace":       str,
+		"updateImageTags": boolean,
+		"helm": openObject(map[string]*schema{
+			"releaseName":             str,
+			"chart":                   openObject(map[string]*schema{"name": str, "version": str, "repo": str, "path": str}),
+			"values":                  anyValue,
+			"valuesFiles":             strList,
+			"displayOutput":           boolean,
+			"upgradeArgs":             strList,
+			"templateArgs":            strList,
+			"disableDependencyUpdate": boolean,
+		}),
+		"kubectl": openObject(map[string]*schema{
+			"manifests":         strList,
+			"kustomize":         boolean,
+			"kustomizeArgs":     strList,
+			"createArgs":        strList,
+			"applyArgs":         strList,
+			"inlineManifest":    str,
+			"kubectlBinaryPath": str,
+			"patches":           listOf(anyValue),
+		}),
+	})
+
+	port := object(map[string]*schema{"port": str, "bindAddress": str}, "port")
+	sync := openObject(map[string]*schema{
+		"path":                 str,
+		"excludePaths":         strList,
+		"excludeFile":          str,
+		"downloadExcludePaths": strList,
+		"downloadExcludeFile":  str,
+		"uploadExcludePaths":   strList,
+		"uploadExcludeFile":    str,
+		"startContainer":       boolean,
+		"onUpload":             anyValue,
+		"initialSync":          enum("preferLocal", "preferRemote", "preferNewest", "keepAll", "mirrorLocal", "mirrorRemote", "disabled"),
+		"waitInitialSync":      boolean,
+		"initialSyncCompareBy": enum("mtime", "size"),
+		"disableDownload":      boolean,
+		"disableUpload":        boolean,
+		"noWatch":              boolean,
+		"polling":              boolean,
+		"printLogs":            boolean,
+	})
+	devContainer := openObject(map[string]*schema{
+		"container":  str,
+		"devImage":   str,
+		"command":    strList,
+		"args":       strList,
+		"workingDir": str,
+		"env":        listOf(object(map[string]*schema{"name": str, "value": str}, "name")),
+		"sync":       listOf(sync),
+	})
+	dev := openObject(map[string]*schema{
+		"imageSelector": str,
+		"labelSelector": strMap,
+		"namespace":     str,
+		"container":     str,
+		"devImage":      str,
+		"ports":         listOf(port),
+		"sync":          listOf(sync),
+		"command":       strList,
+		"args":          strList,
+		"workingDir":    str,
+		"env":           listOf(object(map[string]*schema{"name": str, "value": str}, "name")),
+		"containers":    mapOf(devContainer),
+		"open":          listOf(anyValue),
+		"patches":       listOf(anyValue),
+	})
+
+	patch := object(map[string]*schema{
+		"op":    enum(opAdd, opReplace, opRemove),
+		"path":  str,
+		"value": anyValue,
+	}, "op", "path")
+	profile := object(map[string]*schema{
+		"name":        str,
+		"description": str,
+		"parent":      str,
+		"parents":     listOf(or(str, openObject(map[string]*schema{"profile": str}))),
+		"activation":  listOf(anyValue),
+		"patches":     listOf(patch),
+		"merge":       mapOf(anyValue),
+		"replace":     mapOf(anyValue),
+	}, "name")
+
+	return object(map[string]*schema{
+		"version":       str,
+		"name":          str,
+		"imports":       listOf(openObject(map[string]*schema{"path": str, "git": str, "branch": str, "tag": str, "revision": str, "subPath": str, "enabled": boolean})),
+		"vars":          or(mapOf(variable), listOf(variable)),
+		"localRegistry": anyValue,
+		"images":        mapOf(image),
+		"deployments":   mapOf(deployment),
+		"dev":           mapOf(dev),
+		"pipelines":     mapOf(or(str, object(map[string]*schema{"name": str, "run": str, "flags": listOf(anyValue), "continueOnError": boolean}))),
+		"commands":      mapOf(or(str, object(map[string]*schema{"name": str, "command": str, "args": strList, "appendArgs": boolean, "description": str, "internal": boolean, "after": str}))),
+		"functions":     strMap,
+		"dependencies": mapOf(openObject(map[string]*schema{
+			"name": str, "path": str, "git": str, "branch": str, "tag": str, "revision": str, "subPath": str,
+			"pipeline": str, "vars": mapOf(anyValue), "overwriteVars": boolean, "ignoreDependencies": boolean, "namespace": str,
+		})),
+		"hooks":       listOf(anyValue),
+		"pullSecrets": mapOf(anyValue),
+		"require":     object(map[string]*schema{"devspace": str, "commands": listOf(anyValue), "plugins": listOf(anyValue)}),
+		"profiles":    listOf(profile),
+	}, "version", "name")
+}()
+
+// validate checks a YAML node against the schema and adds the problems found
+// to issues
+func (s *schema) validate(n *yaml.Node, path []string, issues *[]Issue) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if s.kind == kindAny || isNull(n) {
+		return
+	}
+	if !s.accepts(n) {
+		if s.alt != nil {
+			s.alt.validate(n, path, issues)
+			return
+		}
+		*issues = append(*issues, schemaIssue(n, path, "expected %s, got %s", s.describe(), describeNode(n)))
+		return
+	}
+
+	switch s.kind {
+	case kindString:
+		if len(s.enum) > 0 && !slices.Contains(s.enum, n.Value) && !strings.Contains(n.Value, "${") {
+			*issues = append(*issues, schemaIssue(n, path, "invalid value %q: must be one of %s", n.Value, strings.Join(s.enum, ", ")))
+		}
+	case kindObject:
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" {
+				continue
+			}
+			seen[key.Value] = true
+			field, ok := s.fields[key.Value]
+			if !ok {
+				if !s.open {
+					*issues = append(*issues, schemaIssue(key, path, "unknown field %q%s", key.Value, suggestion(key.Value, s.fields)))
+				}
+				continue
+			}
+			field.validate(value, append(path, key.Value), issues)
+		}
+		for _, name := range s.required {
+			if !seen[name] {
+				*issues = append(*issues, schemaIssue(n, path, "missing required field %q", name))
+			}
+		}
+	case kindMap:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			s.items.validate(n.Content[i+1], append(path, n.Content[i].Value), issues)
+		}
+	case kindList:
+		for i, item := range n.Content {
+			s.items.validate(item, append(path, fmt.Sprint(i)), issues)
+		}
+	}
+}
+
+// accepts reports whether the node has the YAML kind of the schema. Strings
+// with variables are accepted for every scalar type.
+func (s *schema) accepts(n *yaml.Node) bool {
+	switch s.kind {
+	case kindObject, kindMap:
+		return n.Kind == yaml.MappingNode
+	case kindList:
+		return n.Kind == yaml.SequenceNode
+	}
+	if n.Kind != yaml.ScalarNode {
+		return false
+	}
+	switch s.kind {
+	case kindBool:
+		return n.Tag == "!!bool" || strings.Contains(n.Value, "${")
+	}
+	return true
+}
+
+// describe names the values a schema accepts
+func (s *schema) describe() string {
+	names := map[kind]string{
+		kindString: "a string",
+		kindBool:   "a boolean",
+		kindObject: "an object",
+		kindMap:    "an object",
+		kindList:   "a list",
+	}
+	if s.alt != nil && names[s.alt.kind] != names[s.kind] {
+		return names[s.kind] + " or " + s.alt.describe()
+	}
+	return names[s.kind]
+}
+
+// describeNode names the kind of value a node holds
+func describeNode(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch n.Tag {
+	case "!!bool":
+		return "a boolean"
+	case "!!int", "!!float":
+		return "a number"
+	}
+	return "a string"
+}
+
+// isNull reports whether a node is an empty value, which devspace ignores
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+}
+
+// schemaIssue returns a schema error at the position of a node
+func schemaIssue(n *yaml.Node, path []string, format string, args ...any) Issue {
+	return Issue{
+		Severity: SeverityError,
+		Rule:     RuleSchema,
+		Path:     formatPath(path),
+		Line:     n.Line,
+		Column:   n.Column,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// suggestion proposes the known field closest to an unknown one
+func suggestion(name string, fields map[string]*schema) string {
+	known := make([]string, 0, len(fields))
+	for field := range fields {
+		known = append(known, field)
+	}
+	sort.Strings(known)
+	best, bestDistance := "", 3
+	for _, field := range known {
+		if d := distance(strings.ToLower(name), strings.ToLower(field)); d < bestDistance {
+			best, bestDistance = field, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean %q?", best)
+}
+
+// distance returns the Levenshtein distance of two strings
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
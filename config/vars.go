@@ -0,0 +1,316 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Variable sources
+const (
+	SourceAll     = "all"
+	SourceEnv     = "env"
+	SourceInput   = "input"
+	SourceCommand = "command"
+	SourceNone    = "none"
+)
+
+// varPattern matches ${NAME} and $!{NAME}, which keeps the value a string
+var varPattern = regexp.MustCompile(`\$(!?)\{([^{}]+)\}`)
+
+// runtimePrefix marks variables only known while devspace runs
+const runtimePrefix = "runtime."
+
+// unsubstitutedSections are executed by devspace's shell, which resolves
+// variables itself when they run
+var unsubstitutedSections = map[string]bool{
+	"vars":      true,
+	"profiles":  true,
+	"pipelines": true,
+	"commands":  true,
+	"functions": true,
+}
+
+// Options control how a config is resolved
+type Options struct {
+	// Profiles are applied in order
+	Profiles []string
+	// Vars override variable values, like devspace --var
+	Vars map[string]string
+	// LookupEnv reads environment variables; os.LookupEnv by default
+	LookupEnv func(string) (string, bool)
+	// Cache holds the values devspace remembers for input variables
+	Cache map[string]string
+	// Namespace and KubeContext are used for DEVSPACE_NAMESPACE and
+	// DEVSPACE_CONTEXT. They are runtime values when empty.
+	Namespace   string
+	KubeContext string
+}
+
+// ResolvedVar is the value a variable resolved to
+type ResolvedVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Source is where the value came from: override, value, env, cache,
+	// default or predefined
+	Source string `json:"source,omitempty"`
+	// Unresolved explains why there is no value, e.g. that the variable is
+	// set by a command
+	Unresolved string `json:"unresolved,omitempty"`
+	Password   bool   `json:"password,omitempty"`
+}
+
+// Resolved is a config with profiles applied and variables substituted
+type Resolved struct {
+	Config map[string]any
+	// Vars are all defined and referenced variables, sorted by name
+	Vars []ResolvedVar
+}
+
+// Resolve applies the profiles of opts and substitutes variables
+func (c *Config) Resolve(opts Options) (*Resolved, error) {
+	doc, err := applyProfiles(c.Doc, opts.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+
+	r := &resolver{
+		defs:      make(map[string]Var),
+		opts:      opts,
+		vars:      make(map[string]*ResolvedVar),
+		resolving: make(map[string]bool),
+		predefined: map[string]string{
+			"DEVSPACE_NAME":      c.Name(),
+			"DEVSPACE_PROFILE":   lastOrEmpty(opts.Profiles),
+			"DEVSPACE_PROFILES":  strings.Join(opts.Profiles, " "),
+			"DEVSPACE_NAMESPACE": opts.Namespace,
+			"DEVSPACE_CONTEXT":   opts.KubeContext,
+		},
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		r.predefined["DEVSPACE_USER_HOME"] = home
+	}
+	definitions := varDefinitions(doc)
+	for _, v := range definitions {
+		r.defs[v.Name] = v
+	}
+	for _, v := range definitions {
+		r.resolve(v.Name)
+	}
+
+	for key, value := range doc {
+		if !unsubstitutedSections[key] {
+			doc[key] = r.substitute(value)
+		}
+	}
+
+	resolved := &Resolved{Config: doc}
+	for _, name := range sortedVarNames(r.vars) {
+		if _, predefined := r.predefined[name]; predefined && r.defs[name].Name == "" {
+			continue
+		}
+		resolved.Vars = append(resolved.Vars, *r.vars[name])
+	}
+	return resolved, nil
+}
+
+// resolver resolves variables on demand
+type resolver struct {
+	defs       map[string]Var
+	opts       Options
+	predefined map[string]string
+	vars       map[string]*ResolvedVar
+	resolving  map[string]bool
+}
+
+// resolve returns the resolved variable name, resolving it first if needed
+func (r *resolver) resolve(name string) *ResolvedVar {
+	if v, ok := r.vars[name]; ok {
+		return v
+	}
+	v := &ResolvedVar{Name: name}
+	if r.resolving[name] {
+		v.Unresolved = "variable references itself"
+		return v
+	}
+	r.resolving[name] = true
+	defer delete(r.resolving, name)
+
+	def, defined := r.defs[name]
+	v.Password = def.Password
+	set := func(value any, source string) {
+		v.Value, v.Source = r.expand(fmt.Sprint(value)), source
+	}
+
+	if value, ok := r.opts.Vars[name]; ok {
+		set(value, "override")
+	} else if value, ok := r.predefined[name]; ok && !defined {
+		if value == "" {
+			v.Unresolved = "set by devspace at runtime"
+		} else {
+			set(value, "predefined")
+		}
+	} else if def.Value != nil {
+		set(def.Value, "value")
+	} else if def.Source == SourceCommand {
+		v.Unresolved = "set by a command"
+	} else {
+		source := def.Source
+		if source == "" {
+			source = SourceAll
+		}
+		if value, ok := r.opts.LookupEnv(name); ok && (source == SourceAll || source == SourceEnv) {
+			set(value, "env")
+		} else if value, ok := r.opts.Cache[name]; ok && (source == SourceAll || source == SourceInput) {
+			set(value, "cache")
+		} else if def.Default != nil {
+			set(def.Default, "default")
+		} else if source == SourceEnv || source == SourceNone {
+			set("", "default")
+		} else {
+			v.Unresolved = "asked for on first use"
+		}
+	}
+
+	r.vars[name] = v
+	return v
+}
+
+// expand substitutes variables in a string, keeping unresolved references
+func (r *resolver) expand(s string) string {
+	return varPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := strings.TrimSpace(varPattern.FindStringSubmatch(match)[2])
+		if strings.HasPrefix(name, runtimePrefix) {
+			return match
+		}
+		if v := r.resolve(name); v.Unresolved == "" {
+			return v.Value
+		}
+		return match
+	})
+}
+
+// substitute replaces variables in a decoded YAML value. A string that only
+// consists of ${NAME} takes the type of the value, e.g. a number.
+func (r *resolver) substitute(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = r.substitute(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = r.substitute(item)
+		}
+		return v
+	case string:
+		m := varPattern.FindStringSubmatchIndex(v)
+		whole := m != nil && m[0] == 0 && m[1] == len(v) && v[m[2]:m[3]] == ""
+		expanded := r.expand(v)
+		if whole && expanded != v {
+			return convert(expanded)
+		}
+		return expanded
+	default:
+		return value
+	}
+}
+
+// convert turns a variable value into a bool or number where possible
+func convert(s string) any {
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+// varDefinitions reads the vars section of a document. Vars may be a map of
+// names to values or definitions, or a list of definitions with a name.
+func varDefinitions(doc map[string]any) []Var {
+	var vars []Var
+	add := func(name string, item any) {
+		v := Var{Name: name, Source: SourceAll}
+		def, ok := item.(map[string]any)
+		if !ok {
+			v.Value = item
+			vars = append(vars, v)
+			return
+		}
+		v.Value = def["value"]
+		v.Default = def["default"]
+		v.Password, _ = def["password"].(bool)
+		if source := stringField(def, "source"); source != "" {
+			v.Source = source
+		}
+		v.Command = stringField(def, "command")
+		if v.Command != "" || def["commands"] != nil {
+			v.Source = SourceCommand
+		}
+		vars = append(vars, v)
+	}
+
+	switch section := doc["vars"].(type) {
+	case map[string]any:
+		for _, name := range sortedKeys(section) {
+			add(name, section[name])
+		}
+	case []any:
+		for _, item := range section {
+			if def, ok := item.(map[string]any); ok && stringField(def, "name") != "" {
+				add(stringField(def, "name"), def)
+			}
+		}
+		sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	}
+	return vars
+}
+
+// CachedVars returns the variable values devspace remembers in
+// .devspace/cache.yaml of a project directory
+func CachedVars(dir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(dir, ".devspace", "cache.yaml"))
+	if err != nil {
+		return nil
+	}
+	var cache struct {
+		Vars map[string]string `yaml:"vars"`
+	}
+	if err := yaml.Unmarshal(data, &cache); err != nil {
+		return nil
+	}
+	return cache.Vars
+}
+
+// sortedVarNames returns the names of the resolved variables in order
+func sortedVarNames(vars map[string]*ResolvedVar) []string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lastOrEmpty returns the last element of list or ""
+func lastOrEmpty(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return list[len(list)-1]
+}
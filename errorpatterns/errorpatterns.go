@@ -0,0 +1,357 @@
+// Package errorpatterns classifies the error output of devspace, kubectl and
+// related CLIs. Patterns are read from YAML pattern packs: the built-in pack
+// embedded in the binary, followed by the *.yaml files of user directories.
+// A user pattern with the code of an earlier pattern replaces it.
+//
+// Example pattern pack:
+//
+//	patterns:
+//	  - code: VAULT_INJECTOR_DENIED
+//	    category: permission
+//	    severity: error
+//	    regex: 'permission denied.*role "(?P<role>[^"]+)"'
+//	    tools: [deploy, dev_start]
+//	    suggestions:
+//	      - "Vault denied the Kubernetes auth role {{role}}. Check its bound service accounts."
+//	    remediation: "vault read auth/kubernetes/role/{{role}}"
+//
+// A pattern matches when the error output contains pattern and matches
+// regex, for those that are set, and the exit code is one of exit_codes, if
+// any. Tools restrict a pattern to tools by name, with or without the
+// "devspace_" prefix; '*' wildcards are allowed. Suggestions and the
+// remediation command may refer to regex groups as {{1}} or {{name}}, and to
+// the whole match as {{0}}.
+package errorpatterns
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of a classified error
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+)
+
+// BuiltinSource is the source of the patterns embedded in the binary
+const BuiltinSource = "built-in"
+
+// toolPrefix is the common prefix of all tool names
+const toolPrefix = "devspace_"
+
+// exitCodeScore is added to the score of a pattern whose exit code
+// condition holds
+const exitCodeScore = 5
+
+// maxSuggestions caps the suggestions of a classified error
+const maxSuggestions = 4
+
+//go:embed builtin.yaml
+var builtinPack []byte
+
+// placeholderRegex finds {{group}} references in templates
+var placeholderRegex = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// Pattern is a known error and how to resolve it
+type Pattern struct {
+	Code        string   `yaml:"code" json:"code"`
+	Category    string   `yaml:"category" json:"category"`
+	Severity    string   `yaml:"severity" json:"severity" jsonschema:"description=critical or error or warning"`
+	Pattern     string   `yaml:"pattern" json:"pattern,omitempty" jsonschema:"description=Case-insensitive text the error output must contain"`
+	Regex       string   `yaml:"regex" json:"regex,omitempty" jsonschema:"description=Regular expression the error output must match"`
+	ExitCodes   []int    `yaml:"exit_codes" json:"exit_codes,omitempty"`
+	Tools       []string `yaml:"tools" json:"tools,omitempty" jsonschema:"description=Tools the pattern applies to; all tools if empty"`
+	Suggestions []string `yaml:"suggestions" json:"suggestions"`
+	Remediation string   `yaml:"remediation" json:"remediation,omitempty" jsonschema:"description=Command that may resolve the error"`
+	Docs        string   `yaml:"docs" json:"docs,omitempty"`
+	// Priority is added to the score of a match, so that a root cause wins
+	// over a generic symptom reported in the same output
+	Priority int `yaml:"priority" json:"priority,omitempty"`
+	// Source is the file the pattern was loaded from, or BuiltinSource
+	Source string `yaml:"-" json:"source"`
+
+	regex *regexp.Regexp
+}
+
+// Classification is the structured description of a failed command
+type Classification struct {
+	Code        string   `json:"code"`
+	Category    string   `json:"category"`
+	Severity    string   `json:"severity"`
+	Suggestions []string `json:"suggestions"`
+	Remediation string   `json:"remediation,omitempty"`
+	Docs        string   `json:"docs,omitempty"`
+}
+
+// Set is an ordered list of patterns. Ties between equally good matches are
+// won by the earlier pattern.
+type Set struct {
+	patterns []Pattern
+	dirs     []string
+}
+
+// pack is the document format of a pattern file
+type pack struct {
+	Patterns []Pattern `yaml:"patterns"`
+}
+
+// Builtin returns the patterns embedded in the binary
+func Builtin() *Set {
+	patterns, err := Parse(builtinPack, BuiltinSource)
+	if err != nil {
+		panic("invalid built-in error patterns: " + err.Error())
+	}
+	return &Set{patterns: patterns}
+}
+
+// Load returns the built-in patterns followed by the *.yaml and *.yml
+// files of dirs, each directory in the given order and its files sorted by
+// name
+func Load(dirs ...string) (*Set, error) {
+	s := Builtin()
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read error pattern directory: %w", err)
+		}
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			file := filepath.Join(dir, entry.Name())
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read error pattern file: %w", err)
+			}
+			patterns, err := Parse(data, file)
+			if err != nil {
+				return nil, fmt.Errorf("invalid error pattern file %s: %w", file, err)
+			}
+			s.add(patterns)
+		}
+		s.dirs = append(s.dirs, dir)
+	}
+	return s, nil
+}
+
+// Parse decodes and validates a pattern pack. source is recorded in every
+// pattern.
+func Parse(data []byte, source string) ([]Pattern, error) {
+	var p pack
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	codes := make(map[string]bool)
+	for i := range p.Patterns {
+		pattern := &p.Patterns[i]
+		if err := pattern.compile(); err != nil {
+			if pattern.Code == "" {
+				return nil, fmt.Errorf("pattern %d: %w", i+1, err)
+			}
+			return nil, fmt.Errorf("pattern %s: %w", pattern.Code, err)
+		}
+		if codes[pattern.Code] {
+			return nil, fmt.Errorf("duplicate pattern code %q", pattern.Code)
+		}
+		codes[pattern.Code] = true
+		pattern.Source = source
+	}
+	return p.Patterns, nil
+}
+
+// compile validates the pattern, fills in defaults and compiles its regex
+func (p *Pattern) compile() error {
+	if p.Code == "" {
+		return fmt.Errorf("missing code")
+	}
+	if p.Pattern == "" && p.Regex == "" && len(p.ExitCodes) == 0 {
+		return fmt.Errorf("needs a pattern, regex or exit_codes")
+	}
+	if len(p.Suggestions) == 0 {
+		return fmt.Errorf("needs at least one suggestion")
+	}
+	if p.Category == "" {
+		p.Category = "other"
+	}
+	switch p.Severity {
+	case "":
+		p.Severity = SeverityError
+	case SeverityCritical, SeverityError, SeverityWarning:
+	default:
+		return fmt.Errorf("invalid severity %q: must be critical, error or warning", p.Severity)
+	}
+
+	groups := map[string]bool{"0": true}
+	if p.Regex != "" {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+		p.regex = re
+		for i, name := range re.SubexpNames() {
+			groups[strconv.Itoa(i)] = true
+			if name != "" {
+				groups[name] = true
+			}
+		}
+	}
+	for _, template := range append(slices.Clone(p.Suggestions), p.Remediation) {
+		for _, m := range placeholderRegex.FindAllStringSubmatch(template, -1) {
+			if p.regex == nil {
+				return fmt.Errorf("%s refers to a regex group but the pattern has no regex", m[0])
+			}
+			if !groups[m[1]] {
+				return fmt.Errorf("%s refers to an unknown regex group", m[0])
+			}
+		}
+	}
+	return nil
+}
+
+// add appends patterns, replacing earlier patterns with the same code in
+// place
+func (s *Set) add(patterns []Pattern) {
+	for _, p := range patterns {
+		i := slices.IndexFunc(s.patterns, func(existing Pattern) bool { return existing.Code == p.Code })
+		if i >= 0 {
+			s.patterns[i] = p
+		} else {
+			s.patterns = append(s.patterns, p)
+		}
+	}
+}
+
+// Patterns returns the active patterns in order
+func (s *Set) Patterns() []Pattern {
+	return slices.Clone(s.patterns)
+}
+
+// Dirs returns the user directories the patterns were loaded from
+func (s *Set) Dirs() []string {
+	return slices.Clone(s.dirs)
+}
+
+// AppliesTo reports whether the pattern is used for errors of tool. An
+// empty tool matches every pattern.
+func (p Pattern) AppliesTo(tool string) bool {
+	if len(p.Tools) == 0 || tool == "" {
+		return true
+	}
+	for _, t := range p.Tools {
+		if !strings.HasPrefix(t, toolPrefix) && t != "*" {
+			t = toolPrefix + t
+		}
+		if ok, _ := path.Match(t, tool); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns how well the pattern matches the error output, or 0 if it
+// does not match, together with the regex submatches. Longer matches are
+// more specific and score higher.
+func (p Pattern) match(text string, exitCode int) (int, []string) {
+	score := p.Priority
+	if p.Pattern != "" {
+		if !strings.Contains(strings.ToLower(text), strings.ToLower(p.Pattern)) {
+			return 0, nil
+		}
+		score += len(p.Pattern)
+	}
+	var groups []string
+	if p.regex != nil {
+		groups = p.regex.FindStringSubmatch(text)
+		if groups == nil || groups[0] == "" {
+			return 0, nil
+		}
+		score += len(groups[0])
+	}
+	if len(p.ExitCodes) > 0 {
+		if !slices.Contains(p.ExitCodes, exitCode) {
+			return 0, nil
+		}
+		score += exitCodeScore
+	}
+	return max(score, 1), groups
+}
+
+// expand fills the {{group}} placeholders of a template
+func (p Pattern) expand(template string, groups []string) string {
+	return placeholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+		i, err := strconv.Atoi(name)
+		if err != nil && p.regex != nil {
+			i = p.regex.SubexpIndex(name)
+		}
+		if i < 0 || i >= len(groups) {
+			return ""
+		}
+		return groups[i]
+	})
+}
+
+// Classify scores the patterns that apply to tool against the error output.
+// The best match determines the code and remediation; the first suggestion
+// of other matches follows its own. It returns nil when nothing matches.
+func (s *Set) Classify(text string, exitCode int, tool string) *Classification {
+	type match struct {
+		pattern Pattern
+		score   int
+		groups  []string
+	}
+	var matches []match
+	for _, p := range s.patterns {
+		if !p.AppliesTo(tool) {
+			continue
+		}
+		if score, groups := p.match(text, exitCode); score > 0 {
+			matches = append(matches, match{p, score, groups})
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	best := matches[0]
+	c := &Classification{
+		Code:        best.pattern.Code,
+		Category:    best.pattern.Category,
+		Severity:    best.pattern.Severity,
+		Remediation: best.pattern.expand(best.pattern.Remediation, best.groups),
+		Docs:        best.pattern.Docs,
+	}
+	for i, m := range matches {
+		suggestions := m.pattern.Suggestions
+		if i > 0 {
+			suggestions = suggestions[:1]
+		}
+		for _, template := range suggestions {
+			suggestion := m.pattern.expand(template, m.groups)
+			if len(c.Suggestions) < maxSuggestions && !slices.Contains(c.Suggestions, suggestion) {
+				c.Suggestions = append(c.Suggestions, suggestion)
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,169 @@
+package errorpatterns
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const vaultPack = `
+patterns:
+  - code: VAULT_INJECTOR_DENIED
+    category: permission
+    severity: critical
+    regex: 'vault-agent-init.*permission denied.*role "(?P<role>[^"]+)"'
+    tools: [deploy, dev_start]
+    suggestions:
+      - "Vault denied the Kubernetes auth role {{role}}. Check its bound service accounts."
+    remediation: "vault read auth/kubernetes/role/{{role}}"
+  - code: RBAC_FORBIDDEN
+    category: permission
+    pattern: forbidden
+    suggestions:
+      - Ask #platform for access to the namespace.
+`
+
+func TestBuiltin(t *testing.T) {
+	patterns := Builtin().Patterns()
+	if len(patterns) == 0 {
+		t.Fatal("no built-in patterns")
+	}
+	for _, p := range patterns {
+		if p.Source != BuiltinSource {
+			t.Errorf("%s: source = %s", p.Code, p.Source)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{"missing code", "patterns:\n  - pattern: a\n    suggestions: [b]\n", "pattern 1: missing code"},
+		{"no condition", "patterns:\n  - code: A\n    suggestions: [b]\n", "needs a pattern, regex or exit_codes"},
+		{"no suggestion", "patterns:\n  - code: A\n    pattern: a\n", "at least one suggestion"},
+		{"bad regex", "patterns:\n  - code: A\n    regex: '('\n    suggestions: [b]\n", "invalid regex"},
+		{"bad severity", "patterns:\n  - code: A\n    pattern: a\n    severity: fatal\n    suggestions: [b]\n", "invalid severity"},
+		{"duplicate", "patterns:\n  - code: A\n    pattern: a\n    suggestions: [b]\n  - code: A\n    pattern: c\n    suggestions: [d]\n", "duplicate"},
+		{"unknown group", "patterns:\n  - code: A\n    regex: 'user (\\w+)'\n    suggestions: ['{{name}}']\n", "{{name}} refers to an unknown regex group"},
+		{"group without regex", "patterns:\n  - code: A\n    pattern: a\n    remediation: 'echo {{1}}'\n    suggestions: [b]\n", "has no regex"},
+		{"unknown field", "patterns:\n  - code: A\n    pattern: a\n    suggestion: b\n", "suggestion"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc), "test.yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "org.yaml"), []byte(vaultPack), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a pack"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtin := Builtin().Patterns()
+	patterns := s.Patterns()
+	if len(patterns) != len(builtin)+1 {
+		t.Fatalf("got %d patterns, want %d", len(patterns), len(builtin)+1)
+	}
+	for i, p := range builtin {
+		if p.Code == "RBAC_FORBIDDEN" && (patterns[i].Code != "RBAC_FORBIDDEN" || patterns[i].Source != filepath.Join(dir, "org.yaml")) {
+			t.Errorf("RBAC_FORBIDDEN should be replaced in place, got %+v", patterns[i])
+		}
+	}
+	if last := patterns[len(patterns)-1]; last.Code != "VAULT_INJECTOR_DENIED" || last.Severity != SeverityCritical {
+		t.Errorf("unexpected appended pattern: %+v", last)
+	}
+	if dirs := s.Dirs(); len(dirs) != 1 || dirs[0] != dir {
+		t.Errorf("dirs = %v", dirs)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.yml"), []byte("patterns:\n  - code: A\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "broken.yml") {
+		t.Errorf("error should name the broken file, got %v", err)
+	}
+}
+
+func TestClassifyInterpolatesGroups(t *testing.T) {
+	patterns, err := Parse([]byte(vaultPack), "org.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Set{patterns: patterns}
+	output := `Error: pod api-0 init container vault-agent-init failed: permission denied: role "api-prod" is not bound to service account "api"`
+
+	c := s.Classify(output, 1, "devspace_deploy")
+	if c == nil || c.Code != "VAULT_INJECTOR_DENIED" {
+		t.Fatalf("unexpected classification: %+v", c)
+	}
+	if c.Suggestions[0] != "Vault denied the Kubernetes auth role api-prod. Check its bound service accounts." {
+		t.Errorf("suggestion = %q", c.Suggestions[0])
+	}
+	if c.Remediation != "vault read auth/kubernetes/role/api-prod" {
+		t.Errorf("remediation = %q", c.Remediation)
+	}
+
+	if c := s.Classify(output, 1, "devspace_logs"); c != nil {
+		t.Errorf("pattern should not apply to devspace_logs, got %+v", c)
+	}
+	if c := s.Classify(output, 1, ""); c == nil {
+		t.Error("patterns should apply when the tool is unknown")
+	}
+}
+
+func TestClassifyScoresAllPatterns(t *testing.T) {
+	s := Builtin()
+	tests := []struct {
+		output   string
+		exitCode int
+		want     string
+	}{
+		{"Cannot find a devspace.yaml in /work", 1, "DEVSPACE_CONFIG_MISSING"},
+		{"error parsing devspace.yaml: line 3", 1, "DEVSPACE_CONFIG_ERROR"},
+		{`namespaces "dev" not found`, 1, "NAMESPACE_NOT_FOUND"},
+		{`pods "api" not found`, 1, "RESOURCE_NOT_FOUND"},
+		{"", 137, "PROCESS_KILLED"},
+		{"", 1, ""},
+	}
+	for _, tt := range tests {
+		c := s.Classify(tt.output, tt.exitCode, "devspace_deploy")
+		got := ""
+		if c != nil {
+			got = c.Code
+		}
+		if got != tt.want {
+			t.Errorf("Classify(%q, %d) = %q, want %q", tt.output, tt.exitCode, got, tt.want)
+		}
+	}
+}
+
+func TestAppliesTo(t *testing.T) {
+	p := Pattern{Tools: []string{"deploy", "devspace_dev_*"}}
+	for tool, want := range map[string]bool{
+		"devspace_deploy":    true,
+		"devspace_dev_start": true,
+		"devspace_build":     false,
+		"":                   true,
+	} {
+		if got := p.AppliesTo(tool); got != want {
+			t.Errorf("AppliesTo(%q) = %v, want %v", tool, got, want)
+		}
+	}
+}
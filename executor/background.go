@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// DefaultOutputLines is the number of output lines kept for a background process
+const DefaultOutputLines = 2000
+
+// DefaultStopGrace is how long a background process may take to exit after
+// being asked to terminate before it is killed
+const DefaultStopGrace = 5 * time.Second
+
+// Background is a supervised long-running child process. Its output is kept
+// in a bounded RingBuffer and it runs in its own process group so that
+// stopping it also stops any processes it spawned.
+type Background struct {
+	cmd       *exec.Cmd
+	output    *RingBuffer
+	startedAt time.Time
+	done      chan struct{}
+
+	mu       sync.Mutex
+	exitCode int
+	err      error
+	stopping bool
+}
+
+// StartBackground starts cmd without waiting for it to finish. Timeout is
+// ignored; the process runs until it exits or Stop is called. OnLine, if set,
+// is called for every output line in addition to it being buffered.
+func StartBackground(c Command, outputLines int) (*Background, error) {
+	if outputLines <= 0 {
+		outputLines = DefaultOutputLines
+	}
+
+	cmd := exec.Command(resolveBinary(c.Binary), c.Args...)
+	if c.Dir != "" {
+		cmd.Dir = c.Dir
+	}
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	setProcessGroup(cmd)
+	// Do not hang on pipes held open by grandchildren that outlive the child
+	cmd.WaitDelay = DefaultStopGrace
+
+	b := &Background{
+		cmd:    cmd,
+		output: NewRingBuffer(outputLines),
+		done:   make(chan struct{}),
+	}
+
+	var mu sync.Mutex
+	emit := func(stream Stream, line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		b.output.Append(stream, line)
+		if c.OnLine != nil {
+			c.OnLine(stream, line)
+		}
+	}
+	outLines := &lineWriter{stream: Stdout, emit: emit}
+	errLines := &lineWriter{stream: Stderr, emit: emit}
+	cmd.Stdout = outLines
+	cmd.Stderr = errLines
+	cmd.Stdin = nil
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	b.startedAt = time.Now()
+
+	go func() {
+		err := cmd.Wait()
+		outLines.Flush()
+		errLines.Flush()
+
+		b.mu.Lock()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			b.exitCode = exitErr.ExitCode()
+		} else if err != nil {
+			b.exitCode = -1
+			b.err = err
+		}
+		b.mu.Unlock()
+		close(b.done)
+	}()
+
+	return b, nil
+}
+
+// Pid returns the process ID of the child
+func (b *Background) Pid() int {
+	return b.cmd.Process.Pid
+}
+
+// StartedAt returns when the process was started
+func (b *Background) StartedAt() time.Time {
+	return b.startedAt
+}
+
+// Output returns the buffer holding the process output
+func (b *Background) Output() *RingBuffer {
+	return b.output
+}
+
+// Done is closed once the process has exited
+func (b *Background) Done() <-chan struct{} {
+	return b.done
+}
+
+// Running reports whether the process has not exited yet
+func (b *Background) Running() bool {
+	select {
+	case <-b.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// ExitCode returns the exit code once the process has exited. A process
+// killed by a signal reports -1.
+func (b *Background) ExitCode() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exitCode
+}
+
+// Err returns an error if waiting for the process failed for a reason other
+// than a non-zero exit
+func (b *Background) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err
+}
+
+// Stopped reports whether Stop was called
+func (b *Background) Stopped() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stopping
+}
+
+// Stop terminates the process group, escalating to a kill if it has not
+// exited after grace. It blocks until the process is gone.
+func (b *Background) Stop(grace time.Duration) error {
+	b.mu.Lock()
+	b.stopping = true
+	b.mu.Unlock()
+
+	if !b.Running() {
+		return nil
+	}
+
+	if err := terminateProcessGroup(b.cmd); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+
+	select {
+	case <-b.done:
+		return nil
+	case <-time.After(grace):
+	}
+
+	if err := killProcessGroup(b.cmd); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	<-b.done
+	return nil
+}
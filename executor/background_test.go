@@ -0,0 +1,131 @@
+//go:build !windows
+
+package executor
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer(t *testing.T) {
+	b := NewRingBuffer(3)
+	for _, l := range []string{"a", "b", "c", "d", "e"} {
+		b.Append(Stdout, l)
+	}
+
+	lines, next, dropped := b.ReadFrom(0, 0)
+	if dropped != 2 {
+		t.Errorf("dropped = %d, want 2", dropped)
+	}
+	if next != 5 {
+		t.Errorf("next = %d, want 5", next)
+	}
+	if got := joinLines(lines); got != "c,d,e" {
+		t.Errorf("lines = %s, want c,d,e", got)
+	}
+
+	lines, next, dropped = b.ReadFrom(3, 1)
+	if got := joinLines(lines); got != "d" || next != 4 || dropped != 0 {
+		t.Errorf("ReadFrom(3, 1) = %s, %d, %d; want d, 4, 0", got, next, dropped)
+	}
+
+	lines, next, _ = b.ReadFrom(5, 0)
+	if len(lines) != 0 || next != 5 {
+		t.Errorf("reading at the end should return nothing, got %v next=%d", lines, next)
+	}
+
+	if got := joinLines(b.Tail(2)); got != "d,e" {
+		t.Errorf("Tail(2) = %s, want d,e", got)
+	}
+	if got := joinLines(b.Tail(10)); got != "c,d,e" {
+		t.Errorf("Tail(10) = %s, want c,d,e", got)
+	}
+}
+
+func joinLines(lines []Line) string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.Text
+	}
+	return strings.Join(texts, ",")
+}
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestStartBackground_Exit(t *testing.T) {
+	requireShell(t)
+
+	b, err := StartBackground(Command{Binary: "sh", Args: []string{"-c", "echo hello; echo oops >&2; exit 3"}}, 10)
+	if err != nil {
+		t.Fatalf("StartBackground() error = %v", err)
+	}
+
+	select {
+	case <-b.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("process did not exit")
+	}
+
+	if b.Running() {
+		t.Error("Running() should be false after exit")
+	}
+	if b.ExitCode() != 3 {
+		t.Errorf("ExitCode() = %d, want 3", b.ExitCode())
+	}
+	lines, _, _ := b.Output().ReadFrom(0, 0)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 buffered lines, got %v", lines)
+	}
+	for _, l := range lines {
+		if l.Text == "oops" && l.Stream != Stderr {
+			t.Errorf("stderr line recorded on %s", l.Stream)
+		}
+	}
+}
+
+func TestStartBackground_StopKillsProcessGroup(t *testing.T) {
+	requireShell(t)
+
+	// The shell starts a grandchild and reports its PID, then waits
+	b, err := StartBackground(Command{Binary: "sh", Args: []string{"-c", "sleep 60 & echo $!; wait"}}, 10)
+	if err != nil {
+		t.Fatalf("StartBackground() error = %v", err)
+	}
+
+	var childPid int
+	deadline := time.Now().Add(5 * time.Second)
+	for childPid == 0 && time.Now().Before(deadline) {
+		if lines := b.Output().Tail(1); len(lines) == 1 {
+			childPid, _ = strconv.Atoi(lines[0].Text)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if childPid == 0 {
+		t.Fatal("did not receive grandchild PID")
+	}
+
+	if err := b.Stop(time.Second); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if b.Running() || !b.Stopped() {
+		t.Error("process should be stopped")
+	}
+
+	// The grandchild must be gone as well (allow for reaping by init)
+	deadline = time.Now().Add(5 * time.Second)
+	for syscall.Kill(childPid, 0) == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if syscall.Kill(childPid, 0) == nil {
+		t.Errorf("grandchild %d survived Stop()", childPid)
+	}
+}
@@ -1,9 +1,7 @@
 package executor
 
 import (
-	"bytes"
 	"context"
-	"os/exec"
 	"time"
 )
 
@@ -38,44 +36,12 @@ func ExecuteInDir(ctx context.Context, workingDir string, args ...string) Result
 
 // ExecuteWithOptions runs a devspace command with custom timeout and working directory
 func ExecuteWithOptions(ctx context.Context, timeout time.Duration, workingDir string, args ...string) Result {
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "devspace", args...)
-
-	if workingDir != "" {
-		cmd.Dir = workingDir
-	}
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	result := Result{
-		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
-		ExitCode: 0,
-	}
-
-	if err != nil {
-		// Check for context cancellation/timeout first
-		if ctx.Err() == context.DeadlineExceeded {
-			result.ExitCode = -2
-			result.Error = "command timed out"
-		} else if ctx.Err() == context.Canceled {
-			result.ExitCode = -3
-			result.Error = "command was cancelled"
-		} else if exitErr, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitErr.ExitCode()
-		} else {
-			result.ExitCode = -1
-			result.Error = err.Error()
-		}
-	}
-
-	return result
+	return NewExecRunner().Run(ctx, Command{
+		Binary:  DevspaceBinary,
+		Args:    args,
+		Dir:     workingDir,
+		Timeout: timeout,
+	})
 }
 
 // FormatOutput returns a formatted string combining stdout and stderr
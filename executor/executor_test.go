@@ -166,3 +166,52 @@ func TestExecute(t *testing.T) {
 	_ = result.Success()
 	_ = result.FormatOutput()
 }
+
+func TestExecRunner_OnLine(t *testing.T) {
+	var lines []string
+	result := NewExecRunner().Run(context.Background(), Command{
+		Binary: "sh",
+		Args:   []string{"-c", "echo one; echo two >&2; printf three"},
+		OnLine: func(stream Stream, line string) {
+			lines = append(lines, string(stream)+":"+line)
+		},
+	})
+	if !result.Success() {
+		t.Skipf("sh not available: %s", result.FormatOutput())
+	}
+
+	if result.Stdout != "one\nthree" {
+		t.Errorf("Stdout = %q, want full buffered output", result.Stdout)
+	}
+	want := map[string]bool{"stdout:one": true, "stderr:two": true, "stdout:three": true}
+	if len(lines) != len(want) {
+		t.Fatalf("got lines %v, want %d lines", lines, len(want))
+	}
+	for _, l := range lines {
+		if !want[l] {
+			t.Errorf("unexpected line %q", l)
+		}
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := &lineWriter{stream: Stdout, emit: func(stream Stream, line string) {
+		lines = append(lines, line)
+	}}
+
+	w.Write([]byte("par"))
+	w.Write([]byte("tial\r\nnext\nla"))
+	w.Write([]byte("st"))
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 complete lines before flush, got %v", lines)
+	}
+	w.Flush()
+
+	expected := []string{"partial", "next", "last"}
+	for i, l := range expected {
+		if lines[i] != l {
+			t.Errorf("line %d = %q, want %q", i, lines[i], l)
+		}
+	}
+}
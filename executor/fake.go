@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// FakeRunner is a scriptable in-memory Runner for tests. It records every
+// invocation and replays the first scripted response whose binary and
+// argument prefix match the command.
+type FakeRunner struct {
+	mu        sync.Mutex
+	responses []fakeResponse
+	calls     []Command
+}
+
+type fakeResponse struct {
+	binary string
+	prefix []string
+	result Result
+}
+
+// NewFakeRunner creates an empty FakeRunner
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{}
+}
+
+// On scripts the result returned for commands of binary whose arguments
+// start with argsPrefix. An empty prefix matches any invocation of binary.
+func (f *FakeRunner) On(binary string, argsPrefix []string, result Result) *FakeRunner {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.responses = append(f.responses, fakeResponse{
+		binary: binary,
+		prefix: argsPrefix,
+		result: result,
+	})
+	return f
+}
+
+// Run records the command and returns the matching scripted result
+func (f *FakeRunner) Run(ctx context.Context, cmd Command) Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, cmd)
+
+	for _, r := range f.responses {
+		if r.binary == cmd.Binary && hasPrefix(cmd.Args, r.prefix) {
+			if cmd.OnLine != nil {
+				replayLines(cmd.OnLine, Stdout, r.result.Stdout)
+				replayLines(cmd.OnLine, Stderr, r.result.Stderr)
+			}
+			return r.result
+		}
+	}
+
+	return Result{
+		ExitCode: -1,
+		Error:    "fake runner: no response scripted for " + cmd.String(),
+	}
+}
+
+// Calls returns a copy of all recorded invocations in order
+func (f *FakeRunner) Calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	calls := make([]Command, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+// replayLines feeds scripted output to a line callback
+func replayLines(fn LineFunc, stream Stream, output string) {
+	if output == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		fn(stream, line)
+	}
+}
+
+// hasPrefix reports whether args starts with prefix
+func hasPrefix(args, prefix []string) bool {
+	if len(prefix) > len(args) {
+		return false
+	}
+	for i, p := range prefix {
+		if args[i] != p {
+			return false
+		}
+	}
+	return true
+}
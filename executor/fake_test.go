@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFakeRunner(t *testing.T) {
+	fake := NewFakeRunner().
+		On("devspace", []string{"list", "deployments"}, Result{Stdout: "deployments"}).
+		On("devspace", []string{"list"}, Result{Stdout: "generic list"}).
+		On("kubectl", nil, Result{Stderr: "forbidden", ExitCode: 1})
+
+	ctx := context.Background()
+
+	if got := fake.Run(ctx, Command{Binary: "devspace", Args: []string{"list", "deployments"}}); got.Stdout != "deployments" {
+		t.Errorf("expected first matching response, got %q", got.Stdout)
+	}
+	if got := fake.Run(ctx, Command{Binary: "devspace", Args: []string{"list", "profiles"}}); got.Stdout != "generic list" {
+		t.Errorf("expected prefix match, got %q", got.Stdout)
+	}
+	if got := fake.Run(ctx, Command{Binary: "kubectl", Args: []string{"get", "pods"}}); got.ExitCode != 1 {
+		t.Errorf("expected scripted exit code 1, got %d", got.ExitCode)
+	}
+	if got := fake.Run(ctx, Command{Binary: "devspace", Args: []string{"deploy"}}); got.Success() {
+		t.Error("unscripted command should fail")
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 recorded calls, got %d", len(calls))
+	}
+	if !reflect.DeepEqual(calls[1].Args, []string{"list", "profiles"}) {
+		t.Errorf("unexpected recorded args: %v", calls[1].Args)
+	}
+}
+
+func TestExecRunner_Env(t *testing.T) {
+	result := NewExecRunner().Run(context.Background(), Command{
+		Binary: "sh",
+		Args:   []string{"-c", "echo $DEVSPACE_MCP_TEST"},
+		Env:    []string{"DEVSPACE_MCP_TEST=hello"},
+	})
+	if !result.Success() {
+		t.Skipf("sh not available: %s", result.FormatOutput())
+	}
+	if result.Stdout != "hello\n" {
+		t.Errorf("Stdout = %q, want %q", result.Stdout, "hello\n")
+	}
+}
+
+func TestExecRunner_Stdin(t *testing.T) {
+	result := NewExecRunner().Run(context.Background(), Command{
+		Binary: "cat",
+		Stdin:  "kind: Service\n",
+	})
+	if !result.Success() {
+		t.Skipf("cat not available: %s", result.FormatOutput())
+	}
+	if result.Stdout != "kind: Service\n" {
+		t.Errorf("Stdout = %q, want %q", result.Stdout, "kind: Service\n")
+	}
+}
+
+func TestSetBinaryPath(t *testing.T) {
+	t.Cleanup(func() { SetBinaryPath(DevspaceBinary, "") })
+
+	SetBinaryPath(DevspaceBinary, "echo")
+	result := NewExecRunner().Run(context.Background(), Command{
+		Binary: DevspaceBinary,
+		Args:   []string{"pinned"},
+	})
+	if !result.Success() {
+		t.Skipf("echo not available: %s", result.FormatOutput())
+	}
+	if result.Stdout != "pinned\n" {
+		t.Errorf("Stdout = %q, want %q", result.Stdout, "pinned\n")
+	}
+
+	SetBinaryPath(DevspaceBinary, "")
+	if got := resolveBinary(DevspaceBinary); got != DevspaceBinary {
+		t.Errorf("resolveBinary() = %q after reset", got)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package executor
+
+import "os/exec"
+
+// setProcessGroup is a no-op on Windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessGroup kills the child; Windows has no graceful equivalent
+// of SIGTERM for console processes
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// killProcessGroup kills the child
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,95 @@
+package executor
+
+import "sync"
+
+// Line is a single captured output line
+type Line struct {
+	Seq    int64  `json:"seq"`
+	Stream Stream `json:"stream"`
+	Text   string `json:"text"`
+}
+
+// RingBuffer keeps the most recent output lines of a process. Every line
+// gets a monotonically increasing sequence number so readers can resume
+// from a cursor and detect lines that were dropped in between.
+type RingBuffer struct {
+	mu    sync.Mutex
+	lines []Line
+	start int
+	size  int
+	next  int64
+}
+
+// NewRingBuffer creates a buffer holding at most capacity lines
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer{lines: make([]Line, capacity)}
+}
+
+// Append adds a line, evicting the oldest one when the buffer is full
+func (b *RingBuffer) Append(stream Stream, text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	line := Line{Seq: b.next, Stream: stream, Text: text}
+	b.next++
+
+	if b.size < len(b.lines) {
+		b.lines[(b.start+b.size)%len(b.lines)] = line
+		b.size++
+		return
+	}
+	b.lines[b.start] = line
+	b.start = (b.start + 1) % len(b.lines)
+}
+
+// ReadFrom returns up to limit lines with a sequence number >= cursor, the
+// cursor to pass on the next call, and how many lines between cursor and the
+// oldest retained line were evicted. A limit <= 0 returns all available lines.
+func (b *RingBuffer) ReadFrom(cursor int64, limit int) (lines []Line, next int64, dropped int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	oldest := b.next - int64(b.size)
+	if cursor < oldest {
+		dropped = oldest - cursor
+		cursor = oldest
+	}
+	if cursor > b.next {
+		cursor = b.next
+	}
+
+	for seq := cursor; seq < b.next; seq++ {
+		if limit > 0 && len(lines) >= limit {
+			break
+		}
+		lines = append(lines, b.lines[(b.start+int(seq-oldest))%len(b.lines)])
+	}
+	return lines, cursor + int64(len(lines)), dropped
+}
+
+// Tail returns the last n lines
+func (b *RingBuffer) Tail(n int) []Line {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > b.size {
+		n = b.size
+	} else if n < 0 {
+		n = 0
+	}
+	lines := make([]Line, 0, n)
+	for i := b.size - n; i < b.size; i++ {
+		lines = append(lines, b.lines[(b.start+i)%len(b.lines)])
+	}
+	return lines
+}
+
+// Next returns the sequence number the next appended line will get
+func (b *RingBuffer) Next() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.next
+}
@@ -0,0 +1,186 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DevspaceBinary is the name of the devspace CLI binary
+const DevspaceBinary = "devspace"
+
+// KubectlBinary is the name of the kubectl CLI binary
+const KubectlBinary = "kubectl"
+
+// binaryPaths maps binary names to the executables configured with SetBinaryPath
+var binaryPaths = map[string]string{}
+
+// SetBinaryPath runs the executable at path for commands naming the binary,
+// e.g. to pin a specific devspace version. It must be called before any
+// command is run. An empty path restores the lookup in PATH.
+func SetBinaryPath(name, path string) {
+	if path == "" || path == name {
+		delete(binaryPaths, name)
+		return
+	}
+	binaryPaths[name] = path
+}
+
+// resolveBinary returns the executable to run for a binary name
+func resolveBinary(name string) string {
+	if path, ok := binaryPaths[name]; ok {
+		return path
+	}
+	return name
+}
+
+// Stream identifies the output stream a line was written to
+type Stream string
+
+const (
+	// Stdout is the standard output stream
+	Stdout Stream = "stdout"
+	// Stderr is the standard error stream
+	Stderr Stream = "stderr"
+)
+
+// LineFunc receives output lines while a command is running
+type LineFunc func(stream Stream, line string)
+
+// Command describes a single process invocation
+type Command struct {
+	Binary  string
+	Args    []string
+	Dir     string
+	Env     []string
+	Timeout time.Duration
+	// Stdin, if non-empty, is written to the process' standard input
+	Stdin string
+	// OnLine, if set, is called for every complete output line as it is
+	// produced. Calls are serialized. The full output is still returned
+	// in the Result.
+	OnLine LineFunc
+}
+
+// String renders the command as a shell-like line, mainly for diagnostics
+func (c Command) String() string {
+	return strings.Join(append([]string{c.Binary}, c.Args...), " ")
+}
+
+// Runner executes commands and reports their output
+type Runner interface {
+	Run(ctx context.Context, cmd Command) Result
+}
+
+// ExecRunner runs commands as local processes
+type ExecRunner struct{}
+
+// NewExecRunner creates a Runner backed by os/exec
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{}
+}
+
+// Run executes the command and waits for it to finish
+func (r *ExecRunner) Run(ctx context.Context, c Command) Result {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, resolveBinary(c.Binary), c.Args...)
+
+	if c.Dir != "" {
+		cmd.Dir = c.Dir
+	}
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	if c.Stdin != "" {
+		cmd.Stdin = strings.NewReader(c.Stdin)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	var outLines, errLines *lineWriter
+	if c.OnLine != nil {
+		var mu sync.Mutex
+		emit := func(stream Stream, line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			c.OnLine(stream, line)
+		}
+		outLines = &lineWriter{stream: Stdout, emit: emit}
+		errLines = &lineWriter{stream: Stderr, emit: emit}
+		cmd.Stdout = io.MultiWriter(&stdout, outLines)
+		cmd.Stderr = io.MultiWriter(&stderr, errLines)
+	}
+
+	err := cmd.Run()
+
+	if c.OnLine != nil {
+		outLines.Flush()
+		errLines.Flush()
+	}
+
+	result := Result{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: 0,
+	}
+
+	if err != nil {
+		// Check for context cancellation/timeout first
+		if ctx.Err() == context.DeadlineExceeded {
+			result.ExitCode = -2
+			result.Error = "command timed out"
+		} else if ctx.Err() == context.Canceled {
+			result.ExitCode = -3
+			result.Error = "command was cancelled"
+		} else if exitErr, ok := err.(*exec.ExitError); ok {
+			result.ExitCode = exitErr.ExitCode()
+		} else {
+			result.ExitCode = -1
+			result.Error = err.Error()
+		}
+	}
+
+	return result
+}
+
+// lineWriter splits written bytes into lines and emits each complete line
+type lineWriter struct {
+	stream Stream
+	emit   LineFunc
+	buf    []byte
+}
+
+// Write buffers p and emits every complete line it contains
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(w.stream, strings.TrimSuffix(string(w.buf[:i]), "\r"))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits any trailing output that was not terminated by a newline
+func (w *lineWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.emit(w.stream, strings.TrimSuffix(string(w.buf), "\r"))
+		w.buf = nil
+	}
+}
@@ -1,25 +1,109 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"devspace-mcp/executor"
+	"devspace-mcp/policy"
+	"devspace-mcp/serverconfig"
 	"devspace-mcp/tools"
+	"devspace-mcp/transport"
+	"devspace-mcp/workspace"
 
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// stringList is a flag that can be repeated
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ", ") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
 func main() {
+	configFile := flag.String("config", os.Getenv("DEVSPACE_MCP_CONFIG"), "Path to the server configuration file (env: DEVSPACE_MCP_CONFIG)")
+	configFlags := serverconfig.RegisterFlags(flag.CommandLine)
+	policyFile := flag.String("policy", os.Getenv("DEVSPACE_MCP_POLICY"), "Path to a policy file protecting kube contexts and namespaces (env: DEVSPACE_MCP_POLICY)")
+	var redactPatterns stringList
+	flag.Var(&redactPatterns, "redact-pattern", "Regular expression for additional secrets to mask in tool output; may be repeated")
+	transportName := flag.String("transport", transport.Stdio, "Transport to serve: stdio, sse or http")
+	listenAddr := flag.String("listen", transport.DefaultAddr, "Listen address for the sse and http transports")
+	authToken := flag.String("auth-token", os.Getenv("DEVSPACE_MCP_AUTH_TOKEN"), "Bearer token required by the sse and http transports (env: DEVSPACE_MCP_AUTH_TOKEN)")
+	flag.Parse()
+
+	cfg, err := serverconfig.Load(*configFile)
+	if err == nil {
+		err = configFlags.Apply(cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		os.Exit(1)
+	}
+	tools.SetConfig(cfg)
+
+	projects := workspace.NewRegistry(cfg.Projects.Roots)
+	if _, err := projects.Scan(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	tools.SetProjects(projects)
+
+	if *transportName != transport.Stdio && *authToken == "" && !transport.IsLoopback(*listenAddr) {
+		fmt.Fprintf(os.Stderr, "Warning: serving on %s without --auth-token\n", *listenAddr)
+	}
+
+	if err := tools.SetErrorPatternDirs(cfg.ErrorPatterns.Dirs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error pattern error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := tools.SetRedactPatterns(redactPatterns); err != nil {
+		fmt.Fprintf(os.Stderr, "Redaction error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *policyFile != "" {
+		p, err := policy.Load(*policyFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Policy error: %v\n", err)
+			os.Exit(1)
+		}
+		tools.SetPolicy(p)
+	}
+
+	hooks := &server.Hooks{}
+	tools.RegisterHooks(hooks)
+
 	s := server.NewMCPServer(
 		"devspace-mcp",
 		"1.0.0",
 		server.WithToolCapabilities(true),
 		server.WithRecovery(),
+		server.WithLogging(),
+		server.WithHooks(hooks),
 	)
 
-	tools.RegisterAll(s)
+	tools.RegisterAll(s, executor.NewExecRunner())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	if len(cfg.Projects.Roots) > 0 {
+		go projects.Watch(ctx, cfg.Projects.RefreshInterval)
+	}
+	err = transport.Serve(ctx, s, transport.Options{
+		Transport: *transportName,
+		Addr:      *listenAddr,
+		AuthToken: *authToken,
+	})
+	stop()
+
+	// Stop background dev sessions before exiting
+	tools.Shutdown()
 
-	if err := server.ServeStdio(s); err != nil {
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,246 @@
+// Package policy protects kube contexts and namespaces from destructive
+// tool calls. A policy file lists rules that match kube contexts and
+// namespaces by glob pattern and either deny tools outright or require an
+// explicit confirmation token.
+//
+// Example policy file:
+//
+//	rules:
+//	  - name: shared-staging
+//	    kube_contexts: ["*staging*"]
+//	    deny: [purge, deploy:force_deploy, exec]
+//	    confirm: [deploy, run_pipeline]
+//	    confirm_token: staging-ok
+//
+// Actions name a tool, with or without the "devspace_" prefix. An action
+// of the form "tool:param" only matches calls that set the boolean
+// parameter, e.g. "deploy:force_deploy". "*" matches every tool.
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// toolPrefix is the common prefix of all tool names
+const toolPrefix = "devspace_"
+
+// Policy is a set of rules loaded from a policy file
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule protects the matching kube contexts and namespaces. Empty pattern
+// lists match any context or namespace.
+type Rule struct {
+	Name         string   `yaml:"name"`
+	KubeContexts []string `yaml:"kube_contexts"`
+	Namespaces   []string `yaml:"namespaces"`
+	Deny         []string `yaml:"deny"`
+	Confirm      []string `yaml:"confirm"`
+	// ConfirmToken is the value the confirm parameter must carry. It
+	// defaults to the rule name.
+	ConfirmToken string `yaml:"confirm_token"`
+}
+
+// Request describes a tool call to check
+type Request struct {
+	Tool          string
+	Flags         map[string]bool
+	KubeContext   string
+	Namespace     string
+	AllNamespaces bool
+	Confirm       string
+}
+
+// Violation is returned when a request breaks a rule
+type Violation struct {
+	Rule string
+	// Action is the deny or confirm entry that matched
+	Action      string
+	Tool        string
+	KubeContext string
+	Namespace   string
+	// Token is set when the action is allowed with confirmation
+	Token string
+}
+
+// Error describes the violated rule and, for confirmation rules, how to proceed
+func (v *Violation) Error() string {
+	target := fmt.Sprintf("kube context %q, namespace %q", v.KubeContext, v.Namespace)
+	if v.Token != "" {
+		return fmt.Sprintf("policy rule %q requires confirmation for %s (action %q) in %s: call again with confirm=%q to proceed",
+			v.Rule, v.Tool, v.Action, target, v.Token)
+	}
+	return fmt.Sprintf("policy rule %q denies %s (action %q) in %s", v.Rule, v.Tool, v.Action, target)
+}
+
+// Load reads and validates a policy file
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse decodes and validates a policy document
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for i, r := range p.Rules {
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", r.Name)
+		}
+		names[r.Name] = true
+		if len(r.Deny) == 0 && len(r.Confirm) == 0 {
+			return nil, fmt.Errorf("rule %q neither denies nor requires confirmation for any tool", r.Name)
+		}
+		for _, action := range append(append([]string{}, r.Deny...), r.Confirm...) {
+			if strings.TrimSpace(action) == "" {
+				return nil, fmt.Errorf("rule %q has an empty action", r.Name)
+			}
+		}
+	}
+	return &p, nil
+}
+
+// Applies reports whether any rule could affect the given call, regardless
+// of its kube context and namespace. Callers use it to skip resolving the
+// current context for calls no rule cares about.
+func (p *Policy) Applies(tool string, flags map[string]bool) bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range p.Rules {
+		if matchAction(r.Deny, tool, flags) != "" || matchAction(r.Confirm, tool, flags) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns a *Violation for the first rule the request breaks. Deny
+// rules are checked before confirmation rules so a deny cannot be bypassed
+// with a token.
+func (p *Policy) Check(r Request) error {
+	if p == nil {
+		return nil
+	}
+
+	var confirm *Violation
+	for _, rule := range p.Rules {
+		if !rule.matchesTarget(r) {
+			continue
+		}
+		if action := matchAction(rule.Deny, r.Tool, r.Flags); action != "" {
+			return &Violation{Rule: rule.Name, Action: action, Tool: r.Tool, KubeContext: r.KubeContext, Namespace: r.namespace()}
+		}
+		if confirm != nil {
+			continue
+		}
+		if action := matchAction(rule.Confirm, r.Tool, r.Flags); action != "" && r.Confirm != rule.token() {
+			confirm = &Violation{Rule: rule.Name, Action: action, Tool: r.Tool, KubeContext: r.KubeContext, Namespace: r.namespace(), Token: rule.token()}
+		}
+	}
+	if confirm != nil {
+		return confirm
+	}
+	return nil
+}
+
+// token returns the confirmation token of the rule
+func (r Rule) token() string {
+	if r.ConfirmToken != "" {
+		return r.ConfirmToken
+	}
+	return r.Name
+}
+
+// matchesTarget reports whether the rule covers the request's context and namespace
+func (r Rule) matchesTarget(req Request) bool {
+	if len(r.KubeContexts) > 0 && !matchAny(r.KubeContexts, req.KubeContext) {
+		return false
+	}
+	// A request across all namespaces touches every protected namespace
+	if len(r.Namespaces) > 0 && !req.AllNamespaces && !matchAny(r.Namespaces, req.Namespace) {
+		return false
+	}
+	return true
+}
+
+// namespace renders the request namespace for error messages
+func (r Request) namespace() string {
+	if r.AllNamespaces {
+		return "*"
+	}
+	return r.Namespace
+}
+
+// matchAction returns the first action that matches the tool call
+func matchAction(actions []string, tool string, flags map[string]bool) string {
+	for _, action := range actions {
+		name, flag, hasFlag := strings.Cut(strings.TrimSpace(action), ":")
+		if name != "*" && !strings.HasPrefix(name, toolPrefix) {
+			name = toolPrefix + name
+		}
+		if name != "*" && name != tool {
+			continue
+		}
+		if hasFlag && !flags[flag] {
+			continue
+		}
+		return action
+	}
+	return ""
+}
+
+// matchAny reports whether value matches one of the glob patterns
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if Match(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether value matches the glob pattern. '*' matches any
+// sequence of characters, including '/' and ':' which are common in kube
+// context names, and '?' matches a single character.
+func Match(pattern, value string) bool {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String()).MatchString(value)
+}
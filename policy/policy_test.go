@@ -0,0 +1,143 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicy = `
+rules:
+  - name: shared-staging
+    kube_contexts: ["arn:aws:eks:*:cluster/staging"]
+    deny: [purge, deploy:force_deploy, exec]
+    confirm: [deploy, devspace_run_pipeline]
+    confirm_token: staging-ok
+  - name: prod-namespaces
+    namespaces: ["prod-*"]
+    deny: ["*"]
+`
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"staging", "staging", true},
+		{"staging", "staging-2", false},
+		{"*staging*", "kind-staging-2", true},
+		{"arn:aws:eks:*:cluster/staging", "arn:aws:eks:eu-west-1:123456789012:cluster/staging", true},
+		{"prod-?", "prod-1", true},
+		{"prod-?", "prod-12", false},
+		{"team.a", "teamxa", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{"missing name", "rules:\n  - deny: [purge]\n", "no name"},
+		{"duplicate name", "rules:\n  - name: a\n    deny: [purge]\n  - name: a\n    deny: [exec]\n", "duplicate"},
+		{"no actions", "rules:\n  - name: a\n    namespaces: [dev]\n", "neither denies"},
+		{"unknown field", "rules:\n  - name: a\n    denied: [purge]\n", "denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	if p, err := Parse(nil); err != nil || len(p.Rules) != 0 {
+		t.Errorf("empty policy should parse, got %v, %v", p, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	staging := "arn:aws:eks:eu-west-1:123456789012:cluster/staging"
+
+	tests := []struct {
+		name      string
+		req       Request
+		wantRule  string
+		wantToken string
+	}{
+		{"purge on staging", Request{Tool: "devspace_purge", KubeContext: staging, Namespace: "team-a"}, "shared-staging", ""},
+		{"force deploy on staging", Request{Tool: "devspace_deploy", KubeContext: staging, Flags: map[string]bool{"force_deploy": true}}, "shared-staging", ""},
+		{"deploy on staging needs confirmation", Request{Tool: "devspace_deploy", KubeContext: staging}, "shared-staging", "staging-ok"},
+		{"confirmed deploy on staging", Request{Tool: "devspace_deploy", KubeContext: staging, Confirm: "staging-ok"}, "", ""},
+		{"wrong token", Request{Tool: "devspace_run_pipeline", KubeContext: staging, Confirm: "yes"}, "shared-staging", "staging-ok"},
+		{"token does not lift a deny", Request{Tool: "devspace_exec", KubeContext: staging, Confirm: "staging-ok"}, "shared-staging", ""},
+		{"purge on dev context", Request{Tool: "devspace_purge", KubeContext: "kind-dev", Namespace: "team-a"}, "", ""},
+		{"any tool in prod namespace", Request{Tool: "devspace_logs", KubeContext: "kind-dev", Namespace: "prod-eu"}, "prod-namespaces", ""},
+		{"all namespaces include prod", Request{Tool: "devspace_list_pods", KubeContext: "kind-dev", AllNamespaces: true}, "prod-namespaces", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.Check(tt.req)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("expected no violation, got %v", err)
+				}
+				return
+			}
+			var v *Violation
+			if !errors.As(err, &v) {
+				t.Fatalf("expected a violation, got %v", err)
+			}
+			if v.Rule != tt.wantRule || v.Token != tt.wantToken {
+				t.Errorf("violation = %+v, want rule %q token %q", v, tt.wantRule, tt.wantToken)
+			}
+			if !strings.Contains(err.Error(), tt.wantRule) {
+				t.Errorf("error should name the rule: %v", err)
+			}
+		})
+	}
+}
+
+func TestApplies(t *testing.T) {
+	p, _ := Parse([]byte("rules:\n  - name: a\n    deny: [deploy:force_deploy]\n"))
+
+	if p.Applies("devspace_deploy", nil) {
+		t.Error("deploy without force_deploy should not be affected")
+	}
+	if !p.Applies("devspace_deploy", map[string]bool{"force_deploy": true}) {
+		t.Error("deploy with force_deploy should be affected")
+	}
+	if (*Policy)(nil).Applies("devspace_purge", nil) {
+		t.Error("a nil policy affects nothing")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(testPolicy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(p.Rules) != 2 {
+		t.Errorf("expected 2 rules, got %d", len(p.Rules))
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,57 @@
+package serverconfig
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Flags are command line overrides of the configuration
+type Flags struct {
+	fs     *flag.FlagSet
+	values map[string]*flagValue
+}
+
+// flagValue records the raw value of a flag until it is applied
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (v *flagValue) String() string     { return v.value }
+func (v *flagValue) Set(s string) error { v.value = s; return nil }
+func (v *flagValue) IsBoolFlag() bool   { return v.isBool }
+
+// RegisterFlags defines a flag for every setting that can be overridden.
+// Flag values are applied with Apply after the configuration was loaded.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs, values: make(map[string]*flagValue)}
+	for _, s := range Default().settings() {
+		v := &flagValue{isBool: s.isBool}
+		f.values[s.flag] = v
+		fs.Var(v, s.flag, fmt.Sprintf("%s (env: %s%s)", s.usage, envPrefix, s.env))
+	}
+	return f
+}
+
+// Apply overrides the configuration with the flags set on the command line
+func (f *Flags) Apply(c *Config) error {
+	setters := make(map[string]setting)
+	for _, s := range c.settings() {
+		setters[s.flag] = s
+	}
+
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		s, ok := setters[fl.Name]
+		if !ok || err != nil {
+			return
+		}
+		if setErr := s.set(f.values[fl.Name].value); setErr != nil {
+			err = fmt.Errorf("invalid --%s: %w", fl.Name, setErr)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return c.Validate()
+}
@@ -0,0 +1,371 @@
+// Package serverconfig holds the settings of the MCP server itself: which
+// binaries to run, how long commands may take, the defaults applied to tool
+// parameters, output limits and which tools to register.
+//
+// Settings are read from a YAML file, overridden by DEVSPACE_MCP_*
+// environment variables and finally by command line flags.
+//
+// Example configuration file:
+//
+//	binaries:
+//	  devspace: /opt/devspace/v6.3.12/devspace
+//	timeouts:
+//	  default: 2m
+//	  long_running: 10m
+//	  tools:
+//	    devspace_deploy: 30m
+//	defaults:
+//	  namespace: dev
+//	  kube_context: kind-dev
+//	max_output_bytes: 1048576
+//	tools:
+//	  read_only: true
+//	  disable: [devspace_logs]
+//	projects:
+//	  roots: [/workspace/services]
+//	error_patterns:
+//	  dirs: [/etc/devspace-mcp/patterns]
+package serverconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"devspace-mcp/executor"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix is the prefix of all environment variable overrides
+const envPrefix = "DEVSPACE_MCP_"
+
+// Config is the server configuration
+type Config struct {
+	Binaries Binaries `yaml:"binaries"`
+	Timeouts Timeouts `yaml:"timeouts"`
+	Defaults Defaults `yaml:"defaults"`
+	// MaxOutputBytes caps the size of the text returned by a tool. Zero
+	// means unlimited.
+	MaxOutputBytes int           `yaml:"max_output_bytes"`
+	Tools          Tools         `yaml:"tools"`
+	Projects       Projects      `yaml:"projects"`
+	ErrorPatterns  ErrorPatterns `yaml:"error_patterns"`
+}
+
+// Binaries are the paths of the CLIs the tools run
+type Binaries struct {
+	Devspace string `yaml:"devspace" json:"devspace"`
+	Kubectl  string `yaml:"kubectl" json:"kubectl"`
+}
+
+// Timeouts bound how long a single command may run
+type Timeouts struct {
+	// Default applies to short commands such as list, print and logs
+	Default time.Duration `yaml:"default"`
+	// LongRunning applies to build, deploy, pipelines and sync
+	LongRunning time.Duration `yaml:"long_running"`
+	// Tools overrides the timeout of every command run by a tool
+	Tools map[string]time.Duration `yaml:"tools"`
+}
+
+// Defaults are used when a tool call omits the parameter
+type Defaults struct {
+	WorkingDir  string `yaml:"working_dir" json:"working_dir,omitempty"`
+	Namespace   string `yaml:"namespace" json:"namespace,omitempty"`
+	KubeContext string `yaml:"kube_context" json:"kube_context,omitempty"`
+	Profile     string `yaml:"profile" json:"profile,omitempty"`
+}
+
+// Tool categories, from inspecting to running arbitrary commands
+const (
+	// CategoryRead tools only inspect the project and cluster
+	CategoryRead = "read"
+	// CategoryMutate tools build, deploy, sync or start processes
+	CategoryMutate = "mutate"
+	// CategoryDestructive tools remove resources
+	CategoryDestructive = "destructive"
+	// CategoryExec tools run arbitrary commands
+	CategoryExec = "exec"
+)
+
+// Categories lists all tool categories
+var Categories = []string{CategoryRead, CategoryMutate, CategoryDestructive, CategoryExec}
+
+// Tools selects the tools to register. Names may use '*' wildcards.
+// Disabled tools are never registered and enabled tools always are. Other
+// tools are registered if their category is allowed or, without a category
+// filter, if the enable list is empty.
+type Tools struct {
+	// ReadOnly registers only tools of the read category
+	ReadOnly bool `yaml:"read_only"`
+	// Categories, if not empty, registers only tools of these categories
+	Categories []string `yaml:"categories"`
+	// Enable registers the listed tools regardless of their category
+	Enable []string `yaml:"enable"`
+	// Disable skips the listed tools
+	Disable []string `yaml:"disable"`
+}
+
+// Projects configures the registry of devspace projects
+type Projects struct {
+	// Roots are searched for devspace configs
+	Roots []string `yaml:"roots"`
+	// RefreshInterval is how often the roots are rescanned for changes
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+// ErrorPatterns configures the patterns that classify command errors
+type ErrorPatterns struct {
+	// Dirs hold YAML pattern packs loaded on top of the built-in patterns
+	Dirs []string `yaml:"dirs"`
+}
+
+// AllowedCategories returns the categories to register, or nil when tools
+// are not filtered by category
+func (t Tools) AllowedCategories() []string {
+	if t.ReadOnly {
+		return []string{CategoryRead}
+	}
+	return t.Categories
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		Binaries: Binaries{
+			Devspace: executor.DevspaceBinary,
+			Kubectl:  executor.KubectlBinary,
+		},
+		Timeouts: Timeouts{
+			Default:     executor.DefaultTimeout,
+			LongRunning: executor.LongRunningTimeout,
+			Tools: map[string]time.Duration{
+				"devspace_exec": 5 * time.Minute,
+				// Status runs analyze with --timeout=30 and a short grace period
+				"devspace_status": 35 * time.Second,
+			},
+		},
+		Projects: Projects{RefreshInterval: 10 * time.Second},
+	}
+}
+
+// Load returns the default configuration overridden by the file at path,
+// if path is not empty, and by environment variables
+func Load(path string) (*Config, error) {
+	c := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := c.parse(data); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+	if err := c.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parse overrides the configuration with a YAML document
+func (c *Config) parse(data []byte) error {
+	defaults := c.Timeouts.Tools
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	// Tool timeouts from the file add to the built-in ones
+	for tool, timeout := range defaults {
+		if _, ok := c.Timeouts.Tools[tool]; !ok {
+			c.Timeouts.Tools[tool] = timeout
+		}
+	}
+	return c.Validate()
+}
+
+// ApplyEnv overrides the configuration with DEVSPACE_MCP_* variables
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, s := range c.settings() {
+		if value, ok := lookup(envPrefix + s.env); ok {
+			if err := s.set(value); err != nil {
+				return fmt.Errorf("invalid %s%s: %w", envPrefix, s.env, err)
+			}
+		}
+	}
+	return c.Validate()
+}
+
+// Validate checks that timeouts and limits are not negative
+func (c *Config) Validate() error {
+	if c.Timeouts.Default < 0 || c.Timeouts.LongRunning < 0 {
+		return fmt.Errorf("timeouts must not be negative")
+	}
+	for tool, timeout := range c.Timeouts.Tools {
+		if timeout < 0 {
+			return fmt.Errorf("timeout of %s must not be negative", tool)
+		}
+	}
+	if c.Projects.RefreshInterval < 0 {
+		return fmt.Errorf("projects.refresh_interval must not be negative")
+	}
+	if c.MaxOutputBytes < 0 {
+		return fmt.Errorf("max_output_bytes must not be negative")
+	}
+	for _, category := range c.Tools.Categories {
+		if !contains(Categories, category) {
+			return fmt.Errorf("unknown tool category %q: must be one of %s", category, strings.Join(Categories, ", "))
+		}
+	}
+	return nil
+}
+
+// Timeout returns the timeout of the commands run by tool. longRunning
+// selects the fallback for tools without their own timeout.
+func (c *Config) Timeout(tool string, longRunning bool) time.Duration {
+	if timeout := c.Timeouts.Tools[tool]; timeout > 0 {
+		return timeout
+	}
+	if longRunning {
+		if c.Timeouts.LongRunning > 0 {
+			return c.Timeouts.LongRunning
+		}
+		return executor.LongRunningTimeout
+	}
+	if c.Timeouts.Default > 0 {
+		return c.Timeouts.Default
+	}
+	return executor.DefaultTimeout
+}
+
+// setting is a configuration value that can be set from a string
+type setting struct {
+	// env is the environment variable name without the prefix
+	env string
+	// flag is the command line flag name
+	flag  string
+	usage string
+	set   func(string) error
+	// isBool makes the flag a boolean switch
+	isBool bool
+}
+
+// settings lists the values that can be overridden by environment
+// variables and flags
+func (c *Config) settings() []setting {
+	return []setting{
+		{"DEVSPACE_BINARY", "devspace-binary", "Path of the devspace binary", setString(&c.Binaries.Devspace), false},
+		{"KUBECTL_BINARY", "kubectl-binary", "Path of the kubectl binary", setString(&c.Binaries.Kubectl), false},
+		{"TIMEOUT", "timeout", "Timeout of short commands such as list, print and logs", setDuration(&c.Timeouts.Default), false},
+		{"LONG_RUNNING_TIMEOUT", "long-running-timeout", "Timeout of build, deploy, pipeline and sync commands", setDuration(&c.Timeouts.LongRunning), false},
+		{"TOOL_TIMEOUTS", "tool-timeouts", "Per-tool timeouts as tool=duration pairs separated by commas", c.setToolTimeouts, false},
+		{"WORKING_DIR", "working-dir", "Default working directory of tool calls", setString(&c.Defaults.WorkingDir), false},
+		{"NAMESPACE", "namespace", "Default namespace of tool calls", setString(&c.Defaults.Namespace), false},
+		{"KUBE_CONTEXT", "kube-context", "Default kube context of tool calls", setString(&c.Defaults.KubeContext), false},
+		{"PROFILE", "profile", "Default devspace profile of tool calls", setString(&c.Defaults.Profile), false},
+		{"MAX_OUTPUT_BYTES", "max-output-bytes", "Maximum size of the text returned by a tool; 0 means unlimited", setInt(&c.MaxOutputBytes), false},
+		{"READ_ONLY", "read-only", "Register only tools that inspect the project and cluster", setBool(&c.Tools.ReadOnly), true},
+		{"TOOL_CATEGORIES", "tool-categories", "Comma-separated categories of tools to register: read, mutate, destructive, exec", setList(&c.Tools.Categories), false},
+		{"ENABLE_TOOLS", "enable-tools", "Comma-separated tools to register regardless of their category", setList(&c.Tools.Enable), false},
+		{"DISABLE_TOOLS", "disable-tools", "Comma-separated tools not to register", setList(&c.Tools.Disable), false},
+		{"PROJECT_ROOTS", "project-roots", "Comma-separated directories to search for devspace projects", setList(&c.Projects.Roots), false},
+		{"PROJECT_REFRESH_INTERVAL", "project-refresh-interval", "How often the project roots are rescanned for changes", setDuration(&c.Projects.RefreshInterval), false},
+		{"ERROR_PATTERN_DIRS", "error-pattern-dirs", "Comma-separated directories with YAML error pattern packs", setList(&c.ErrorPatterns.Dirs), false},
+	}
+}
+
+// setToolTimeouts parses "tool=duration" pairs separated by commas
+func (c *Config) setToolTimeouts(value string) error {
+	if c.Timeouts.Tools == nil {
+		c.Timeouts.Tools = make(map[string]time.Duration)
+	}
+	for _, pair := range splitList(value) {
+		tool, d, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("expected tool=duration, got %q", pair)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil {
+			return err
+		}
+		c.Timeouts.Tools[strings.TrimSpace(tool)] = timeout
+	}
+	return nil
+}
+
+func setString(p *string) func(string) error {
+	return func(v string) error {
+		*p = v
+		return nil
+	}
+}
+
+func setDuration(p *time.Duration) func(string) error {
+	return func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*p = d
+		return nil
+	}
+}
+
+func setInt(p *int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*p = n
+		return nil
+	}
+}
+
+func setBool(p *bool) func(string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*p = b
+		return nil
+	}
+}
+
+func setList(p *[]string) func(string) error {
+	return func(v string) error {
+		*p = splitList(v)
+		return nil
+	}
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// contains reports whether list contains value
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
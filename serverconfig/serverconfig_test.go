@@ -0,0 +1,215 @@
+package serverconfig
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"devspace-mcp/executor"
+)
+
+const testConfig = `
+binaries:
+  devspace: /opt/devspace/v6.3.12/devspace
+timeouts:
+  long_running: 20m
+  tools:
+    devspace_deploy: 30m
+defaults:
+  namespace: dev
+  kube_context: kind-dev
+max_output_bytes: 65536
+tools:
+  disable: [devspace_purge]
+projects:
+  roots: [/workspace/services]
+  refresh_interval: 30s
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "devspace-mcp.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDefault(t *testing.T) {
+	c := Default()
+	if c.Binaries.Devspace != executor.DevspaceBinary || c.Binaries.Kubectl != executor.KubectlBinary {
+		t.Errorf("unexpected binaries: %+v", c.Binaries)
+	}
+	tests := []struct {
+		tool        string
+		longRunning bool
+		want        time.Duration
+	}{
+		{"devspace_list_pods", false, executor.DefaultTimeout},
+		{"devspace_deploy", true, executor.LongRunningTimeout},
+		{"devspace_exec", false, 5 * time.Minute},
+		{"devspace_status", false, 35 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := c.Timeout(tt.tool, tt.longRunning); got != tt.want {
+			t.Errorf("Timeout(%s) = %v, want %v", tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	c, err := Load(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if c.Binaries.Devspace != "/opt/devspace/v6.3.12/devspace" || c.Binaries.Kubectl != executor.KubectlBinary {
+		t.Errorf("unexpected binaries: %+v", c.Binaries)
+	}
+	if got := c.Timeout("devspace_deploy", true); got != 30*time.Minute {
+		t.Errorf("deploy timeout = %v", got)
+	}
+	if got := c.Timeout("devspace_build", true); got != 20*time.Minute {
+		t.Errorf("build timeout = %v", got)
+	}
+	if got := c.Timeout("devspace_exec", false); got != 5*time.Minute {
+		t.Errorf("built-in exec timeout should be kept, got %v", got)
+	}
+	if c.Defaults.Namespace != "dev" || c.Defaults.KubeContext != "kind-dev" || c.MaxOutputBytes != 65536 {
+		t.Errorf("unexpected config: %+v", c)
+	}
+	if len(c.Tools.Disable) != 1 || c.Tools.Disable[0] != "devspace_purge" {
+		t.Errorf("unexpected tools: %+v", c.Tools)
+	}
+	if strings.Join(c.Projects.Roots, ",") != "/workspace/services" || c.Projects.RefreshInterval != 30*time.Second {
+		t.Errorf("unexpected projects: %+v", c.Projects)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"unknown field", "timeout: 5m\n", "timeout"},
+		{"bad duration", "timeouts:\n  default: soon\n", "soon"},
+		{"negative limit", "max_output_bytes: -1\n", "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"DEVSPACE_MCP_KUBECTL_BINARY":     "/usr/local/bin/kubectl-1.29",
+		"DEVSPACE_MCP_TIMEOUT":            "90s",
+		"DEVSPACE_MCP_TOOL_TIMEOUTS":      "devspace_deploy=45m, devspace_build=25m",
+		"DEVSPACE_MCP_PROFILE":            "ci",
+		"DEVSPACE_MCP_ENABLE_TOOLS":       "devspace_list_*, devspace_logs",
+		"DEVSPACE_MCP_PROJECT_ROOTS":      "/srv/a,/srv/b",
+		"DEVSPACE_MCP_ERROR_PATTERN_DIRS": "/etc/devspace-mcp/patterns",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	c := Default()
+	if err := c.ApplyEnv(lookup); err != nil {
+		t.Fatalf("ApplyEnv() error = %v", err)
+	}
+	if c.Binaries.Kubectl != "/usr/local/bin/kubectl-1.29" || c.Defaults.Profile != "ci" {
+		t.Errorf("unexpected config: %+v", c)
+	}
+	if c.Timeout("devspace_logs", false) != 90*time.Second || c.Timeout("devspace_deploy", true) != 45*time.Minute || c.Timeout("devspace_build", true) != 25*time.Minute {
+		t.Errorf("unexpected timeouts: %+v", c.Timeouts)
+	}
+	if strings.Join(c.Tools.Enable, ",") != "devspace_list_*,devspace_logs" {
+		t.Errorf("unexpected enabled tools: %v", c.Tools.Enable)
+	}
+	if strings.Join(c.Projects.Roots, ",") != "/srv/a,/srv/b" {
+		t.Errorf("unexpected project roots: %v", c.Projects.Roots)
+	}
+	if strings.Join(c.ErrorPatterns.Dirs, ",") != "/etc/devspace-mcp/patterns" {
+		t.Errorf("unexpected error pattern dirs: %v", c.ErrorPatterns.Dirs)
+	}
+
+	env = map[string]string{"DEVSPACE_MCP_TOOL_TIMEOUTS": "devspace_deploy"}
+	if err := Default().ApplyEnv(lookup); err == nil || !strings.Contains(err.Error(), "DEVSPACE_MCP_TOOL_TIMEOUTS") {
+		t.Errorf("expected an error naming the variable, got %v", err)
+	}
+}
+
+func TestFlagsOverrideConfig(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse([]string{"--devspace-binary", "/ci/devspace", "--long-running-timeout=40m", "--namespace="}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(writeConfig(t, testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := flags.Apply(c); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if c.Binaries.Devspace != "/ci/devspace" {
+		t.Errorf("devspace binary = %q", c.Binaries.Devspace)
+	}
+	if c.Timeouts.LongRunning != 40*time.Minute {
+		t.Errorf("long running timeout = %v", c.Timeouts.LongRunning)
+	}
+	if c.Defaults.Namespace != "" {
+		t.Errorf("an explicit empty flag should clear the namespace, got %q", c.Defaults.Namespace)
+	}
+	if c.Defaults.KubeContext != "kind-dev" {
+		t.Errorf("unset flags should keep the file value, got %q", c.Defaults.KubeContext)
+	}
+
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	flags = RegisterFlags(fs)
+	_ = fs.Parse([]string{"--timeout", "later"})
+	if err := flags.Apply(Default()); err == nil || !strings.Contains(err.Error(), "--timeout") {
+		t.Errorf("expected an error naming the flag, got %v", err)
+	}
+}
+
+func TestToolCategories(t *testing.T) {
+	c, err := Load(writeConfig(t, "tools:\n  categories: [read, mutate]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(c.Tools.AllowedCategories(), ","); got != "read,mutate" {
+		t.Errorf("AllowedCategories() = %q", got)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse([]string{"--read-only"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := flags.Apply(c); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(c.Tools.AllowedCategories(), ","); got != CategoryRead {
+		t.Errorf("read-only mode should only allow the read category, got %q", got)
+	}
+
+	if _, err := Load(writeConfig(t, "tools:\n  categories: [admin]\n")); err == nil || !strings.Contains(err.Error(), `unknown tool category "admin"`) {
+		t.Errorf("expected an unknown category error, got %v", err)
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"time"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -14,6 +14,8 @@ import (
 func DevspaceAnalyzeTool() mcp.Tool {
 	return mcp.NewTool("devspace_analyze",
 		mcp.WithDescription("Analyze a Kubernetes namespace for potential problems and issues. Note: When everything is healthy, output may be minimal as DevSpace focuses on reporting problems."),
+		mcp.WithOutputSchema[commandOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace to analyze"),
 		),
@@ -33,7 +35,7 @@ func DevspaceAnalyzeTool() mcp.Tool {
 			mcp.Description("Timeout in seconds (default: 120, max: 600)"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 	)
 }
@@ -71,7 +73,7 @@ func DevspaceAnalyzeHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 		args = append(args, "--ignore-pod-restarts")
 	}
 
-	timeout := executor.DefaultTimeout
+	timeout := commandTimeout("devspace_analyze")
 	timeoutSec := req.GetInt("timeout", 0)
 	if timeoutSec > 0 {
 		// Cap timeout at 600 seconds (10 minutes)
@@ -84,11 +86,11 @@ func DevspaceAnalyzeHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 
 	workingDir := req.GetString("working_dir", "")
 
-	result := executor.ExecuteWithOptions(ctx, timeout, workingDir, args...)
+	result := executeDevspace(ctx, timeout, workingDir, args...)
 
 	if !result.Success() {
-		return mcp.NewToolResultError(EnhanceError(result)), nil
+		return errorResult(req, result), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	return mcp.NewToolResultStructured(newCommandOutput(args, result), result.FormatOutput()), nil
 }
@@ -3,7 +3,7 @@ package tools
 import (
 	"context"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -12,6 +12,8 @@ import (
 func DevspaceBuildTool() mcp.Tool {
 	return mcp.NewTool("devspace_build",
 		mcp.WithDescription("Build all images defined in devspace.yaml"),
+		mcp.WithOutputSchema[commandOutput](),
+		withCategory(serverconfig.CategoryMutate),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace"),
 		),
@@ -28,7 +30,7 @@ func DevspaceBuildTool() mcp.Tool {
 			mcp.Description("Tag to use for built images"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 	)
 }
@@ -68,11 +70,11 @@ func DevspaceBuildHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 	workingDir := req.GetString("working_dir", "")
 
 	// Build can take a while, use long running timeout
-	result := executor.ExecuteWithOptions(ctx, executor.LongRunningTimeout, workingDir, args...)
+	result := executeDevspaceStreaming(ctx, req, longRunningTimeout("devspace_build"), workingDir, args...)
 
 	if !result.Success() {
-		return mcp.NewToolResultError(EnhanceError(result)), nil
+		return errorResult(req, result), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	return mcp.NewToolResultStructured(newCommandOutput(args, result), result.FormatOutput()), nil
 }
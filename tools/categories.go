@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// categoryMetaKey is the tool _meta field holding the tool category
+const categoryMetaKey = "category"
+
+// withCategory records the category of a tool in its metadata and sets the
+// matching MCP annotations
+func withCategory(category string) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		if t.Meta == nil {
+			t.Meta = &mcp.Meta{}
+		}
+		if t.Meta.AdditionalFields == nil {
+			t.Meta.AdditionalFields = make(map[string]any)
+		}
+		t.Meta.AdditionalFields[categoryMetaKey] = category
+
+		t.Annotations.ReadOnlyHint = mcp.ToBoolPtr(category == serverconfig.CategoryRead)
+		t.Annotations.DestructiveHint = mcp.ToBoolPtr(category == serverconfig.CategoryDestructive || category == serverconfig.CategoryExec)
+	}
+}
+
+// toolCategory returns the category of a tool definition
+func toolCategory(tool mcp.Tool) string {
+	if tool.Meta != nil {
+		if category, ok := tool.Meta.AdditionalFields[categoryMetaKey].(string); ok {
+			return category
+		}
+	}
+	return ""
+}
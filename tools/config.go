@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/policy"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// serverConfig holds the binaries, timeouts, defaults and tool selection
+var serverConfig = serverconfig.Default()
+
+// SetConfig installs the server configuration. It must be called before
+// RegisterAll so the tool selection and defaults take effect.
+func SetConfig(c *serverconfig.Config) {
+	serverConfig = c
+	executor.SetBinaryPath(executor.DevspaceBinary, c.Binaries.Devspace)
+	executor.SetBinaryPath(executor.KubectlBinary, c.Binaries.Kubectl)
+}
+
+// commandTimeout returns the timeout of the short commands run by a tool
+func commandTimeout(tool string) time.Duration {
+	return serverConfig.Timeout(tool, false)
+}
+
+// longRunningTimeout returns the timeout of build, deploy and similar
+// commands run by a tool
+func longRunningTimeout(tool string) time.Duration {
+	return serverConfig.Timeout(tool, true)
+}
+
+// toolSelected reports whether the configuration registers the tool.
+// Disabled tools are skipped and enabled tools registered regardless of
+// their category.
+func toolSelected(tool mcp.Tool) bool {
+	selection := serverConfig.Tools
+	if matchesAny(selection.Disable, tool.Name) {
+		return false
+	}
+	if matchesAny(selection.Enable, tool.Name) {
+		return true
+	}
+	if categories := selection.AllowedCategories(); len(categories) > 0 {
+		return slices.Contains(categories, toolCategory(tool))
+	}
+	return len(selection.Enable) == 0
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if policy.Match(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredDefaults returns the configured parameter defaults by parameter name
+func configuredDefaults() map[string]string {
+	d := serverConfig.Defaults
+	return map[string]string{
+		"working_dir":  d.WorkingDir,
+		"namespace":    d.Namespace,
+		"kube_context": d.KubeContext,
+		"profile":      d.Profile,
+	}
+}
+
+// limitOutput wraps a handler so text longer than the configured maximum is
+// truncated. The structured content shares one budget of the same size and
+// is marked with "truncated" when it had to be shortened.
+func limitOutput(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		limit := serverConfig.MaxOutputBytes
+		if result == nil || err != nil || limit <= 0 {
+			return result, err
+		}
+
+		for i, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				text.Text = truncateMiddle(text.Text, limit)
+				result.Content[i] = text
+			}
+		}
+		if result.StructuredContent != nil {
+			if data, err := json.Marshal(result.StructuredContent); err == nil && len(data) > limit {
+				var content any
+				if json.Unmarshal(data, &content) == nil {
+					content = limitStructured(content, limit)
+					if m, ok := content.(map[string]any); ok {
+						m["truncated"] = true
+					}
+					result.StructuredContent = content
+					if result.Meta == nil {
+						result.Meta = &mcp.Meta{}
+					}
+					if result.Meta.AdditionalFields == nil {
+						result.Meta.AdditionalFields = make(map[string]any)
+					}
+					result.Meta.AdditionalFields["truncated"] = true
+				}
+			}
+		}
+		return result, nil
+	}
+}
+
+// limitStructured shrinks a decoded JSON value to about limit bytes. Long
+// strings are cut to a common length first, so one large field cannot use
+// up the budget of the others. If that is not enough, the longest arrays
+// lose their middle elements.
+func limitStructured(content any, limit int) any {
+	var lengths []int
+	mapStrings(content, func(s string) string {
+		lengths = append(lengths, len(s))
+		return s
+	})
+	total := 0
+	for _, n := range lengths {
+		total += n
+	}
+	size := jsonSize(content)
+	if share := stringShare(lengths, limit-(size-total)); share >= 0 {
+		content = mapStrings(content, func(s string) string {
+			// The marker would make short strings longer
+			if t := truncateMiddle(s, share); len(t) < len(s) {
+				return t
+			}
+			return s
+		})
+	}
+
+	for jsonSize(content) > limit {
+		var longest *arrayRef
+		for _, ref := range collectArrays(content, func(v []any) { content = v }) {
+			if len(ref.items) > 1 && (longest == nil || len(ref.items) > len(longest.items)) {
+				longest = &ref
+			}
+		}
+		if longest == nil {
+			break
+		}
+		n := len(longest.items)
+		keep := n / 4
+		longest.set(append(longest.items[:keep:keep], longest.items[n-keep:]...))
+	}
+	return content
+}
+
+// stringShare returns the length every string is cut to so all strings fit
+// in budget bytes, or -1 if they already fit
+func stringShare(lengths []int, budget int) int {
+	total := 0
+	for _, n := range lengths {
+		total += n
+	}
+	if total <= budget {
+		return -1
+	}
+	sorted := slices.Clone(lengths)
+	slices.Sort(sorted)
+	remaining := max(budget, 0)
+	for i, n := range sorted {
+		share := remaining / (len(sorted) - i)
+		if n > share {
+			return share
+		}
+		remaining -= n
+	}
+	return 0
+}
+
+// arrayRef is an array inside a decoded JSON value and a way to replace it
+type arrayRef struct {
+	items []any
+	set   func([]any)
+}
+
+// collectArrays returns every array of a decoded JSON value. set replaces v
+// itself.
+func collectArrays(v any, set func([]any)) []arrayRef {
+	var refs []arrayRef
+	switch v := v.(type) {
+	case []any:
+		refs = append(refs, arrayRef{v, set})
+		for i := range v {
+			refs = append(refs, collectArrays(v[i], func(items []any) { v[i] = items })...)
+		}
+	case map[string]any:
+		for k := range v {
+			refs = append(refs, collectArrays(v[k], func(items []any) { v[k] = items })...)
+		}
+	}
+	return refs
+}
+
+// jsonSize returns the length of the JSON encoding of v
+func jsonSize(v any) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// truncateMiddle shortens text to about limit bytes, keeping its beginning
+// and end where table headers and error messages are
+func truncateMiddle(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	head, tail := limit/2, len(text)-limit/2
+	for head > 0 && !utf8.RuneStart(text[head]) {
+		head--
+	}
+	for tail < len(text) && !utf8.RuneStart(text[tail]) {
+		tail++
+	}
+	return fmt.Sprintf("%s\n\n... [%d bytes truncated] ...\n\n%s", text[:head], tail-head, text[tail:])
+}
+
+// mapStrings replaces every string of a decoded JSON value with fn's result
+func mapStrings(v any, fn func(string) string) any {
+	switch v := v.(type) {
+	case string:
+		return fn(v)
+	case []any:
+		for i := range v {
+			v[i] = mapStrings(v[i], fn)
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = mapStrings(v[k], fn)
+		}
+	}
+	return v
+}
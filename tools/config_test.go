@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/policy"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// useConfig installs a configuration and registers all tools on a fresh server
+func useConfig(t *testing.T, c *serverconfig.Config) (*server.MCPServer, *executor.FakeRunner) {
+	t.Helper()
+	fake := executor.NewFakeRunner()
+	previous := serverConfig
+	t.Cleanup(func() { SetConfig(previous) })
+
+	SetConfig(c)
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	RegisterAll(s, fake)
+	return s, fake
+}
+
+func TestConfigSelectsTools(t *testing.T) {
+	c := serverconfig.Default()
+	c.Tools.Enable = []string{"devspace_list_*", "devspace_logs", "devspace_purge"}
+	c.Tools.Disable = []string{"devspace_purge"}
+	s, _ := useConfig(t, c)
+
+	var names []string
+	for name := range s.ListTools() {
+		names = append(names, name)
+	}
+	if len(names) != 10 {
+		t.Errorf("expected 10 tools, got %d: %v", len(names), names)
+	}
+	for _, name := range []string{"devspace_list_pods", "devspace_logs"} {
+		if s.GetTool(name) == nil {
+			t.Errorf("tool %s should be registered", name)
+		}
+	}
+	for _, name := range []string{"devspace_purge", "devspace_deploy"} {
+		if s.GetTool(name) != nil {
+			t.Errorf("tool %s should not be registered", name)
+		}
+	}
+}
+
+func TestConfigDefaultsAndTimeouts(t *testing.T) {
+	c := serverconfig.Default()
+	dir := projectDir(t, "devspace.yaml")
+	c.Defaults = serverconfig.Defaults{WorkingDir: dir, Namespace: "dev", KubeContext: "kind-dev", Profile: "ci"}
+	c.Timeouts.Tools["devspace_deploy"] = 30 * time.Minute
+	s, fake := useConfig(t, c)
+	fake.On("devspace", []string{"deploy"}, executor.Result{Stdout: "deployed"})
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: "NAME\n"})
+
+	if _, err := s.GetTool("devspace_deploy").Handler(context.Background(), newRequest(map[string]any{"namespace": "team-a"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetTool("devspace_list_pods").Handler(context.Background(), newRequest(map[string]any{})); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	deploy := strings.Join(calls[0].Args, " ")
+	for _, want := range []string{"--namespace team-a", "--kube-context kind-dev", "--profile ci"} {
+		if !strings.Contains(deploy, want) {
+			t.Errorf("deploy args %q should contain %q", deploy, want)
+		}
+	}
+	if calls[0].Dir != dir {
+		t.Errorf("deploy dir = %q", calls[0].Dir)
+	}
+	if calls[0].Timeout != 30*time.Minute {
+		t.Errorf("deploy timeout = %v", calls[0].Timeout)
+	}
+	if pods := strings.Join(calls[1].Args, " "); !strings.Contains(pods, "-n dev") && !strings.Contains(pods, "--namespace dev") {
+		t.Errorf("list pods args %q should use the default namespace", pods)
+	}
+	if calls[1].Timeout != executor.DefaultTimeout {
+		t.Errorf("list pods timeout = %v", calls[1].Timeout)
+	}
+}
+
+func TestConfigLimitsOutput(t *testing.T) {
+	c := serverconfig.Default()
+	c.MaxOutputBytes = 100
+	s, fake := useConfig(t, c)
+	fake.On("devspace", []string{"logs"}, executor.Result{Stdout: "first line\n" + strings.Repeat("x", 500) + "\nlast line\n"})
+
+	result, err := s.GetTool("devspace_logs").Handler(context.Background(), newRequest(map[string]any{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := resultText(result)
+	if !strings.HasPrefix(text, "first line") || !strings.Contains(text, "last line") || !strings.Contains(text, "bytes truncated") {
+		t.Errorf("unexpected text: %q", text)
+	}
+	// The effective targets are appended after the limit is applied
+	if body, _, _ := strings.Cut(text, "\n\n🎯 Target:"); len(body) > 200 {
+		t.Errorf("text is %d bytes", len(body))
+	}
+}
+
+func TestConfigLimitsStructuredOutput(t *testing.T) {
+	c := serverconfig.Default()
+	c.MaxOutputBytes = 500
+	s, fake := useConfig(t, c)
+	var lines []string
+	for i := range 200 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	fake.On("devspace", []string{"logs"}, executor.Result{Stdout: strings.Join(lines, "\n")})
+
+	result, err := s.GetTool("devspace_logs").Handler(context.Background(), newRequest(map[string]any{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(result.StructuredContent)
+	if len(data) > 500 {
+		t.Errorf("structured content is %d bytes: %s", len(data), data)
+	}
+	structured := result.StructuredContent.(map[string]any)
+	got := structured["lines"].([]any)
+	if structured["truncated"] != true || got[0] != "line 0" || got[len(got)-1] != "line 199" {
+		t.Errorf("unexpected structured content: %s", data)
+	}
+	if result.Meta == nil || result.Meta.AdditionalFields["truncated"] != true {
+		t.Errorf("expected truncated in the metadata, got %+v", result.Meta)
+	}
+}
+
+func TestLimitStructuredSharesBudget(t *testing.T) {
+	content := map[string]any{
+		"output": strings.Repeat("a", 5000),
+		"config": strings.Repeat("b", 5000),
+		"source": "cli",
+	}
+	got := limitStructured(content, 1000).(map[string]any)
+	if size := jsonSize(got); size > 1100 {
+		t.Errorf("content is %d bytes", size)
+	}
+	if got["source"] != "cli" {
+		t.Errorf("short fields should be kept, got %v", got["source"])
+	}
+	for _, key := range []string{"output", "config"} {
+		if s := got[key].(string); !strings.Contains(s, "bytes truncated") || len(s) < 400 {
+			t.Errorf("%s should keep its share of the budget, got %d bytes", key, len(s))
+		}
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	if got := truncateMiddle("short", 10); got != "short" {
+		t.Errorf("short text should be kept, got %q", got)
+	}
+	got := truncateMiddle("ééééé|ééééé", 9)
+	if !strings.HasPrefix(got, "éé\n") || !strings.HasSuffix(got, "\néé") || !strings.Contains(got, "[13 bytes truncated]") {
+		t.Errorf("unexpected truncation: %q", got)
+	}
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	c := serverconfig.Default()
+	c.Tools.ReadOnly = true
+	s, _ := useConfig(t, c)
+
+	for _, name := range []string{
+		"devspace_version", "devspace_list_namespaces", "devspace_list_contexts", "devspace_list_deployments",
+		"devspace_list_profiles", "devspace_list_vars", "devspace_print", "devspace_analyze", "devspace_logs",
+		"devspace_list_pods", "devspace_status", "devspace_list_ports", "devspace_server_info",
+	} {
+		if s.GetTool(name) == nil {
+			t.Errorf("tool %s should be registered in read-only mode", name)
+		}
+	}
+	for _, name := range []string{"devspace_build", "devspace_deploy", "devspace_purge", "devspace_run", "devspace_exec", "devspace_sync", "devspace_dev_start"} {
+		if s.GetTool(name) != nil {
+			t.Errorf("tool %s should not be registered in read-only mode", name)
+		}
+	}
+}
+
+func TestCategoryFilterWithAllowList(t *testing.T) {
+	c := serverconfig.Default()
+	c.Tools.Categories = []string{serverconfig.CategoryRead, serverconfig.CategoryMutate}
+	c.Tools.Enable = []string{"devspace_exec"}
+	c.Tools.Disable = []string{"devspace_sync"}
+	s, _ := useConfig(t, c)
+
+	for name, want := range map[string]bool{
+		"devspace_logs":   true,
+		"devspace_deploy": true,
+		"devspace_exec":   true,
+		"devspace_sync":   false,
+		"devspace_purge":  false,
+		"devspace_run":    false,
+	} {
+		if got := s.GetTool(name) != nil; got != want {
+			t.Errorf("tool %s registered = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestServerInfo(t *testing.T) {
+	c := serverconfig.Default()
+	c.Tools.ReadOnly = true
+	c.Defaults.Namespace = "dev"
+	s, _ := useConfig(t, c)
+	p, err := policy.Parse([]byte("rules:\n  - name: prod\n    namespaces: [prod-*]\n    confirm: [logs]\n    confirm_token: secret-token\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousPolicy := activePolicy
+	t.Cleanup(func() { activePolicy = previousPolicy })
+	SetPolicy(p)
+
+	result, err := s.GetTool("devspace_server_info").Handler(context.Background(), newRequest(map[string]any{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, ok := result.StructuredContent.(serverInfoOutput)
+	if !ok {
+		t.Fatalf("unexpected structured content %T", result.StructuredContent)
+	}
+	if !info.ReadOnly || len(info.Categories) != 1 || info.Categories[0] != serverconfig.CategoryRead {
+		t.Errorf("unexpected selection: %+v", info)
+	}
+	registered := map[string]bool{}
+	for _, tool := range info.Tools {
+		registered[tool.Name] = tool.Registered
+	}
+	if !registered["devspace_logs"] || registered["devspace_deploy"] || len(info.Tools) != len(toolStatuses) {
+		t.Errorf("unexpected tool statuses: %+v", info.Tools)
+	}
+	if len(info.PolicyRules) != 1 || info.PolicyRules[0].Name != "prod" {
+		t.Errorf("unexpected policy rules: %+v", info.PolicyRules)
+	}
+
+	text := resultText(result)
+	for _, want := range []string{"Mode: read-only", "🚫 devspace_deploy (mutate)", "✅ devspace_logs (read)", "- prod: contexts *, namespaces prod-*", "namespace: dev"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text should contain %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "secret-token") {
+		t.Error("the confirmation token must not be reported")
+	}
+}
@@ -0,0 +1,323 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// targetParams are the parameters resolved from the call, the session context
+// and the configured defaults, in the order they are reported
+var targetParams = []string{"working_dir", "namespace", "kube_context", "profile"}
+
+// Sources of an effective parameter value
+const (
+	sourceArgument = "argument"
+	sourceSession  = "session"
+	sourceConfig   = "config"
+	sourceUnset    = "unset"
+)
+
+// effectiveMetaKey is the result _meta field holding the effective values
+const effectiveMetaKey = "effective"
+
+// contextStore holds the target parameters set by each client session
+type contextStore struct {
+	mu       sync.Mutex
+	sessions map[string]map[string]string
+}
+
+// sessionContexts is the context store of all client sessions
+var sessionContexts = &contextStore{sessions: make(map[string]map[string]string)}
+
+// get returns a copy of the values of a session
+func (s *contextStore) get(id string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string]string, len(targetParams))
+	for name, value := range s.sessions[id] {
+		values[name] = value
+	}
+	return values
+}
+
+// update merges values into the context of a session, clearing it first if
+// requested. Empty values are ignored.
+func (s *contextStore) update(id string, values map[string]string, clear bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.sessions[id]
+	if current == nil || clear {
+		current = make(map[string]string, len(targetParams))
+		s.sessions[id] = current
+	}
+	for name, value := range values {
+		if value != "" {
+			current[name] = value
+		}
+	}
+}
+
+// remove forgets the context of a session
+func (s *contextStore) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+// RegisterHooks adds the hooks the tools need to the server hooks. The
+// context of a client session is dropped when the session ends.
+func RegisterHooks(hooks *server.Hooks) {
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		sessionContexts.remove(session.SessionID())
+	})
+}
+
+// sessionID returns the ID of the client session making a call, or "" when
+// the call is not part of a session
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// effectiveValue is the value a parameter resolved to and where it came from
+type effectiveValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source" jsonschema:"enum=argument,enum=session,enum=config,enum=unset"`
+}
+
+// resolveTargets returns the effective value of every target parameter the
+// tool accepts: the call argument, else the session context, else the
+// configured default
+func resolveTargets(ctx context.Context, tool mcp.Tool, req mcp.CallToolRequest) map[string]effectiveValue {
+	session := sessionContexts.get(sessionID(ctx))
+	configured := configuredDefaults()
+
+	effective := make(map[string]effectiveValue)
+	for _, name := range targetParams {
+		if _, accepted := tool.InputSchema.Properties[name]; !accepted {
+			continue
+		}
+		effective[name] = resolveTarget(req.GetString(name, ""), session[name], configured[name])
+	}
+	return effective
+}
+
+// resolveTarget picks the first non-empty of an argument, session and
+// configured value
+func resolveTarget(argument, session, configured string) effectiveValue {
+	switch {
+	case argument != "":
+		return effectiveValue{argument, sourceArgument}
+	case session != "":
+		return effectiveValue{session, sourceSession}
+	case configured != "":
+		return effectiveValue{configured, sourceConfig}
+	default:
+		return effectiveValue{"", sourceUnset}
+	}
+}
+
+// withDefaults fills in working_dir, namespace, kube_context and profile from
+// the session context or the configured defaults when a call to a tool
+// accepting them leaves them empty. The effective values are appended to the
+// result so the client knows what the call targeted.
+func withDefaults(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		effective := resolveTargets(ctx, tool, req)
+		if len(effective) == 0 {
+			return handler(ctx, req)
+		}
+
+		args := make(map[string]any, len(req.GetArguments())+len(effective))
+		for k, v := range req.GetArguments() {
+			args[k] = v
+		}
+		for name, e := range effective {
+			if e.Source == sourceSession || e.Source == sourceConfig {
+				args[name] = e.Value
+			}
+		}
+		req.Params.Arguments = args
+
+		result, err := handler(ctx, req)
+		if result != nil && err == nil {
+			echoTargets(result, effective)
+		}
+		return result, err
+	}
+}
+
+// echoTargets appends the effective target parameters to a result
+func echoTargets(result *mcp.CallToolResult, effective map[string]effectiveValue) {
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			text.Text += "\n\n🎯 Target: " + formatTargets(effective)
+			result.Content[i] = text
+			break
+		}
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[effectiveMetaKey] = effective
+}
+
+// formatTargets renders effective values as "name=value (source)" pairs
+func formatTargets(effective map[string]effectiveValue) string {
+	var parts []string
+	for _, name := range targetParams {
+		e, ok := effective[name]
+		if !ok {
+			continue
+		}
+		if e.Source == sourceUnset {
+			parts = append(parts, name+" not set")
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s (%s)", name, e.Value, e.Source))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// managesContext reports whether the tool reads or writes the session
+// context itself, so its parameters must not be filled from it
+func managesContext(tool mcp.Tool) bool {
+	return tool.Name == "devspace_set_context" || tool.Name == "devspace_get_context"
+}
+
+// contextOutput is the result of devspace_set_context and devspace_get_context
+type contextOutput struct {
+	Session   serverconfig.Defaults     `json:"session" jsonschema:"description=Values set with devspace_set_context in this client session"`
+	Config    serverconfig.Defaults     `json:"config" jsonschema:"description=Defaults from the server configuration"`
+	Effective map[string]effectiveValue `json:"effective" jsonschema:"description=Value used when a call omits the parameter and where it comes from"`
+}
+
+// DevspaceSetContextTool returns the tool definition for setting session defaults
+func DevspaceSetContextTool() mcp.Tool {
+	return mcp.NewTool("devspace_set_context",
+		mcp.WithDescription("Set the working_dir, namespace, kube_context and profile used by all tools of this session when a call omits them. Explicit arguments still take precedence. Omitted values are kept unless clear is set."),
+		mcp.WithOutputSchema[contextOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+		mcp.WithString("project",
+			mcp.Description("Name of a project listed by devspace_list_projects; sets working_dir to its directory"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context"),
+		),
+		mcp.WithString("profile",
+			mcp.Description("DevSpace profile"),
+		),
+		mcp.WithBoolean("clear",
+			mcp.Description("Remove all values of this session before setting the given ones"),
+		),
+	)
+}
+
+// DevspaceSetContextHandler handles setting session defaults
+func DevspaceSetContextHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := sessionID(ctx)
+	if id == "" {
+		return mcp.NewToolResultError("no client session: the context can only be set over a session-based transport"), nil
+	}
+
+	values := make(map[string]string, len(targetParams))
+	for _, name := range targetParams {
+		value := req.GetString(name, "")
+		if err := ValidateStringParam(name, value); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		values[name] = value
+	}
+	if name := req.GetString("project", ""); name != "" {
+		if values["working_dir"] != "" {
+			return mcp.NewToolResultError("pass either project or working_dir, not both"), nil
+		}
+		p, err := lookupProject(name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		values["working_dir"] = p.Dir
+	}
+	if dir := values["working_dir"]; dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return mcp.NewToolResultError(fmt.Sprintf("working_dir %s is not a directory", dir)), nil
+		}
+	}
+
+	sessionContexts.update(id, values, req.GetBool("clear", false))
+	out := describeContext(ctx)
+	return mcp.NewToolResultStructured(out, "Session context updated\n\n"+formatContext(out)), nil
+}
+
+// DevspaceGetContextTool returns the tool definition for showing session defaults
+func DevspaceGetContextTool() mcp.Tool {
+	return mcp.NewTool("devspace_get_context",
+		mcp.WithDescription("Show the working_dir, namespace, kube_context and profile used when a call omits them, and whether each comes from this session or the server configuration"),
+		mcp.WithOutputSchema[contextOutput](),
+		withCategory(serverconfig.CategoryRead),
+	)
+}
+
+// DevspaceGetContextHandler handles showing session defaults
+func DevspaceGetContextHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	out := describeContext(ctx)
+	return mcp.NewToolResultStructured(out, formatContext(out)), nil
+}
+
+// describeContext returns the session, configured and effective defaults of
+// the calling session
+func describeContext(ctx context.Context) contextOutput {
+	session := sessionContexts.get(sessionID(ctx))
+	out := contextOutput{
+		Session: serverconfig.Defaults{
+			WorkingDir:  session["working_dir"],
+			Namespace:   session["namespace"],
+			KubeContext: session["kube_context"],
+			Profile:     session["profile"],
+		},
+		Config:    serverConfig.Defaults,
+		Effective: make(map[string]effectiveValue, len(targetParams)),
+	}
+
+	configured := configuredDefaults()
+	for _, name := range targetParams {
+		out.Effective[name] = resolveTarget("", session[name], configured[name])
+	}
+	return out
+}
+
+// formatContext renders the effective defaults as text
+func formatContext(out contextOutput) string {
+	var b strings.Builder
+	b.WriteString("# Session Context\n\n")
+	for _, name := range targetParams {
+		e := out.Effective[name]
+		if e.Source == sourceUnset {
+			fmt.Fprintf(&b, "%s: (not set)\n", name)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s (%s)\n", name, e.Value, e.Source)
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// testSession is a client session identified only by its ID
+type testSession struct{ id string }
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return s.id }
+
+// sessionContext returns a context carrying a client session with the given ID
+func sessionContext(s *server.MCPServer, id string) context.Context {
+	return s.WithContext(context.Background(), testSession{id})
+}
+
+func TestSessionContextResolution(t *testing.T) {
+	c := serverconfig.Default()
+	c.Defaults.Namespace = "shared"
+	s, fake := useConfig(t, c)
+	t.Cleanup(func() { sessionContexts.remove("a"); sessionContexts.remove("b") })
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: "NAME READY\n"})
+
+	alice := sessionContext(s, "a")
+	result, _ := s.GetTool("devspace_set_context").Handler(alice, newRequest(map[string]any{"namespace": "alice"}))
+	if result.IsError {
+		t.Fatalf("set context failed: %s", resultText(result))
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		args      map[string]any
+		namespace string
+		source    string
+	}{
+		{"session default", alice, map[string]any{}, "alice", sourceSession},
+		{"explicit argument", alice, map[string]any{"namespace": "other"}, "other", sourceArgument},
+		{"config default", sessionContext(s, "b"), map[string]any{}, "shared", sourceConfig},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.GetTool("devspace_list_pods").Handler(tt.ctx, newRequest(tt.args))
+			if err != nil || result.IsError {
+				t.Fatalf("list pods failed: %v %s", err, resultText(result))
+			}
+			if args := strings.Join(fake.Calls()[i].Args, " "); !strings.Contains(args, "-n "+tt.namespace) {
+				t.Errorf("args %q should target namespace %s", args, tt.namespace)
+			}
+			if want := "namespace=" + tt.namespace + " (" + tt.source + ")"; !strings.Contains(resultText(result), want) {
+				t.Errorf("result should echo %q, got %s", want, resultText(result))
+			}
+			effective := result.Meta.AdditionalFields[effectiveMetaKey].(map[string]effectiveValue)
+			if got := effective["namespace"]; got != (effectiveValue{tt.namespace, tt.source}) {
+				t.Errorf("effective namespace = %+v", got)
+			}
+		})
+	}
+}
+
+func TestSessionKubeContextReachesClusterTools(t *testing.T) {
+	s, fake := useConfig(t, serverconfig.Default())
+	t.Cleanup(func() { sessionContexts.remove("a") })
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: "NAME READY\n"})
+	fake.On("devspace", nil, executor.Result{})
+	ctx := sessionContext(s, "a")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "devspace.yaml"), []byte("version: v2beta1\nname: shop\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s.GetTool("devspace_set_context").Handler(ctx, newRequest(map[string]any{"working_dir": dir, "namespace": "dev", "kube_context": "kind-dev"}))
+
+	tests := []struct {
+		tool string
+		args map[string]any
+		flag string
+	}{
+		{"devspace_exec", map[string]any{"command": "ls"}, "--kube-context kind-dev"},
+		{"devspace_list_pods", map[string]any{}, "--context kind-dev"},
+		{"devspace_status", map[string]any{}, "--kube-context kind-dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			before := len(fake.Calls())
+			result, err := s.GetTool(tt.tool).Handler(ctx, newRequest(tt.args))
+			if err != nil || result.IsError {
+				t.Fatalf("call failed: %v %s", err, resultText(result))
+			}
+			if args := strings.Join(fake.Calls()[before].Args, " "); !strings.Contains(args, tt.flag) {
+				t.Errorf("args %q should use the session context", args)
+			}
+			if !strings.Contains(resultText(result), "kube_context=kind-dev (session)") {
+				t.Errorf("result should echo the session context, got %s", resultText(result))
+			}
+		})
+	}
+}
+
+func TestSetContext(t *testing.T) {
+	s, _ := useConfig(t, serverconfig.Default())
+	t.Cleanup(func() { sessionContexts.remove("a") })
+	ctx := sessionContext(s, "a")
+	dir := t.TempDir()
+
+	call := func(ctx context.Context, tool string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := s.GetTool(tool).Handler(ctx, newRequest(args))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	call(ctx, "devspace_set_context", map[string]any{"working_dir": dir, "namespace": "dev"})
+	call(ctx, "devspace_set_context", map[string]any{"profile": "debug"})
+	out := call(ctx, "devspace_get_context", map[string]any{}).StructuredContent.(contextOutput)
+	if out.Session != (serverconfig.Defaults{WorkingDir: dir, Namespace: "dev", Profile: "debug"}) {
+		t.Errorf("session context = %+v", out.Session)
+	}
+	if out.Effective["kube_context"].Source != sourceUnset {
+		t.Errorf("kube_context = %+v, want unset", out.Effective["kube_context"])
+	}
+
+	call(ctx, "devspace_set_context", map[string]any{"clear": true, "namespace": "prod"})
+	out = call(ctx, "devspace_get_context", map[string]any{}).StructuredContent.(contextOutput)
+	if out.Session != (serverconfig.Defaults{Namespace: "prod"}) {
+		t.Errorf("session context after clear = %+v", out.Session)
+	}
+
+	for name, args := range map[string]map[string]any{
+		"flag injection":    {"namespace": "--all"},
+		"missing directory": {"working_dir": filepath.Join(dir, "missing")},
+	} {
+		if result := call(ctx, "devspace_set_context", args); !result.IsError {
+			t.Errorf("%s: expected an error, got %s", name, resultText(result))
+		}
+	}
+	if result := call(context.Background(), "devspace_set_context", map[string]any{"namespace": "dev"}); !result.IsError {
+		t.Error("setting the context without a session should fail")
+	}
+}
+
+func TestUnregisterSessionDropsContext(t *testing.T) {
+	hooks := &server.Hooks{}
+	RegisterHooks(hooks)
+	s := server.NewMCPServer("test", "0.0.0", server.WithHooks(hooks))
+	ctx := context.Background()
+	if err := s.RegisterSession(ctx, testSession{"gone"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessionContexts.update("gone", map[string]string{"namespace": "dev"}, false)
+	s.UnregisterSession(ctx, "gone")
+	if values := sessionContexts.get("gone"); len(values) != 0 {
+		t.Errorf("context should be dropped, got %v", values)
+	}
+}
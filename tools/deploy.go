@@ -3,7 +3,7 @@ package tools
 import (
 	"context"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -12,6 +12,8 @@ import (
 func DevspaceDeployTool() mcp.Tool {
 	return mcp.NewTool("devspace_deploy",
 		mcp.WithDescription("Deploy the project to Kubernetes using devspace"),
+		mcp.WithOutputSchema[commandOutput](),
+		withCategory(serverconfig.CategoryMutate),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace to deploy to"),
 		),
@@ -31,7 +33,7 @@ func DevspaceDeployTool() mcp.Tool {
 			mcp.Description("Skip building images"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 	)
 }
@@ -71,11 +73,11 @@ func DevspaceDeployHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.C
 	workingDir := req.GetString("working_dir", "")
 
 	// Deploy can take a while, use long running timeout
-	result := executor.ExecuteWithOptions(ctx, executor.LongRunningTimeout, workingDir, args...)
+	result := executeDevspaceStreaming(ctx, req, longRunningTimeout("devspace_deploy"), workingDir, args...)
 
 	if !result.Success() {
-		return mcp.NewToolResultError(EnhanceError(result)), nil
+		return errorResult(req, result), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	return mcp.NewToolResultStructured(newCommandOutput(args, result), result.FormatOutput()), nil
 }
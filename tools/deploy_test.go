@@ -0,0 +1,79 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func TestDevspaceDeployHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"deploy"}, executor.Result{Stdout: "Successfully deployed api"})
+
+	result, err := DevspaceDeployHandler(ctx, newRequest(map[string]any{
+		"namespace":    "dev",
+		"kube_context": "kind-dev",
+		"profile":      "local",
+		"force_deploy": true,
+		"working_dir":  "/work/api",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", resultText(result))
+	}
+	if !strings.Contains(resultText(result), "Successfully deployed api") {
+		t.Errorf("expected deploy output in result, got %q", resultText(result))
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 invocation, got %d", len(calls))
+	}
+	want := []string{"deploy", "--namespace", "dev", "--kube-context", "kind-dev", "--profile", "local", "--force-deploy"}
+	if !reflect.DeepEqual(calls[0].Args, want) {
+		t.Errorf("args = %v, want %v", calls[0].Args, want)
+	}
+	if calls[0].Dir != "/work/api" {
+		t.Errorf("dir = %q, want /work/api", calls[0].Dir)
+	}
+	if calls[0].Timeout != executor.LongRunningTimeout {
+		t.Errorf("timeout = %v, want %v", calls[0].Timeout, executor.LongRunningTimeout)
+	}
+}
+
+func TestDevspaceDeployHandlerFailure(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"deploy"}, executor.Result{
+		Stderr:   "Unable to connect to the server: dial tcp: i/o timeout",
+		ExitCode: 1,
+	})
+
+	result, err := DevspaceDeployHandler(ctx, newRequest(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	if !strings.Contains(resultText(result), "VPN connection") {
+		t.Errorf("expected enhanced error, got %q", resultText(result))
+	}
+}
+
+func TestDevspaceDeployHandlerRejectsFlagInjection(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+
+	result, _ := DevspaceDeployHandler(ctx, newRequest(map[string]any{
+		"namespace": "--all",
+	}))
+	if !result.IsError {
+		t.Fatal("expected validation error")
+	}
+	if len(fake.Calls()) != 0 {
+		t.Error("devspace should not be invoked when validation fails")
+	}
+}
@@ -0,0 +1,493 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// devOutputLines is the number of output lines retained per dev session
+const devOutputLines = 5000
+
+// devStartupWait is how long devspace_dev_start waits to catch a session
+// that fails immediately (bad config, unreachable cluster)
+var devStartupWait = 3 * time.Second
+
+// startProcess launches supervised background processes
+var startProcess = executor.StartBackground
+
+// devSession is a devspace dev process started by this server
+type devSession struct {
+	ID          string
+	WorkingDir  string
+	Namespace   string
+	KubeContext string
+	Profile     string
+	Args        []string
+	Env         []string
+	proc        *executor.Background
+}
+
+// state describes whether the session is still running
+func (d *devSession) state() string {
+	if d.proc.Running() {
+		return "running"
+	}
+	if d.proc.Stopped() {
+		return "stopped"
+	}
+	if err := d.proc.Err(); err != nil {
+		return fmt.Sprintf("failed (%v)", err)
+	}
+	return fmt.Sprintf("exited (code %d)", d.proc.ExitCode())
+}
+
+// devSessionInfo is the structured form of a dev session
+type devSessionInfo struct {
+	ID           string    `json:"id"`
+	State        string    `json:"state"`
+	PID          int       `json:"pid"`
+	WorkingDir   string    `json:"working_dir"`
+	Namespace    string    `json:"namespace,omitempty"`
+	KubeContext  string    `json:"kube_context,omitempty"`
+	Profile      string    `json:"profile,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	OutputCursor int64     `json:"output_cursor"`
+}
+
+// info returns the structured form of the session
+func (d *devSession) info() devSessionInfo {
+	return devSessionInfo{
+		ID:           d.ID,
+		State:        d.state(),
+		PID:          d.proc.Pid(),
+		WorkingDir:   d.WorkingDir,
+		Namespace:    d.Namespace,
+		KubeContext:  d.KubeContext,
+		Profile:      d.Profile,
+		StartedAt:    d.proc.StartedAt(),
+		OutputCursor: d.proc.Output().Next(),
+	}
+}
+
+// describe renders a short human readable summary of the session
+func (d *devSession) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session: %s\n", d.ID)
+	fmt.Fprintf(&b, "State: %s\n", d.state())
+	fmt.Fprintf(&b, "PID: %d\n", d.proc.Pid())
+	fmt.Fprintf(&b, "Working directory: %s\n", displayDir(d.WorkingDir))
+	if d.Namespace != "" {
+		fmt.Fprintf(&b, "Namespace: %s\n", d.Namespace)
+	}
+	if d.KubeContext != "" {
+		fmt.Fprintf(&b, "Kube context: %s\n", d.KubeContext)
+	}
+	if d.Profile != "" {
+		fmt.Fprintf(&b, "Profile: %s\n", d.Profile)
+	}
+	fmt.Fprintf(&b, "Started: %s (%s ago)\n", d.proc.StartedAt().Format(time.RFC3339), time.Since(d.proc.StartedAt()).Round(time.Second))
+	fmt.Fprintf(&b, "Output cursor: %d\n", d.proc.Output().Next())
+	return b.String()
+}
+
+// devSessionManager tracks the dev sessions started by this server
+type devSessionManager struct {
+	mu       sync.Mutex
+	sessions map[string]*devSession
+	nextID   int
+}
+
+// devSessions holds all dev sessions of the running server
+var devSessions = newDevSessionManager()
+
+func newDevSessionManager() *devSessionManager {
+	return &devSessionManager{sessions: make(map[string]*devSession)}
+}
+
+// start launches a dev session unless one is already running for the same
+// working directory and namespace
+func (m *devSessionManager) start(session *devSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, existing := range m.sessions {
+		if existing.WorkingDir != session.WorkingDir || existing.Namespace != session.Namespace {
+			continue
+		}
+		if existing.proc.Running() {
+			return fmt.Errorf("dev session %s is already running for %s (namespace %q); stop it first with devspace_dev_stop",
+				id, displayDir(existing.WorkingDir), existing.Namespace)
+		}
+		delete(m.sessions, id)
+	}
+
+	proc, err := startProcess(executor.Command{
+		Binary: executor.DevspaceBinary,
+		Args:   session.Args,
+		Dir:    session.WorkingDir,
+		Env:    session.Env,
+	}, devOutputLines)
+	if err != nil {
+		return fmt.Errorf("failed to start devspace dev: %w", err)
+	}
+
+	m.nextID++
+	session.ID = fmt.Sprintf("dev-%d", m.nextID)
+	session.proc = proc
+	m.sessions[session.ID] = session
+	return nil
+}
+
+// get returns the session with the given ID
+func (m *devSessionManager) get(id string) (*devSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("dev session %q not found. Use devspace_dev_status to list sessions", id)
+	}
+	return session, nil
+}
+
+// list returns all sessions ordered by ID
+func (m *devSessionManager) list() []*devSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions := make([]*devSession, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	return sessions
+}
+
+// stop terminates a session and forgets it
+func (m *devSessionManager) stop(id string) (*devSession, error) {
+	session, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.proc.Stop(executor.DefaultStopGrace); err != nil {
+		return session, fmt.Errorf("failed to stop dev session %s: %w", id, err)
+	}
+
+	m.mu.Lock()
+	delete(m.sessions, id)
+	m.mu.Unlock()
+	return session, nil
+}
+
+// stopAll terminates every session, used on server shutdown
+func (m *devSessionManager) stopAll() {
+	var wg sync.WaitGroup
+	for _, s := range m.list() {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			m.stop(id)
+		}(s.ID)
+	}
+	wg.Wait()
+}
+
+// DevspaceDevStartTool returns the tool definition for starting a dev session
+func DevspaceDevStartTool() mcp.Tool {
+	return mcp.NewTool("devspace_dev_start",
+		mcp.WithDescription("Start 'devspace dev' as a supervised background session (sync, port forwarding, dev containers). Returns a session ID; use devspace_dev_output to read its output and devspace_dev_stop to end it. Only one session per working_dir/namespace can run at a time. Dev configs that open a terminal or attach to a container must be disabled, e.g. with a profile, because the session has no TTY."),
+		mcp.WithOutputSchema[devSessionInfo](),
+		withCategory(serverconfig.CategoryMutate),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
+		mcp.WithString("profile",
+			mcp.Description("Profile to use"),
+		),
+		mcp.WithBoolean("skip_build",
+			mcp.Description("Skip building images"),
+		),
+		mcp.WithBoolean("force_build",
+			mcp.Description("Force rebuilding images even if not changed"),
+		),
+		mcp.WithBoolean("force_deploy",
+			mcp.Description("Force redeployment even if not changed"),
+		),
+	)
+}
+
+// DevspaceDevStartHandler handles starting a dev session
+func DevspaceDevStartHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := []string{"dev", "--no-colors"}
+
+	namespace := req.GetString("namespace", "")
+	if namespace != "" {
+		if err := ValidateStringParam("namespace", namespace); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--namespace", namespace)
+	}
+	kubeContext := req.GetString("kube_context", "")
+	if kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--kube-context", kubeContext)
+	}
+	profile := req.GetString("profile", "")
+	if profile != "" {
+		if err := ValidateStringParam("profile", profile); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--profile", profile)
+	}
+	if req.GetBool("skip_build", false) {
+		args = append(args, "--skip-build")
+	}
+	if req.GetBool("force_build", false) {
+		args = append(args, "--force-build")
+	}
+	if req.GetBool("force_deploy", false) {
+		args = append(args, "--force-deploy")
+	}
+
+	// Sessions are keyed by directory, so ./app and /work/app must match
+	workingDir, err := filepath.Abs(req.GetString("working_dir", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid working_dir: %v", err)), nil
+	}
+	if _, err := locateConfig(ctx, req); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Without a TTY, a dev terminal or attach waits for input that never
+	// comes. Configs the local parser cannot read are left to devspace.
+	if resolved, err := resolveNative(ctx, req); err == nil {
+		if interactive := resolved.InteractiveDevConfigs(); len(interactive) > 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("devspace dev would open an interactive terminal for %s, which a background session has no TTY for. Set terminal.disabled: true (or attach.disabled: true) in these dev configs, or pass a profile that disables them.",
+				strings.Join(interactive, ", "))), nil
+		}
+	}
+
+	session := &devSession{
+		WorkingDir:  workingDir,
+		Namespace:   namespace,
+		KubeContext: kubeContext,
+		Profile:     profile,
+		Args:        args,
+		Env:         devspaceEnv(ctx),
+	}
+	if err := devSessions.start(session); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Give devspace a moment so that immediate failures are reported here
+	// rather than on the next output call
+	select {
+	case <-session.proc.Done():
+	case <-time.After(devStartupWait):
+	case <-ctx.Done():
+	}
+
+	var out strings.Builder
+	out.WriteString(session.describe())
+	if lines := session.proc.Output().Tail(50); len(lines) > 0 {
+		out.WriteString("\n## Output\n")
+		out.WriteString(formatLines(lines))
+	}
+
+	if !session.proc.Running() {
+		return mcp.NewToolResultError(out.String()), nil
+	}
+	return mcp.NewToolResultStructured(session.info(), out.String()), nil
+}
+
+// DevspaceDevStopTool returns the tool definition for stopping a dev session
+func DevspaceDevStopTool() mcp.Tool {
+	return mcp.NewTool("devspace_dev_stop",
+		mcp.WithDescription("Stop a devspace dev session started with devspace_dev_start. Terminates the whole process group, including sync and port-forwarding helpers."),
+		mcp.WithOutputSchema[devSessionInfo](),
+		withCategory(serverconfig.CategoryMutate),
+		mcp.WithString("session_id",
+			mcp.Required(),
+			mcp.Description("ID of the session returned by devspace_dev_start"),
+		),
+	)
+}
+
+// DevspaceDevStopHandler handles stopping a dev session
+func DevspaceDevStopHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := req.GetString("session_id", "")
+	if id == "" {
+		return mcp.NewToolResultError("session_id parameter is required"), nil
+	}
+
+	session, err := devSessions.stop(id)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var out strings.Builder
+	out.WriteString(session.describe())
+	if lines := session.proc.Output().Tail(20); len(lines) > 0 {
+		out.WriteString("\n## Last output\n")
+		out.WriteString(formatLines(lines))
+	}
+	return mcp.NewToolResultStructured(session.info(), out.String()), nil
+}
+
+// DevspaceDevStatusTool returns the tool definition for inspecting dev sessions
+func DevspaceDevStatusTool() mcp.Tool {
+	return mcp.NewTool("devspace_dev_status",
+		mcp.WithDescription("Show the state of devspace dev sessions started by this server. Without session_id, lists all sessions."),
+		mcp.WithOutputSchema[devSessionsOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("session_id",
+			mcp.Description("ID of a specific session"),
+		),
+	)
+}
+
+// DevspaceDevStatusHandler handles the dev session status command
+func DevspaceDevStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if id := req.GetString("session_id", ""); id != "" {
+		session, err := devSessions.get(id)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultStructured(devSessionsOutput{Sessions: []devSessionInfo{session.info()}}, session.describe()), nil
+	}
+
+	sessions := devSessions.list()
+	if len(sessions) == 0 {
+		return mcp.NewToolResultStructured(devSessionsOutput{Sessions: []devSessionInfo{}}, "No dev sessions. Start one with devspace_dev_start."), nil
+	}
+
+	descriptions := make([]string, 0, len(sessions))
+	infos := make([]devSessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		descriptions = append(descriptions, s.describe())
+		infos = append(infos, s.info())
+	}
+	return mcp.NewToolResultStructured(devSessionsOutput{Sessions: infos}, strings.Join(descriptions, "\n")), nil
+}
+
+// DevspaceDevOutputTool returns the tool definition for reading dev session output
+func DevspaceDevOutputTool() mcp.Tool {
+	return mcp.NewTool("devspace_dev_output",
+		mcp.WithDescription("Read output of a devspace dev session incrementally. Pass the returned next cursor on the following call to only receive new lines."),
+		mcp.WithOutputSchema[devOutputResult](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("session_id",
+			mcp.Required(),
+			mcp.Description("ID of the session returned by devspace_dev_start"),
+		),
+		mcp.WithNumber("cursor",
+			mcp.Description("Sequence number to read from (default: 0, the oldest retained line)"),
+		),
+		mcp.WithNumber("max_lines",
+			mcp.Description("Maximum number of lines to return (default: 200, max: 2000)"),
+		),
+		mcp.WithString("grep",
+			mcp.Description("Only return lines containing this text (case-insensitive)"),
+		),
+	)
+}
+
+// DevspaceDevOutputHandler handles reading dev session output
+func DevspaceDevOutputHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := req.GetString("session_id", "")
+	if id == "" {
+		return mcp.NewToolResultError("session_id parameter is required"), nil
+	}
+
+	session, err := devSessions.get(id)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	cursor := int64(req.GetInt("cursor", 0))
+	if cursor < 0 {
+		cursor = 0
+	}
+	maxLines := req.GetInt("max_lines", 200)
+	if maxLines < 1 {
+		maxLines = 200
+	} else if maxLines > 2000 {
+		maxLines = 2000
+	}
+
+	lines, next, dropped := session.proc.Output().ReadFrom(cursor, maxLines)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Session %s is %s. Next cursor: %d\n", session.ID, session.state(), next)
+	if dropped > 0 {
+		fmt.Fprintf(&out, "⚠️  %d older lines were discarded from the output buffer\n", dropped)
+	}
+	out.WriteString("\n")
+
+	if pattern := req.GetString("grep", ""); pattern != "" {
+		matching := make([]executor.Line, 0, len(lines))
+		for _, l := range lines {
+			if containsIgnoreCase(l.Text, pattern) {
+				matching = append(matching, l)
+			}
+		}
+		lines = matching
+	}
+	if len(lines) == 0 {
+		out.WriteString("(no new output)\n")
+	} else {
+		out.WriteString(formatLines(lines))
+	}
+
+	structured := devOutputResult{
+		SessionID:  session.ID,
+		State:      session.state(),
+		NextCursor: next,
+		Dropped:    dropped,
+		Lines:      lines,
+	}
+	if structured.Lines == nil {
+		structured.Lines = []executor.Line{}
+	}
+	return mcp.NewToolResultStructured(structured, out.String()), nil
+}
+
+// formatLines renders buffered lines, marking those written to stderr
+func formatLines(lines []executor.Line) string {
+	var b strings.Builder
+	for _, l := range lines {
+		if l.Stream == executor.Stderr {
+			b.WriteString("[stderr] ")
+		}
+		b.WriteString(l.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// displayDir renders a working directory, showing the server's directory
+// for an empty value
+func displayDir(dir string) string {
+	if dir == "" {
+		return "(server working directory)"
+	}
+	return dir
+}
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"devspace-mcp/executor"
+)
+
+// useShellDevProcess makes dev sessions run script with sh instead of devspace
+func useShellDevProcess(t *testing.T, script string) *[]executor.Command {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	var started []executor.Command
+	previousStart, previousWait, previousSessions := startProcess, devStartupWait, devSessions
+	startProcess = func(c executor.Command, lines int) (*executor.Background, error) {
+		started = append(started, c)
+		c.Binary = "sh"
+		c.Args = []string{"-c", script}
+		return executor.StartBackground(c, lines)
+	}
+	devStartupWait = 200 * time.Millisecond
+	devSessions = newDevSessionManager()
+	t.Cleanup(func() {
+		devSessions.stopAll()
+		startProcess, devStartupWait, devSessions = previousStart, previousWait, previousSessions
+	})
+	return &started
+}
+
+func devProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "devspace.yaml"), []byte("version: v2beta1"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	return dir
+}
+
+func TestDevSessionLifecycle(t *testing.T) {
+	started := useShellDevProcess(t, "echo starting dev; echo sync ready; sleep 60")
+	dir := devProject(t)
+	ctx := context.Background()
+
+	result, _ := DevspaceDevStartHandler(ctx, newRequest(map[string]any{
+		"working_dir": dir,
+		"namespace":   "dev",
+		"skip_build":  true,
+	}))
+	if result.IsError {
+		t.Fatalf("start failed: %s", resultText(result))
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "Session: dev-1") || !strings.Contains(text, "State: running") {
+		t.Errorf("unexpected start output:\n%s", text)
+	}
+
+	want := "dev --no-colors --namespace dev --skip-build"
+	if got := strings.Join((*started)[0].Args, " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+
+	// A second session for the same project and namespace is rejected
+	result, _ = DevspaceDevStartHandler(ctx, newRequest(map[string]any{"working_dir": dir, "namespace": "dev"}))
+	if !result.IsError || !strings.Contains(resultText(result), "already running") {
+		t.Errorf("expected duplicate session error, got %s", resultText(result))
+	}
+
+	result, _ = DevspaceDevOutputHandler(ctx, newRequest(map[string]any{"session_id": "dev-1", "cursor": 1}))
+	text = resultText(result)
+	if strings.Contains(text, "starting dev") || !strings.Contains(text, "sync ready") || !strings.Contains(text, "Next cursor: 2") {
+		t.Errorf("unexpected incremental output:\n%s", text)
+	}
+
+	result, _ = DevspaceDevStatusHandler(ctx, newRequest(nil))
+	if !strings.Contains(resultText(result), "dev-1") {
+		t.Errorf("status should list dev-1, got %s", resultText(result))
+	}
+
+	result, _ = DevspaceDevStopHandler(ctx, newRequest(map[string]any{"session_id": "dev-1"}))
+	if result.IsError || !strings.Contains(resultText(result), "State: stopped") {
+		t.Errorf("unexpected stop output: %s", resultText(result))
+	}
+
+	result, _ = DevspaceDevStatusHandler(ctx, newRequest(map[string]any{"session_id": "dev-1"}))
+	if !result.IsError {
+		t.Error("stopped session should be forgotten")
+	}
+}
+
+func TestDevSessionImmediateFailure(t *testing.T) {
+	useShellDevProcess(t, "echo 'fatal: cannot connect to cluster' >&2; exit 1")
+
+	result, _ := DevspaceDevStartHandler(context.Background(), newRequest(map[string]any{"working_dir": devProject(t)}))
+	if !result.IsError {
+		t.Fatal("expected error for session that exits immediately")
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "exited (code 1)") || !strings.Contains(text, "[stderr] fatal: cannot connect to cluster") {
+		t.Errorf("unexpected failure output:\n%s", text)
+	}
+}
+
+func TestDevSessionRequiresConfig(t *testing.T) {
+	started := useShellDevProcess(t, "sleep 60")
+
+	result, _ := DevspaceDevStartHandler(context.Background(), newRequest(map[string]any{"working_dir": t.TempDir()}))
+	if !result.IsError {
+		t.Error("expected error without devspace.yaml")
+	}
+	if len(*started) != 0 {
+		t.Error("no process should be started without devspace.yaml")
+	}
+}
+
+func TestShutdownStopsDevSessions(t *testing.T) {
+	useShellDevProcess(t, "sleep 60")
+
+	DevspaceDevStartHandler(context.Background(), newRequest(map[string]any{"working_dir": devProject(t)}))
+	sessions := devSessions.list()
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(sessions))
+	}
+
+	Shutdown()
+
+	if sessions[0].proc.Running() {
+		t.Error("Shutdown should stop running dev sessions")
+	}
+	if len(devSessions.list()) != 0 {
+		t.Error("Shutdown should forget stopped sessions")
+	}
+}
+
+func TestDevSessionRejectsInteractiveTerminal(t *testing.T) {
+	started := useShellDevProcess(t, "sleep 60")
+	dir := t.TempDir()
+	config := `version: v2beta1
+dev:
+  api:
+    imageSelector: api
+    terminal:
+      command: ./devspace_start.sh
+profiles:
+  - name: background
+    patches:
+      - op: add
+        path: dev.api.terminal.disabled
+        value: true
+`
+	if err := os.WriteFile(filepath.Join(dir, "devspace.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := DevspaceDevStartHandler(context.Background(), newRequest(map[string]any{"working_dir": dir}))
+	if !result.IsError || !strings.Contains(resultText(result), "interactive terminal for dev.api") {
+		t.Fatalf("expected the terminal to be rejected, got %s", resultText(result))
+	}
+	if len(*started) != 0 {
+		t.Fatal("no process should be started for an interactive dev config")
+	}
+
+	result, _ = DevspaceDevStartHandler(context.Background(), newRequest(map[string]any{"working_dir": dir, "profile": "background"}))
+	if result.IsError {
+		t.Fatalf("start with the background profile failed: %s", resultText(result))
+	}
+	want := "dev --no-colors --profile background"
+	if got := strings.Join((*started)[0].Args, " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
+func TestDevSessionNormalizesWorkingDir(t *testing.T) {
+	started := useShellDevProcess(t, "sleep 60")
+	dir := devProject(t)
+	t.Chdir(filepath.Dir(dir))
+
+	result, _ := DevspaceDevStartHandler(context.Background(), newRequest(map[string]any{"working_dir": "./" + filepath.Base(dir)}))
+	if result.IsError {
+		t.Fatalf("start failed: %s", resultText(result))
+	}
+	if (*started)[0].Dir != dir {
+		t.Errorf("session should run in %s, got %s", dir, (*started)[0].Dir)
+	}
+
+	result, _ = DevspaceDevStartHandler(context.Background(), newRequest(map[string]any{"working_dir": dir + "/"}))
+	if !result.IsError || !strings.Contains(resultText(result), "already running") {
+		t.Errorf("the same project by its absolute path should be rejected, got %s", resultText(result))
+	}
+}
@@ -0,0 +1,501 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Finding severities, from most to least urgent
+const (
+	severityCritical = "critical"
+	severityError    = "error"
+	severityWarning  = "warning"
+)
+
+// severityRank orders findings; higher ranks come first
+var severityRank = map[string]int{severityCritical: 3, severityError: 2, severityWarning: 1}
+
+// diagnoseLogLines is the number of previous container log lines fetched for
+// a crash looping container
+const diagnoseLogLines = 50
+
+// diagnoseFinding is a problem found by devspace_diagnose. Pods with the same
+// problem in the same workload and container share a finding.
+type diagnoseFinding struct {
+	Rank       int      `json:"rank"`
+	Severity   string   `json:"severity" jsonschema:"description=critical or error or warning"`
+	Rule       string   `json:"rule" jsonschema:"description=image-pull or crash-loop or oom-killed or container-config or unschedulable or probe-failure or not-ready"`
+	Workload   string   `json:"workload" jsonschema:"description=Owning workload such as deployment/api or the pod itself"`
+	Container  string   `json:"container,omitempty"`
+	Pods       []string `json:"pods"`
+	Summary    string   `json:"summary"`
+	Evidence   []string `json:"evidence" jsonschema:"description=Excerpts of container status and events and logs supporting the finding"`
+	NextAction string   `json:"next_action"`
+
+	restarts int
+}
+
+// diagnoseOutput is the result of devspace_diagnose
+type diagnoseOutput struct {
+	Namespace string            `json:"namespace"`
+	Pods      int               `json:"pods" jsonschema:"description=Number of pods checked"`
+	Unhealthy int               `json:"unhealthy" jsonschema:"description=Number of pods with at least one finding"`
+	Findings  []diagnoseFinding `json:"findings"`
+	Analysis  string            `json:"analysis,omitempty" jsonschema:"description=Output of devspace analyze when requested"`
+	Warnings  []string          `json:"warnings,omitempty" jsonschema:"description=Data that could not be fetched"`
+}
+
+// DevspaceDiagnoseTool returns the tool definition for diagnosing a namespace
+func DevspaceDiagnoseTool() mcp.Tool {
+	return mcp.NewTool("devspace_diagnose",
+		mcp.WithDescription("Investigates why pods in a namespace are unhealthy. Checks pod status, events and previous container logs for image pull errors, crash loops, OOMKilled containers, pods that cannot be scheduled and failing probes, and returns a ranked list of findings with evidence and a suggested next action."),
+		mcp.WithOutputSchema[diagnoseOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to diagnose"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
+		mcp.WithString("deployment",
+			mcp.Description("Only diagnose the pods of this deployment"),
+		),
+		mcp.WithString("label_selector",
+			mcp.Description("Only diagnose pods matching this label selector (e.g., 'app=myapp')"),
+		),
+		mcp.WithBoolean("analyze",
+			mcp.Description("Also run devspace analyze and include its report (default: false)"),
+		),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+	)
+}
+
+// DevspaceDiagnoseHandler handles the diagnose command using kubectl
+func DevspaceDiagnoseHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := req.GetString("namespace", "")
+	if namespace == "" {
+		return mcp.NewToolResultError("namespace parameter is required; pass it or set it with devspace_set_context"), nil
+	}
+	if err := ValidateStringParam("namespace", namespace); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	scope := []string{"-n", namespace}
+	kubeContext := req.GetString("kube_context", "")
+	if kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		scope = append(scope, "--context", kubeContext)
+	}
+	deployment := req.GetString("deployment", "")
+	if deployment != "" {
+		if err := ValidateStringParam("deployment", deployment); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	podArgs := append([]string{"get", "pods"}, scope...)
+	if labelSelector := req.GetString("label_selector", ""); labelSelector != "" {
+		if err := ValidateStringParam("label_selector", labelSelector); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		podArgs = append(podArgs, "-l", labelSelector)
+	}
+
+	timeout := commandTimeout("devspace_diagnose")
+	result := executeKubectl(ctx, timeout, append(podArgs, "-o", "json")...)
+	if !result.Success() {
+		return errorResult(req, result), nil
+	}
+	var list struct {
+		Items []podObject `yaml:"items"`
+	}
+	if err := yaml.Unmarshal([]byte(result.Stdout), &list); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("could not parse kubectl pods: %v", err)), nil
+	}
+	pods := list.Items
+	if deployment != "" {
+		pods = nil
+		for _, p := range list.Items {
+			if podWorkload(p) == "deployment/"+deployment {
+				pods = append(pods, p)
+			}
+		}
+	}
+
+	output := diagnoseOutput{Namespace: namespace, Pods: len(pods), Findings: []diagnoseFinding{}}
+
+	// Events add evidence for scheduling and probe failures; the pod status
+	// alone still finds most problems without them
+	var events []eventObject
+	if len(pods) > 0 {
+		result = executeKubectl(ctx, timeout, append(append([]string{"get", "events"}, scope...), "--field-selector", "involvedObject.kind=Pod", "-o", "json")...)
+		if result.Success() {
+			events, _ = parseEventObjects(result.Stdout)
+		} else {
+			output.Warnings = append(output.Warnings, "Could not fetch events: "+strings.TrimSpace(result.Stderr))
+		}
+	}
+
+	d := diagnosis{
+		logs: func(pod, container string) ([]string, bool) {
+			return previousLogs(ctx, scope, pod, container)
+		},
+	}
+	for _, p := range pods {
+		if d.checkPod(p, podEvents(events, p.Metadata.Name)) {
+			output.Unhealthy++
+		}
+	}
+	output.Findings = d.ranked()
+
+	if req.GetBool("analyze", false) {
+		args := []string{"analyze", "--namespace", namespace, "--wait=false", fmt.Sprintf("--timeout=%d", analyzeWait(timeout))}
+		if kubeContext != "" {
+			args = append(args, "--kube-context", kubeContext)
+		}
+		result = executeDevspace(ctx, timeout, req.GetString("working_dir", ""), args...)
+		if result.Success() {
+			output.Analysis = strings.TrimSpace(result.Stdout)
+		} else {
+			output.Warnings = append(output.Warnings, "Analysis failed: "+strings.TrimSpace(result.Stderr))
+		}
+	}
+
+	return mcp.NewToolResultStructured(output, formatDiagnosis(output)), nil
+}
+
+// podWorkload returns the workload of a pod from its owner, or derived from
+// its name for bare pods
+func podWorkload(p podObject) string {
+	if workload := p.workload(); workload != "" {
+		return workload
+	}
+	return workloadOf("Pod", p.Metadata.Name, nil)
+}
+
+// podEvents returns the events of a pod
+func podEvents(events []eventObject, pod string) []eventObject {
+	var matched []eventObject
+	for _, e := range events {
+		if e.InvolvedObject.Kind == "Pod" && e.InvolvedObject.Name == pod {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// previousLogs returns the logs of the previous instance of a container and
+// whether they are from it. When kubectl has none, the current logs are read
+// with the same namespace and context.
+func previousLogs(ctx context.Context, scope []string, pod, container string) ([]string, bool) {
+	for _, previous := range []bool{true, false} {
+		args := []string{"logs", pod}
+		if previous {
+			args = append(args, "--previous")
+		}
+		args = append(args, scope...)
+		args = append(args, "-c", container, fmt.Sprintf("--tail=%d", diagnoseLogLines))
+		if result := executeKubectl(ctx, commandTimeout("devspace_diagnose"), args...); result.Success() {
+			return splitLines(result.Stdout), previous
+		}
+	}
+	return nil, false
+}
+
+// diagnosis collects the findings of the checked pods
+type diagnosis struct {
+	findings []diagnoseFinding
+	// logs returns the logs of a container and whether they are from its
+	// previous instance
+	logs func(pod, container string) ([]string, bool)
+}
+
+// add records a finding for a pod, merging it with the finding of another
+// pod of the same workload that has the same problem
+func (d *diagnosis) add(pod string, f diagnoseFinding) {
+	if existing := d.find(f); existing != nil {
+		existing.Pods = append(existing.Pods, pod)
+		existing.restarts += f.restarts
+		return
+	}
+	f.Pods = []string{pod}
+	d.findings = append(d.findings, f)
+}
+
+// find returns the recorded finding f would be merged into, if any
+func (d *diagnosis) find(f diagnoseFinding) *diagnoseFinding {
+	for i := range d.findings {
+		existing := &d.findings[i]
+		if existing.Rule == f.Rule && existing.Workload == f.Workload && existing.Container == f.Container {
+			return existing
+		}
+	}
+	return nil
+}
+
+// checkPod applies the rules to a pod and reports whether any matched
+func (d *diagnosis) checkPod(p podObject, events []eventObject) bool {
+	if p.Status.Phase == "Succeeded" {
+		return false
+	}
+	reported := false
+	name := p.Metadata.Name
+	workload := podWorkload(p)
+
+	if p.Status.Phase == "Pending" {
+		for _, c := range p.Status.Conditions {
+			if c.Type == "PodScheduled" && c.Status == "False" {
+				evidence := []string{fmt.Sprintf("PodScheduled=False (%s): %s", c.Reason, c.Message)}
+				evidence = append(evidence, eventEvidence(events, "FailedScheduling")...)
+				d.add(name, diagnoseFinding{
+					Severity:   severityError,
+					Rule:       "unschedulable",
+					Workload:   workload,
+					Summary:    "Pod cannot be scheduled",
+					Evidence:   evidence,
+					NextAction: schedulingAction(c.Message),
+				})
+				reported = true
+			}
+		}
+	}
+
+	statuses := make([]containerStatus, 0, len(p.Status.InitContainerStatuses)+len(p.Status.ContainerStatuses))
+	statuses = append(statuses, p.Status.InitContainerStatuses...)
+	statuses = append(statuses, p.Status.ContainerStatuses...)
+	for _, c := range statuses {
+		if d.checkContainer(name, workload, c, events) {
+			reported = true
+		}
+	}
+
+	if probes := eventEvidence(events, "Unhealthy"); len(probes) > 0 {
+		severity := severityWarning
+		for _, line := range probes {
+			if strings.Contains(line, "Liveness probe failed") {
+				severity = severityError
+			}
+		}
+		d.add(name, diagnoseFinding{
+			Severity:   severity,
+			Rule:       "probe-failure",
+			Workload:   workload,
+			Summary:    "Health probes are failing",
+			Evidence:   probes,
+			NextAction: "Check that the probe path and port match what the container serves; raise initialDelaySeconds or add a startupProbe if the application starts slowly.",
+		})
+		reported = true
+	}
+
+	if !reported && p.Status.Phase == "Running" {
+		var unready []string
+		for _, c := range p.Status.ContainerStatuses {
+			if !c.Ready {
+				unready = append(unready, c.Name)
+			}
+		}
+		if len(unready) > 0 {
+			d.add(name, diagnoseFinding{
+				Severity:   severityWarning,
+				Rule:       "not-ready",
+				Workload:   workload,
+				Summary:    "Containers are running but not ready: " + strings.Join(unready, ", "),
+				Evidence:   []string{fmt.Sprintf("%d/%d containers ready", len(p.Status.ContainerStatuses)-len(unready), len(p.Status.ContainerStatuses))},
+				NextAction: "Check the container logs with devspace_logs and the readiness probe configuration.",
+			})
+			reported = true
+		}
+	}
+	return reported
+}
+
+// checkContainer applies the container rules and reports whether any matched
+func (d *diagnosis) checkContainer(pod, workload string, c containerStatus, events []eventObject) bool {
+	waiting := c.State.Waiting
+	last := c.LastState.Terminated
+	if c.State.Terminated != nil && c.State.Terminated.Reason == "OOMKilled" {
+		last = c.State.Terminated
+	}
+	finding := diagnoseFinding{Workload: workload, Container: c.Name, restarts: c.RestartCount}
+
+	switch {
+	case waiting != nil && (waiting.Reason == "ImagePullBackOff" || waiting.Reason == "ErrImagePull" || waiting.Reason == "InvalidImageName"):
+		finding.Severity = severityCritical
+		finding.Rule = "image-pull"
+		finding.Summary = fmt.Sprintf("Image %s cannot be pulled", c.Image)
+		finding.Evidence = append([]string{statusLine(c.Name, waiting.Reason, waiting.Message)}, eventEvidence(events, "Failed")...)
+		finding.NextAction = patternSuggestion(waiting.Reason, "Check the image name and tag, that it was pushed, and the imagePullSecrets for private registries.")
+	case last != nil && last.Reason == "OOMKilled":
+		finding.Severity = severityCritical
+		finding.Rule = "oom-killed"
+		finding.Summary = "Container was killed for exceeding its memory limit"
+		finding.Evidence = []string{fmt.Sprintf("container %s: terminated OOMKilled (exit code %d), %d restarts", c.Name, last.ExitCode, c.RestartCount)}
+		finding.NextAction = patternSuggestion("OOMKilled", "Raise resources.limits.memory of the container.")
+	case waiting != nil && waiting.Reason == "CrashLoopBackOff":
+		finding.Severity = severityCritical
+		finding.Rule = "crash-loop"
+		finding.Summary = "Container keeps crashing"
+		line := fmt.Sprintf("container %s: CrashLoopBackOff, %d restarts", c.Name, c.RestartCount)
+		if last != nil {
+			line += fmt.Sprintf(", last exit code %d (%s)", last.ExitCode, last.Reason)
+		}
+		finding.Evidence = []string{line}
+		// A merged finding keeps the evidence of its first pod, so the
+		// logs of the others are not needed
+		if d.find(finding) != nil {
+			break
+		}
+		logs, previous := d.logs(pod, c.Name)
+		excerpt := logExcerpt(logs)
+		label := "log: "
+		if !previous {
+			label = "current log: "
+		}
+		for _, l := range excerpt {
+			finding.Evidence = append(finding.Evidence, label+l)
+		}
+		finding.NextAction = patternSuggestion("CrashLoopBackOff", "Fix the error shown in the previous container logs.")
+		if suggestion := logSuggestion(excerpt); suggestion != "" {
+			finding.NextAction = "The logs point to: " + suggestion
+		}
+	case waiting != nil && (waiting.Reason == "CreateContainerConfigError" || waiting.Reason == "CreateContainerError" || waiting.Reason == "RunContainerError"):
+		finding.Severity = severityCritical
+		finding.Rule = "container-config"
+		finding.Summary = "Container cannot be created"
+		finding.Evidence = []string{statusLine(c.Name, waiting.Reason, waiting.Message)}
+		finding.NextAction = "Check that the Secrets, ConfigMaps and volumes referenced by the container exist and contain the referenced keys."
+	default:
+		return false
+	}
+	d.add(pod, finding)
+	return true
+}
+
+// ranked returns the findings, most severe and widespread first
+func (d *diagnosis) ranked() []diagnoseFinding {
+	findings := append([]diagnoseFinding{}, d.findings...)
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] > severityRank[b.Severity]
+		}
+		if len(a.Pods) != len(b.Pods) {
+			return len(a.Pods) > len(b.Pods)
+		}
+		return a.restarts > b.restarts
+	})
+	for i := range findings {
+		findings[i].Rank = i + 1
+	}
+	return findings
+}
+
+// statusLine describes a waiting container
+func statusLine(container, reason, message string) string {
+	if message == "" {
+		return fmt.Sprintf("container %s: %s", container, reason)
+	}
+	return fmt.Sprintf("container %s: %s: %s", container, reason, message)
+}
+
+// eventEvidence returns the events with the given reason as evidence lines
+func eventEvidence(events []eventObject, reason string) []string {
+	var lines []string
+	for _, e := range events {
+		if e.Reason == reason {
+			_, _, count := e.occurrences()
+			lines = append(lines, fmt.Sprintf("%d× %s: %s", count, e.Reason, strings.TrimSpace(e.Message)))
+		}
+	}
+	return lines
+}
+
+// logExcerpt picks the lines of a log that explain a crash: the last error
+// lines, or the last lines when none look like errors
+func logExcerpt(lines []string) []string {
+	excerpt := splitLines(filterByLevel(strings.Join(lines, "\n"), "error"))
+	limit := 5
+	if len(excerpt) == 0 {
+		excerpt, limit = lines, 10
+	}
+	excerpt = excerpt[max(len(excerpt)-limit, 0):]
+	for i, line := range excerpt {
+		if len(line) > 200 {
+			excerpt[i] = line[:200] + "…"
+		}
+	}
+	return excerpt
+}
+
+// patternSuggestion returns the suggestion of the error pattern matching
+// text followed by a more specific hint
+func patternSuggestion(text, hint string) string {
+	if c := errorPatterns.Classify(text, 0, "devspace_diagnose"); c != nil {
+		return c.Suggestions[0] + " " + hint
+	}
+	return hint
+}
+
+// logSuggestion returns the suggestion of the error pattern that best
+// matches the log lines
+func logSuggestion(lines []string) string {
+	if c := errorPatterns.Classify(strings.Join(lines, "\n"), 0, "devspace_diagnose"); c != nil {
+		return c.Suggestions[0]
+	}
+	return ""
+}
+
+// schedulingAction suggests how to make a pod schedulable based on the
+// scheduler message
+func schedulingAction(message string) string {
+	switch {
+	case containsIgnoreCase(message, "Insufficient"):
+		return "Lower the resources.requests of the pod or add capacity to the cluster."
+	case containsIgnoreCase(message, "taint"):
+		return "Add tolerations for the node taints or schedule the pod on other nodes."
+	case containsIgnoreCase(message, "affinity") || containsIgnoreCase(message, "selector"):
+		return "Check the nodeSelector and affinity rules of the pod against the node labels."
+	case containsIgnoreCase(message, "PersistentVolumeClaim"):
+		return "Check that the PersistentVolumeClaims of the pod are bound and their storage class exists."
+	default:
+		return "Check the scheduler message and the nodes with kubectl describe nodes."
+	}
+}
+
+// formatDiagnosis renders the findings, most urgent first
+func formatDiagnosis(out diagnoseOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Diagnosis of namespace %s\n\nChecked %d pods, %d unhealthy.\n", out.Namespace, out.Pods, out.Unhealthy)
+	for _, w := range out.Warnings {
+		fmt.Fprintf(&b, "⚠️  %s\n", w)
+	}
+	if len(out.Findings) == 0 {
+		b.WriteString("\n✅ No problems found.\n")
+	}
+	icons := map[string]string{severityCritical: "🔴", severityError: "🟠", severityWarning: "🟡"}
+	for _, f := range out.Findings {
+		subject := f.Workload
+		if f.Container != "" {
+			subject += " (container " + f.Container + ")"
+		}
+		fmt.Fprintf(&b, "\n## %d. %s %s: %s\n", f.Rank, icons[f.Severity], subject, f.Summary)
+		fmt.Fprintf(&b, "Pods: %s\n", strings.Join(f.Pods, ", "))
+		for _, e := range f.Evidence {
+			fmt.Fprintf(&b, "  > %s\n", e)
+		}
+		fmt.Fprintf(&b, "➡️  %s\n", f.NextAction)
+	}
+	if out.Analysis != "" {
+		b.WriteString("\n## devspace analyze\n" + out.Analysis + "\n")
+	}
+	return b.String()
+}
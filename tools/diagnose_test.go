@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func TestDevspaceDiagnoseHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: readFixture(t, "kubectl_pods_unhealthy.json")})
+	fake.On("kubectl", []string{"get", "events"}, executor.Result{Stdout: readFixture(t, "kubectl_diagnose_events.json")})
+	fake.On("kubectl", []string{"logs", "worker-5b6f7c8d9-abcde"}, executor.Result{
+		Stdout: "starting worker\nconnecting to queue\nFATAL: dial tcp 10.96.0.12:5672: connect: connection refused\n",
+	})
+
+	result, _ := DevspaceDiagnoseHandler(ctx, newRequest(map[string]any{
+		"namespace":    "dev",
+		"kube_context": "kind-dev",
+	}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	output := result.StructuredContent.(diagnoseOutput)
+	if output.Pods != 7 || output.Unhealthy != 6 {
+		t.Errorf("pods = %d, unhealthy = %d, want 7 and 6", output.Pods, output.Unhealthy)
+	}
+
+	var got []string
+	for _, f := range output.Findings {
+		got = append(got, fmt.Sprintf("%d %s %s %s %s", f.Rank, f.Severity, f.Rule, f.Workload, strings.Join(f.Pods, ",")))
+	}
+	want := []string{
+		"1 critical crash-loop deployment/worker worker-5b6f7c8d9-abcde",
+		"2 critical oom-killed statefulset/cache cache-0",
+		"3 critical image-pull deployment/web web-6c8d7f9b4-qwert",
+		"4 error unschedulable deployment/api api-7d9f8b6c5d-x2kqp,api-7d9f8b6c5d-b7n2m",
+		"5 warning probe-failure deployment/frontend frontend-84b5c6d7f8-zx9yw",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	crash := output.Findings[0]
+	if !strings.Contains(strings.Join(crash.Evidence, "\n"), "log: FATAL: dial tcp 10.96.0.12:5672: connect: connection refused") {
+		t.Errorf("crash loop evidence should include the log excerpt, got %v", crash.Evidence)
+	}
+	if !strings.Contains(crash.NextAction, "The connection was refused") {
+		t.Errorf("next action should come from the error pattern in the logs, got %s", crash.NextAction)
+	}
+	if pull := output.Findings[2]; len(pull.Evidence) != 2 || !strings.HasPrefix(pull.Evidence[1], "4× Failed:") {
+		t.Errorf("image pull evidence should include the event, got %v", pull.Evidence)
+	}
+	if action := output.Findings[3].NextAction; !strings.Contains(action, "resources.requests") {
+		t.Errorf("unexpected scheduling action: %s", action)
+	}
+
+	for _, call := range fake.Calls() {
+		if call.Args[0] == "logs" && !strings.Contains(strings.Join(call.Args, " "), "--previous -n dev --context kind-dev -c worker") {
+			t.Errorf("unexpected logs call: %v", call.Args)
+		}
+	}
+	if text := resultText(result); !strings.Contains(text, "## 1. 🔴 deployment/worker (container worker): Container keeps crashing") {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+}
+
+func TestDiagnoseDeploymentFilter(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: readFixture(t, "kubectl_pods_unhealthy.json")})
+	fake.On("kubectl", []string{"get", "events"}, executor.Result{Stderr: "forbidden", ExitCode: 1})
+
+	result, _ := DevspaceDiagnoseHandler(ctx, newRequest(map[string]any{
+		"namespace":  "dev",
+		"deployment": "api",
+	}))
+	output := result.StructuredContent.(diagnoseOutput)
+	if output.Pods != 2 || len(output.Findings) != 1 || output.Findings[0].Rule != "unschedulable" {
+		t.Errorf("unexpected output: %+v", output)
+	}
+	if len(output.Warnings) != 1 || !strings.Contains(output.Warnings[0], "forbidden") {
+		t.Errorf("missing events should be reported, got %v", output.Warnings)
+	}
+}
+
+func TestDiagnoseLogsFallback(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: readFixture(t, "kubectl_pods.json")})
+	fake.On("kubectl", []string{"get", "events"}, executor.Result{Stdout: `{"items": []}`})
+	fake.On("kubectl", []string{"logs", "worker-5b6f7c8d9-abcde", "--previous"}, executor.Result{Stderr: "previous terminated container not found", ExitCode: 1})
+	fake.On("kubectl", []string{"logs"}, executor.Result{Stdout: "panic: nil map\n"})
+
+	result, _ := DevspaceDiagnoseHandler(ctx, newRequest(map[string]any{"namespace": "dev", "kube_context": "kind-dev"}))
+	output := result.StructuredContent.(diagnoseOutput)
+	if len(output.Findings) != 1 || output.Findings[0].Rule != "crash-loop" {
+		t.Fatalf("unexpected findings: %+v", output.Findings)
+	}
+	if evidence := output.Findings[0].Evidence; evidence[len(evidence)-1] != "current log: panic: nil map" {
+		t.Errorf("logs should fall back to the current logs, got %v", evidence)
+	}
+
+	calls := fake.Calls()
+	last := strings.Join(calls[len(calls)-1].Args, " ")
+	if last != "logs worker-5b6f7c8d9-abcde -n dev --context kind-dev -c worker --tail=50" {
+		t.Errorf("unexpected fallback call: %s", last)
+	}
+}
+
+func TestDiagnoseFetchesLogsOncePerFinding(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	pod := func(name string) string {
+		return `{"metadata": {"name": "` + name + `", "ownerReferences": [{"kind": "ReplicaSet", "name": "worker-5b6f7c8d9"}]},
+			"status": {"phase": "Running", "containerStatuses": [{"name": "worker", "restartCount": 4, "state": {"waiting": {"reason": "CrashLoopBackOff"}}}]}}`
+	}
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: `{"items": [` + pod("worker-5b6f7c8d9-aaaaa") + "," + pod("worker-5b6f7c8d9-bbbbb") + `]}`})
+	fake.On("kubectl", []string{"get", "events"}, executor.Result{Stdout: `{"items": []}`})
+	fake.On("kubectl", []string{"logs"}, executor.Result{Stdout: "panic: nil map\n"})
+
+	result, _ := DevspaceDiagnoseHandler(ctx, newRequest(map[string]any{"namespace": "dev"}))
+	output := result.StructuredContent.(diagnoseOutput)
+	if len(output.Findings) != 1 || len(output.Findings[0].Pods) != 2 {
+		t.Fatalf("unexpected findings: %+v", output.Findings)
+	}
+	var logs []string
+	for _, call := range fake.Calls() {
+		if call.Args[0] == "logs" {
+			logs = append(logs, call.Args[1])
+		}
+	}
+	if len(logs) != 1 || logs[0] != "worker-5b6f7c8d9-aaaaa" {
+		t.Errorf("logs should only be read for the pod whose evidence is kept, got %v", logs)
+	}
+}
+
+func TestDiagnoseRequiresNamespace(t *testing.T) {
+	_, ctx := useFakeRunner(t)
+	result, _ := DevspaceDiagnoseHandler(ctx, newRequest(map[string]any{}))
+	if !result.IsError {
+		t.Error("expected an error without namespace")
+	}
+}
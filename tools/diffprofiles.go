@@ -0,0 +1,283 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultDiffSections are the config sections compared by default
+var defaultDiffSections = []string{"images", "deployments", "dev", "vars", "pipelines"}
+
+// baseProfile names the config without a profile in diffs
+const baseProfile = "base"
+
+// profileChange is a value that differs between two resolved configs
+type profileChange struct {
+	Path   string `json:"path"`
+	Change string `json:"change" jsonschema:"description=added or removed or changed"`
+	From   any    `json:"from,omitempty"`
+	To     any    `json:"to,omitempty"`
+}
+
+// diffProfilesOutput is the result of devspace_diff_profiles
+type diffProfilesOutput struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Sections []string        `json:"sections"`
+	Changes  []profileChange `json:"changes"`
+	Diff     string          `json:"diff" jsonschema:"description=Unified diff of the compared sections as YAML"`
+	Source   string          `json:"source" jsonschema:"description=cli when the devspace CLI resolved both configs or native when devspace.yaml was parsed locally"`
+}
+
+// DevspaceDiffProfilesTool returns the tool definition for comparing profiles
+func DevspaceDiffProfilesTool() mcp.Tool {
+	return mcp.NewTool("devspace_diff_profiles",
+		mcp.WithDescription("Show what a profile changes: resolves the configuration for two profiles, or the base configuration and a profile, and returns a path-based diff of images, deployments, dev, vars and pipelines plus a unified YAML diff"),
+		mcp.WithOutputSchema[diffProfilesOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("to_profile",
+			mcp.Required(),
+			mcp.Description("Profile whose configuration is compared"),
+		),
+		mcp.WithString("from_profile",
+			mcp.Description("Profile to compare against; the base configuration without a profile if omitted"),
+		),
+		mcp.WithString("sections",
+			mcp.Description("Comma-separated top-level sections to compare (default: images,deployments,dev,vars,pipelines)"),
+		),
+		mcp.WithBoolean("offline",
+			mcp.Description(offlineDescription),
+		),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+	)
+}
+
+// DevspaceDiffProfilesHandler handles the diff profiles command
+func DevspaceDiffProfilesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	to := req.GetString("to_profile", "")
+	if to == "" {
+		return mcp.NewToolResultError("to_profile parameter is required"), nil
+	}
+	from := req.GetString("from_profile", "")
+	for name, value := range map[string]string{"to_profile": to, "from_profile": from} {
+		if value == "" {
+			continue
+		}
+		if err := ValidateStringParam(name, value); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if from == to {
+		return mcp.NewToolResultError("from_profile and to_profile must differ"), nil
+	}
+
+	sections := defaultDiffSections
+	if value := req.GetString("sections", ""); value != "" {
+		sections = nil
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				sections = append(sections, s)
+			}
+		}
+	}
+
+	output := diffProfilesOutput{From: from, To: to, Sections: sections, Changes: []profileChange{}, Source: sourceCLI}
+	if from == "" {
+		output.From = baseProfile
+	}
+
+	// Both sides must come from the same source: the local parser leaves
+	// variables the CLI resolves, which would show up as differences
+	offline := req.GetBool("offline", false)
+	var printed [2]printOutput
+	for i, profile := range []string{from, to} {
+		var err error
+		if printed[i], err = printProfile(ctx, req, profile, offline); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if printed[0].Source != printed[1].Source {
+		for i, profile := range []string{from, to} {
+			if printed[i].Source == sourceNative {
+				continue
+			}
+			var err error
+			if printed[i], err = printProfile(ctx, req, profile, true); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+	}
+	output.Source = printed[0].Source
+	configs := []map[string]any{
+		selectSections(printed[0].Config, sections),
+		selectSections(printed[1].Config, sections),
+	}
+
+	compareConfigs(configs[0], configs[1], "", &output.Changes)
+	fromYAML, err := yaml.Marshal(configs[0])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	toYAML, err := yaml.Marshal(configs[1])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	output.Diff = unifiedDiff(output.From, output.To, string(fromYAML), string(toYAML))
+
+	return mcp.NewToolResultStructured(output, formatProfileDiff(output)), nil
+}
+
+// printProfile resolves the configuration for a profile through
+// DevspacePrintHandler, so the CLI and its local fallback are used the same
+// way as by devspace_print. offline skips the CLI.
+func printProfile(ctx context.Context, req mcp.CallToolRequest, profile string, offline bool) (printOutput, error) {
+	args := map[string]any{
+		"profile":     profile,
+		"skip_info":   true,
+		"offline":     offline,
+		"working_dir": req.GetString("working_dir", ""),
+		"config":      req.GetString("config", ""),
+	}
+	printReq := mcp.CallToolRequest{}
+	printReq.Params.Name = "devspace_print"
+	printReq.Params.Arguments = args
+
+	name := profile
+	if name == "" {
+		name = baseProfile
+	}
+	result, err := DevspacePrintHandler(ctx, printReq)
+	if err != nil {
+		return printOutput{}, err
+	}
+	if result.IsError {
+		var text []string
+		for _, content := range result.Content {
+			if t, ok := content.(mcp.TextContent); ok {
+				text = append(text, t.Text)
+			}
+		}
+		return printOutput{}, fmt.Errorf("resolving %s failed:\n%s", name, strings.Join(text, "\n"))
+	}
+	printed, ok := result.StructuredContent.(printOutput)
+	if !ok || printed.Config == nil {
+		return printOutput{}, fmt.Errorf("could not parse the configuration printed for %s", name)
+	}
+	return printed, nil
+}
+
+// selectSections returns the given top-level sections of a config
+func selectSections(config map[string]any, sections []string) map[string]any {
+	selected := make(map[string]any)
+	for _, s := range sections {
+		if value, ok := config[s]; ok {
+			selected[s] = value
+		}
+	}
+	return selected
+}
+
+// compareConfigs records the values that differ between two configs. Lists
+// are compared item by item.
+func compareConfigs(from, to any, path string, changes *[]profileChange) {
+	switch f := from.(type) {
+	case map[string]any:
+		t, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(f)+len(t))
+		for key := range f {
+			keys = append(keys, key)
+		}
+		for key := range t {
+			if _, exists := f[key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := path + "." + key
+			fromValue, inFrom := f[key]
+			toValue, inTo := t[key]
+			switch {
+			case !inFrom:
+				*changes = append(*changes, profileChange{Path: displayPath(child), Change: "added", To: toValue})
+			case !inTo:
+				*changes = append(*changes, profileChange{Path: displayPath(child), Change: "removed", From: fromValue})
+			default:
+				compareConfigs(fromValue, toValue, child, changes)
+			}
+		}
+		return
+	case []any:
+		t, ok := to.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(f), len(t)); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(f):
+				*changes = append(*changes, profileChange{Path: displayPath(child), Change: "added", To: t[i]})
+			case i >= len(t):
+				*changes = append(*changes, profileChange{Path: displayPath(child), Change: "removed", From: f[i]})
+			default:
+				compareConfigs(f[i], t[i], child, changes)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, profileChange{Path: displayPath(path), Change: "changed", From: from, To: to})
+	}
+}
+
+// formatProfileDiff renders the change list followed by the unified diff
+func formatProfileDiff(out diffProfilesOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Profile diff: %s → %s\n\nSections: %s\nSource: %s\n\n", out.From, out.To, strings.Join(out.Sections, ", "), out.Source)
+	if len(out.Changes) == 0 {
+		b.WriteString("No differences.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d changes:\n", len(out.Changes))
+	for _, c := range out.Changes {
+		switch c.Change {
+		case "added":
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Path, summarizeValue(c.To))
+		case "removed":
+			fmt.Fprintf(&b, "- %s: %s\n", c.Path, summarizeValue(c.From))
+		default:
+			fmt.Fprintf(&b, "~ %s: %s → %s\n", c.Path, summarizeValue(c.From), summarizeValue(c.To))
+		}
+	}
+	b.WriteString("\n" + out.Diff)
+	return b.String()
+}
+
+// summarizeValue describes a changed value on one line
+func summarizeValue(value any) string {
+	switch v := value.(type) {
+	case map[string]any:
+		return fmt.Sprintf("object with %d fields", len(v))
+	case []any:
+		return fmt.Sprintf("%d items", len(v))
+	case string:
+		return fmt.Sprintf("%q", v)
+	case nil:
+		return "empty"
+	default:
+		return fmt.Sprint(v)
+	}
+}
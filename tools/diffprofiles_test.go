@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func TestDiffProfilesOffline(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+
+	result, _ := DevspaceDiffProfilesHandler(ctx, newRequest(map[string]any{
+		"working_dir": nativeProject(t),
+		"to_profile":  "production",
+		"offline":     true,
+	}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	if len(fake.Calls()) != 0 {
+		t.Errorf("offline must not run the CLI, got %+v", fake.Calls())
+	}
+
+	output := result.StructuredContent.(diffProfilesOutput)
+	if output.From != "base" || output.Source != sourceNative {
+		t.Errorf("unexpected output: %+v", output)
+	}
+	if len(output.Changes) != 1 || output.Changes[0] != (profileChange{
+		Path:   "images.api.image",
+		Change: "changed",
+		From:   "registry.example.com/shop",
+		To:     "prod.example.com/shop",
+	}) {
+		t.Errorf("unexpected changes: %+v", output.Changes)
+	}
+	if !strings.Contains(output.Diff, "-        image: registry.example.com/shop\n+        image: prod.example.com/shop\n") {
+		t.Errorf("unexpected diff:\n%s", output.Diff)
+	}
+}
+
+func TestDiffProfilesUsesPrint(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"print", "--profile", "staging"}, executor.Result{
+		Stdout: "version: v2beta1\nname: shop\ndev:\n  api:\n    ports:\n      - port: \"8080\"\npipelines:\n  dev: start_dev api\n",
+	})
+	fake.On("devspace", []string{"print", "--profile", "production"}, executor.Result{
+		Stdout: "version: v2beta1\nname: shop-prod\ndev:\n  api:\n    ports:\n      - port: \"8080\"\n      - port: \"9090\"\n",
+	})
+
+	result, _ := DevspaceDiffProfilesHandler(ctx, newRequest(map[string]any{
+		"working_dir":  nativeProject(t),
+		"from_profile": "staging",
+		"to_profile":   "production",
+	}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	for _, call := range fake.Calls() {
+		if !strings.Contains(strings.Join(call.Args, " "), "--skip-info") {
+			t.Errorf("print should skip the info table, got %v", call.Args)
+		}
+	}
+
+	output := result.StructuredContent.(diffProfilesOutput)
+	var paths []string
+	for _, c := range output.Changes {
+		paths = append(paths, c.Change+" "+c.Path)
+	}
+	// name is not a compared section
+	want := "added dev.api.ports[1], removed pipelines"
+	if strings.Join(paths, ", ") != want {
+		t.Errorf("changes = %s, want %s", strings.Join(paths, ", "), want)
+	}
+	if !strings.Contains(resultText(result), "--- staging\n+++ production\n") {
+		t.Errorf("text should contain the unified diff, got %s", resultText(result))
+	}
+}
+
+func TestDiffProfilesUsesOneSource(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"print", "--profile", "production"}, executor.Result{Stderr: "error: cannot connect to cluster", ExitCode: 1})
+	fake.On("devspace", []string{"print"}, executor.Result{
+		Stdout: "version: v2beta1\nname: shop\nimages:\n  api:\n    image: registry.example.com/shop\n    tags:\n      - v1.2.0\n",
+	})
+
+	result, _ := DevspaceDiffProfilesHandler(ctx, newRequest(map[string]any{
+		"working_dir": nativeProject(t),
+		"to_profile":  "production",
+	}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	output := result.StructuredContent.(diffProfilesOutput)
+	if output.Source != sourceNative {
+		t.Errorf("source = %s, want native for both sides", output.Source)
+	}
+	// The CLI resolved the VERSION tag of the base config, the local parser
+	// cannot; only the profile's image change remains when both are native
+	if len(output.Changes) != 1 || output.Changes[0].Path != "images.api.image" {
+		t.Errorf("unexpected changes: %+v", output.Changes)
+	}
+	if !strings.Contains(resultText(result), "Source: native") {
+		t.Errorf("text should name the source, got %s", resultText(result))
+	}
+}
+
+func TestDiffProfilesValidation(t *testing.T) {
+	_, ctx := useFakeRunner(t)
+	for _, args := range []map[string]any{
+		{},
+		{"to_profile": "dev", "from_profile": "dev"},
+		{"to_profile": "dev; rm -rf /"},
+	} {
+		result, _ := DevspaceDiffProfilesHandler(ctx, newRequest(args))
+		if !result.IsError {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
@@ -1,124 +1,101 @@
 package tools
 
 import (
+	"fmt"
 	"strings"
 
+	"devspace-mcp/errorpatterns"
 	"devspace-mcp/executor"
+
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
-// ErrorContext represents a known error pattern and its helpful suggestion
-type ErrorContext struct {
-	Pattern    string
-	Suggestion string
+// errorMetaKey is the _meta key under which error results carry their
+// classification
+const errorMetaKey = "error"
+
+// errorPatterns classifies the errors of all tools. It holds the built-in
+// patterns until SetErrorPatternDirs adds user pattern packs.
+var errorPatterns = errorpatterns.Builtin()
+
+// SetErrorPatternDirs loads the pattern packs of dirs on top of the built-in
+// error patterns
+func SetErrorPatternDirs(dirs []string) error {
+	set, err := errorpatterns.Load(dirs...)
+	if err != nil {
+		return err
+	}
+	errorPatterns = set
+	return nil
 }
 
-// errorPatterns contains known error patterns and helpful suggestions
-var errorPatterns = []ErrorContext{
-	{
-		Pattern:    "token has expired",
-		Suggestion: "Your AWS SSO session has expired. Run: aws sso login",
-	},
-	{
-		Pattern:    "Unable to connect to the server",
-		Suggestion: "Cannot reach Kubernetes cluster. Check your VPN connection or cluster status.",
-	},
-	{
-		Pattern:    "connection refused",
-		Suggestion: "Cannot connect to Kubernetes API server. Verify the cluster is running and accessible.",
-	},
-	{
-		Pattern:    "forbidden",
-		Suggestion: "Permission denied. Verify your RBAC permissions for this namespace/resource.",
-	},
-	{
-		Pattern:    "unauthorized",
-		Suggestion: "Authentication failed. Check your kubeconfig credentials and context.",
-	},
-	{
-		Pattern:    "not found",
-		Suggestion: "Resource not found. Verify the namespace, resource name, and that the resource exists.",
-	},
-	{
-		Pattern:    "context deadline exceeded",
-		Suggestion: "Operation timed out. The cluster may be under heavy load or unreachable.",
-	},
-	{
-		Pattern:    "no such host",
-		Suggestion: "DNS resolution failed. Check your network connection and cluster endpoint.",
-	},
-	{
-		Pattern:    "devspace.yaml",
-		Suggestion: "No devspace.yaml found. Use working_dir parameter to specify the project location.",
-	},
-	{
-		Pattern:    "Cannot find a devspace.yaml",
-		Suggestion: "No devspace.yaml found in current directory. Use working_dir parameter to specify the project location.",
-	},
-	{
-		Pattern:    "no space left on device",
-		Suggestion: "Disk space exhausted. Free up disk space or clean up old images/containers.",
-	},
-	{
-		Pattern:    "ImagePullBackOff",
-		Suggestion: "Cannot pull container image. Check image name, registry credentials, and network connectivity.",
-	},
-	{
-		Pattern:    "CrashLoopBackOff",
-		Suggestion: "Container is repeatedly crashing. Check pod logs for application errors.",
-	},
-	{
-		Pattern:    "OOMKilled",
-		Suggestion: "Container was killed due to out of memory. Increase memory limits or optimize application memory usage.",
-	},
-	{
-		Pattern:    "ErrImagePull",
-		Suggestion: "Failed to pull container image. Verify image exists and registry is accessible.",
-	},
-	{
-		Pattern:    "namespace does not exist",
-		Suggestion: "The specified namespace doesn't exist. Create it first or check the namespace name.",
-	},
-	{
-		Pattern:    "certificate has expired",
-		Suggestion: "TLS certificate expired. Renew the certificate or update your kubeconfig.",
-	},
-	{
-		Pattern:    "x509: certificate",
-		Suggestion: "Certificate validation error. Check your kubeconfig certificates or use --insecure flag if appropriate.",
-	},
+// ClassifyError classifies the output of a failed command, or returns nil
+// when no known pattern matches
+func ClassifyError(result executor.Result) *errorpatterns.Classification {
+	return errorPatterns.Classify(errorText(result), result.ExitCode, "")
 }
 
-// EnhanceError analyzes error output and adds helpful suggestions
-func EnhanceError(result executor.Result) string {
-	// Combine stderr and error for analysis
-	errorText := result.Stderr
+// errorText combines stderr and the execution error for analysis
+func errorText(result executor.Result) string {
+	text := result.Stderr
 	if result.Error != "" {
-		if errorText != "" {
-			errorText += "\n"
+		if text != "" {
+			text += "\n"
 		}
-		errorText += result.Error
+		text += result.Error
 	}
+	return text
+}
 
-	// If there's no error text, return formatted output
-	if errorText == "" {
-		return result.FormatOutput()
+// EnhanceError analyzes error output and adds helpful suggestions
+func EnhanceError(result executor.Result) string {
+	return enhanceError(result, ClassifyError(result))
+}
+
+// enhanceError appends the classification of an error to its output
+func enhanceError(result executor.Result, c *errorpatterns.Classification) string {
+	enhanced := result.FormatOutput()
+	if c == nil {
+		return enhanced
 	}
+	return enhanced + formatClassification(c, enhanced != "")
+}
 
-	// Check for matching patterns
-	for _, ec := range errorPatterns {
-		if containsIgnoreCase(errorText, ec.Pattern) {
-			// Add suggestion to the output
-			enhanced := result.FormatOutput()
-			if enhanced != "" {
-				enhanced += "\n\n"
-			}
-			enhanced += "💡 Suggestion: " + ec.Suggestion
-			return enhanced
+// formatClassification renders the suggestions, remediation, docs and code
+// of a classified error
+func formatClassification(c *errorpatterns.Classification, separate bool) string {
+	var b strings.Builder
+	if separate {
+		b.WriteString("\n\n")
+	}
+	for i, s := range c.Suggestions {
+		if i == 0 {
+			b.WriteString("💡 Suggestion: " + s + "\n")
+		} else {
+			b.WriteString("   • " + s + "\n")
 		}
 	}
+	if c.Remediation != "" {
+		b.WriteString("🔧 Try: " + c.Remediation + "\n")
+	}
+	if c.Docs != "" {
+		b.WriteString("📖 Docs: " + c.Docs + "\n")
+	}
+	fmt.Fprintf(&b, "Error code: %s (%s, %s)", c.Code, c.Category, c.Severity)
+	return b.String()
+}
 
-	// No pattern matched, return original output
-	return result.FormatOutput()
+// errorResult returns the tool error for a failed command, classified with
+// the patterns that apply to the called tool. The text carries the
+// suggestions; the classification is attached under _meta for clients that
+// handle errors programmatically.
+func errorResult(req mcp.CallToolRequest, result executor.Result) *mcp.CallToolResult {
+	c := errorPatterns.Classify(errorText(result), result.ExitCode, req.Params.Name)
+	toolResult := mcp.NewToolResultError(enhanceError(result, c))
+	if c != nil {
+		toolResult.Meta = mcp.NewMetaFromMap(map[string]any{errorMetaKey: c})
+	}
+	return toolResult
 }
 
 // containsIgnoreCase checks if text contains pattern (case-insensitive)
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"devspace-mcp/errorpatterns"
 	"devspace-mcp/executor"
 )
 
@@ -214,3 +215,187 @@ func TestContainsIgnoreCase(t *testing.T) {
 		})
 	}
 }
+
+// TestClassifyErrorCorpus classifies error output captured from kubectl,
+// devspace, helm and the executor
+func TestClassifyErrorCorpus(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   executor.Result
+		wantCode string
+	}{
+		{
+			name:     "kubectl RBAC denial",
+			result:   executor.Result{Stderr: `Error from server (Forbidden): pods is forbidden: User "system:serviceaccount:ci:deployer" cannot list resource "pods" in API group "" in the namespace "prod"`, ExitCode: 1},
+			wantCode: "RBAC_FORBIDDEN",
+		},
+		{
+			name: "API server not running",
+			result: executor.Result{Stderr: `E1017 09:00:00.000000   12345 memcache.go:265] couldn't get current server API group list: Get "https://127.0.0.1:6443/api?timeout=32s": dial tcp 127.0.0.1:6443: connect: connection refused
+The connection to the server 127.0.0.1:6443 was refused - did you specify the right host or port?`, ExitCode: 1},
+			wantCode: "API_SERVER_REFUSED",
+		},
+		{
+			name:     "cluster endpoint does not resolve",
+			result:   executor.Result{Stderr: "Unable to connect to the server: dial tcp: lookup api.prod.example.com on 10.0.0.2:53: no such host", ExitCode: 1},
+			wantCode: "DNS_LOOKUP_FAILED",
+		},
+		{
+			name:     "cluster endpoint times out",
+			result:   executor.Result{Stderr: "Unable to connect to the server: dial tcp 10.1.2.3:443: i/o timeout", ExitCode: 1},
+			wantCode: "CLUSTER_UNREACHABLE",
+		},
+		{
+			name: "expired AWS SSO session",
+			result: executor.Result{Stderr: `Error when retrieving token from sso: Token has expired and refresh failed
+Unable to connect to the server: getting credentials: exec: executable aws failed with exit code 255`, ExitCode: 1},
+			wantCode: "AWS_SSO_EXPIRED",
+		},
+		{
+			name:     "missing credentials",
+			result:   executor.Result{Stderr: "error: You must be logged in to the server (Unauthorized)", ExitCode: 1},
+			wantCode: "UNAUTHORIZED",
+		},
+		{
+			name:     "missing namespace",
+			result:   executor.Result{Stderr: `Error from server (NotFound): namespaces "feature-x" not found`, ExitCode: 1},
+			wantCode: "NAMESPACE_NOT_FOUND",
+		},
+		{
+			name:     "missing pod",
+			result:   executor.Result{Stderr: `Error from server (NotFound): pods "api-0" not found`, ExitCode: 1},
+			wantCode: "RESOURCE_NOT_FOUND",
+		},
+		{
+			name:     "missing deployment",
+			result:   executor.Result{Stderr: `Error from server (NotFound): deployments.apps "api" not found`, ExitCode: 1},
+			wantCode: "RESOURCE_NOT_FOUND",
+		},
+		{
+			name:     "no devspace.yaml",
+			result:   executor.Result{Stderr: "fatal Cannot find a devspace.yaml in /work/api or any parent directory", ExitCode: 1},
+			wantCode: "DEVSPACE_CONFIG_MISSING",
+		},
+		{
+			name:     "broken devspace.yaml",
+			result:   executor.Result{Stderr: "fatal error loading config: parsing devspace.yaml: yaml: line 12: did not find expected key", ExitCode: 1},
+			wantCode: "DEVSPACE_CONFIG_ERROR",
+		},
+		{
+			name:     "helm upgrade deadline",
+			result:   executor.Result{Stderr: "Error: UPGRADE FAILED: context deadline exceeded", ExitCode: 1},
+			wantCode: "DEADLINE_EXCEEDED",
+		},
+		{
+			name:     "executor timeout",
+			result:   executor.Result{Stdout: "Building image api...", Error: "command timed out", ExitCode: -2},
+			wantCode: "COMMAND_TIMEOUT",
+		},
+		{
+			name:     "cancelled request",
+			result:   executor.Result{Error: "command was cancelled", ExitCode: -3},
+			wantCode: "COMMAND_CANCELLED",
+		},
+		{
+			name:     "missing binary",
+			result:   executor.Result{Error: `exec: "devspace": executable file not found in $PATH`, ExitCode: -1},
+			wantCode: "BINARY_NOT_FOUND",
+		},
+		{
+			name:     "full disk during build",
+			result:   executor.Result{Stderr: "failed to register layer: write /var/lib/docker/overlay2/3f2a/diff/app/node_modules/x.js: no space left on device", ExitCode: 1},
+			wantCode: "DISK_FULL",
+		},
+		{
+			name: "image tag does not exist",
+			result: executor.Result{Stderr: `Failed to pull image "registry.example.com/web:latest": rpc error: code = NotFound desc = failed to resolve reference "registry.example.com/web:latest": not found
+Warning  Failed  ErrImagePull`, ExitCode: 1},
+			wantCode: "ERR_IMAGE_PULL",
+		},
+		{
+			name:     "exec into a container killed for memory",
+			result:   executor.Result{Stderr: "command terminated with exit code 137\nLast State: Terminated, Reason: OOMKilled", ExitCode: 137},
+			wantCode: "OOM_KILLED",
+		},
+		{
+			name:     "exec into a killed container",
+			result:   executor.Result{Stderr: "command terminated with exit code 137", ExitCode: 137},
+			wantCode: "PROCESS_KILLED",
+		},
+		{
+			name:     "expired cluster certificate",
+			result:   executor.Result{Stderr: "Unable to connect to the server: x509: certificate has expired or is not yet valid: current time 2026-10-17T09:00:00Z is after 2026-09-01T00:00:00Z", ExitCode: 1},
+			wantCode: "CERTIFICATE_EXPIRED",
+		},
+		{
+			name:     "unknown certificate authority",
+			result:   executor.Result{Stderr: "Unable to connect to the server: x509: certificate signed by unknown authority", ExitCode: 1},
+			wantCode: "CERTIFICATE_INVALID",
+		},
+		{
+			name:   "unknown error",
+			result: executor.Result{Stderr: "Some random error message", ExitCode: 1},
+		},
+		{
+			name:   "success",
+			result: executor.Result{Stdout: "Command executed successfully"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ClassifyError(tt.result)
+			if tt.wantCode == "" {
+				if c != nil {
+					t.Errorf("expected no classification, got %+v", c)
+				}
+				return
+			}
+			if c == nil {
+				t.Fatalf("expected %s, got no classification", tt.wantCode)
+			}
+			if c.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", c.Code, tt.wantCode)
+			}
+			if c.Category == "" || c.Severity == "" || len(c.Suggestions) == 0 {
+				t.Errorf("incomplete classification: %+v", c)
+			}
+		})
+	}
+}
+
+func TestClassifyErrorCombinesSuggestions(t *testing.T) {
+	c := ClassifyError(executor.Result{Stderr: "Reason: OOMKilled", ExitCode: 137})
+	want := []string{
+		"Container was killed due to out of memory. Increase memory limits or optimize application memory usage.",
+		"The process was killed with SIGKILL (exit code 137), usually because it ran out of memory.",
+	}
+	if strings.Join(c.Suggestions, "\n") != strings.Join(want, "\n") {
+		t.Errorf("suggestions = %q, want %q", c.Suggestions, want)
+	}
+	if c.Docs == "" {
+		t.Error("expected docs for OOMKilled")
+	}
+
+	text := EnhanceError(executor.Result{Stderr: "Reason: OOMKilled", ExitCode: 137})
+	for _, part := range []string{"💡 Suggestion: Container was killed", "   • The process was killed", "📖 Docs: https://", "Error code: OOM_KILLED (workload, critical)"} {
+		if !strings.Contains(text, part) {
+			t.Errorf("text should contain %q, got:\n%s", part, text)
+		}
+	}
+}
+
+func TestErrorResultCarriesClassification(t *testing.T) {
+	result := errorResult(newRequest(nil), executor.Result{Stderr: `Error from server (NotFound): namespaces "dev" not found`, ExitCode: 1})
+	if !result.IsError || result.Meta == nil {
+		t.Fatalf("expected an error result with _meta, got %+v", result)
+	}
+	c, ok := result.Meta.AdditionalFields[errorMetaKey].(*errorpatterns.Classification)
+	if !ok || c.Code != "NAMESPACE_NOT_FOUND" || c.Remediation != "kubectl create namespace dev" {
+		t.Errorf("unexpected classification: %+v", result.Meta.AdditionalFields)
+	}
+
+	if result := errorResult(newRequest(nil), executor.Result{Stderr: "boom", ExitCode: 1}); result.Meta != nil {
+		t.Errorf("unclassified errors should have no _meta, got %+v", result.Meta)
+	}
+}
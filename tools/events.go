@@ -0,0 +1,403 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Patterns of generated pod and ReplicaSet names, used to find the workload
+// of pods that no longer exist
+var (
+	deploymentPodRegex  = regexp.MustCompile(`^(.+)-[a-z0-9]{6,10}-[a-z0-9]{5}$`)
+	statefulSetPodRegex = regexp.MustCompile(`^(.+)-[0-9]+$`)
+	replicaSetRegex     = regexp.MustCompile(`^(.+)-[a-z0-9]{6,10}$`)
+)
+
+// eventObject holds the fields of an Event resource. Older events only set
+// count and the timestamps, events.k8s.io events use eventTime and series.
+type eventObject struct {
+	Metadata struct {
+		CreationTimestamp string `yaml:"creationTimestamp"`
+	} `yaml:"metadata"`
+	InvolvedObject struct {
+		Kind string `yaml:"kind"`
+		Name string `yaml:"name"`
+	} `yaml:"involvedObject"`
+	Type           string `yaml:"type"`
+	Reason         string `yaml:"reason"`
+	Message        string `yaml:"message"`
+	Count          int    `yaml:"count"`
+	FirstTimestamp string `yaml:"firstTimestamp"`
+	LastTimestamp  string `yaml:"lastTimestamp"`
+	EventTime      string `yaml:"eventTime"`
+	Series         *struct {
+		Count            int    `yaml:"count"`
+		LastObservedTime string `yaml:"lastObservedTime"`
+	} `yaml:"series"`
+}
+
+// eventInfo is an event, deduplicated across the objects of a workload
+type eventInfo struct {
+	Type    string   `json:"type"`
+	Reason  string   `json:"reason"`
+	Message string   `json:"message"`
+	Count   int      `json:"count" jsonschema:"description=How often the event occurred across all objects"`
+	Objects []string `json:"objects" jsonschema:"description=Involved objects such as pod/api-7d9f8b6c5d-x2kqp"`
+	// FirstSeen and LastSeen are RFC 3339 timestamps
+	FirstSeen string `json:"first_seen,omitempty"`
+	LastSeen  string `json:"last_seen,omitempty"`
+
+	first, last time.Time
+}
+
+// eventGroup holds the events of one workload
+type eventGroup struct {
+	Workload string      `json:"workload" jsonschema:"description=Owning workload such as deployment/api or the involved object itself"`
+	Warnings int         `json:"warnings" jsonschema:"description=Number of warning occurrences"`
+	Events   []eventInfo `json:"events"`
+}
+
+// eventsOutput is the result of devspace_events
+type eventsOutput struct {
+	Namespace string       `json:"namespace"`
+	Groups    []eventGroup `json:"groups"`
+}
+
+// eventFilter selects the events to report
+type eventFilter struct {
+	object string
+	since  time.Duration
+}
+
+// DevspaceEventsTool returns the tool definition for listing Kubernetes events
+func DevspaceEventsTool() mcp.Tool {
+	return mcp.NewTool("devspace_events",
+		mcp.WithDescription("Lists Kubernetes events of a namespace using kubectl, deduplicated and grouped per owning workload (e.g. 'deployment/api: 14× FailedScheduling'). Usually the quickest way to see why pods do not start when devspace_analyze reports nothing."),
+		mcp.WithOutputSchema[eventsOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to read events from"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
+		mcp.WithString("involved_object",
+			mcp.Description("Only events of this object or workload: a name or kind/name such as pod/api-7d9f8b6c5d-x2kqp or deployment/api"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Only events of this type: Warning or Normal"),
+		),
+		mcp.WithString("reason",
+			mcp.Description("Only events with this reason (e.g., 'FailedScheduling', 'BackOff')"),
+		),
+		mcp.WithString("since",
+			mcp.Description("Only events seen within this duration (e.g., '30m', '2h', '1d')"),
+		),
+	)
+}
+
+// DevspaceEventsHandler handles the events command using kubectl
+func DevspaceEventsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	namespace := req.GetString("namespace", "")
+	if namespace == "" {
+		return mcp.NewToolResultError("namespace parameter is required; pass it or set it with devspace_set_context"), nil
+	}
+	if err := ValidateStringParam("namespace", namespace); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	scope := []string{"-n", namespace}
+	if kubeContext := req.GetString("kube_context", ""); kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		scope = append(scope, "--context", kubeContext)
+	}
+
+	var selectors []string
+	if eventType := req.GetString("type", ""); eventType != "" {
+		if eventType != "Warning" && eventType != "Normal" {
+			return mcp.NewToolResultError("type must be Warning or Normal"), nil
+		}
+		selectors = append(selectors, "type="+eventType)
+	}
+	if reason := req.GetString("reason", ""); reason != "" {
+		if err := ValidateStringParam("reason", reason); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		selectors = append(selectors, "reason="+reason)
+	}
+
+	var filter eventFilter
+	if object := req.GetString("involved_object", ""); object != "" {
+		if err := ValidateStringParam("involved_object", object); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		filter.object = object
+	}
+	if since := req.GetString("since", ""); since != "" {
+		d, err := parseSince(since)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		filter.since = d
+	}
+
+	timeout := commandTimeout("devspace_events")
+	args := append([]string{"get", "events"}, scope...)
+	if len(selectors) > 0 {
+		args = append(args, "--field-selector", strings.Join(selectors, ","))
+	}
+	result := executeKubectl(ctx, timeout, append(args, "-o", "json")...)
+	if !result.Success() {
+		return errorResult(req, result), nil
+	}
+	events, err := parseEventObjects(result.Stdout)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("could not parse kubectl events: %v", err)), nil
+	}
+
+	// Pods are looked up to find their owners; without them workloads are
+	// derived from the pod names
+	var owners map[string]string
+	if pods := executeKubectl(ctx, timeout, append(append([]string{"get", "pods"}, scope...), "-o", "json")...); pods.Success() {
+		owners = podOwners(pods.Stdout)
+	}
+
+	now := time.Now()
+	output := eventsOutput{Namespace: namespace, Groups: groupEvents(events, owners, filter, now)}
+	return mcp.NewToolResultStructured(output, formatEvents(output, now)), nil
+}
+
+// parseSince parses a duration, allowing days such as 1d
+func parseSince(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid since %q: use a duration such as 30m, 2h or 1d", value)
+	}
+	return d, nil
+}
+
+// parseEventObjects parses 'kubectl get events -o json' output
+func parseEventObjects(output string) ([]eventObject, error) {
+	var list struct {
+		Items []eventObject `yaml:"items"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &list); err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// podOwners maps pod names to their workload, e.g. deployment/api for a pod
+// owned by one of its ReplicaSets
+func podOwners(output string) map[string]string {
+	var list struct {
+		Items []podObject `yaml:"items"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &list); err != nil {
+		return nil
+	}
+	owners := make(map[string]string)
+	for _, p := range list.Items {
+		if owner := p.workload(); owner != "" {
+			owners[p.Metadata.Name] = owner
+		}
+	}
+	return owners
+}
+
+// workload returns the workload owning a pod, or "" for bare pods
+func (p podObject) workload() string {
+	for _, ref := range p.Metadata.OwnerReferences {
+		if ref.Kind == "ReplicaSet" {
+			if hash := p.Metadata.Labels["pod-template-hash"]; hash != "" {
+				return "deployment/" + strings.TrimSuffix(ref.Name, "-"+hash)
+			}
+		}
+		return strings.ToLower(ref.Kind) + "/" + ref.Name
+	}
+	return ""
+}
+
+// workloadOf returns the workload an involved object belongs to
+func workloadOf(kind, name string, owners map[string]string) string {
+	switch kind {
+	case "Pod":
+		if owner, ok := owners[name]; ok {
+			return owner
+		}
+		if m := deploymentPodRegex.FindStringSubmatch(name); m != nil {
+			return "deployment/" + m[1]
+		}
+		if m := statefulSetPodRegex.FindStringSubmatch(name); m != nil {
+			return "statefulset/" + m[1]
+		}
+	case "ReplicaSet":
+		if m := replicaSetRegex.FindStringSubmatch(name); m != nil {
+			return "deployment/" + m[1]
+		}
+	}
+	return strings.ToLower(kind) + "/" + name
+}
+
+// matchesObject reports whether an event of object in workload matches the
+// involved_object filter, given as name or kind/name
+func matchesObject(filter, object, workload string) bool {
+	if filter == "" {
+		return true
+	}
+	for _, candidate := range []string{object, workload} {
+		if strings.EqualFold(filter, candidate) {
+			return true
+		}
+		if _, name, ok := strings.Cut(candidate, "/"); ok && filter == name {
+			return true
+		}
+	}
+	return false
+}
+
+// groupEvents deduplicates events with the same type, reason and message per
+// workload, adding up their counts. Groups with warnings come first.
+func groupEvents(events []eventObject, owners map[string]string, filter eventFilter, now time.Time) []eventGroup {
+	groups := []eventGroup{}
+	groupIndex := make(map[string]int)
+	for _, e := range events {
+		object := strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name
+		workload := workloadOf(e.InvolvedObject.Kind, e.InvolvedObject.Name, owners)
+		if !matchesObject(filter.object, object, workload) {
+			continue
+		}
+		first, last, count := e.occurrences()
+		if filter.since > 0 && !last.IsZero() && now.Sub(last) > filter.since {
+			continue
+		}
+
+		gi, ok := groupIndex[workload]
+		if !ok {
+			gi = len(groups)
+			groupIndex[workload] = gi
+			groups = append(groups, eventGroup{Workload: workload})
+		}
+		g := &groups[gi]
+		if e.Type == "Warning" {
+			g.Warnings += count
+		}
+
+		var info *eventInfo
+		for i := range g.Events {
+			if g.Events[i].Type == e.Type && g.Events[i].Reason == e.Reason && g.Events[i].Message == e.Message {
+				info = &g.Events[i]
+				break
+			}
+		}
+		if info == nil {
+			g.Events = append(g.Events, eventInfo{Type: e.Type, Reason: e.Reason, Message: e.Message})
+			info = &g.Events[len(g.Events)-1]
+		}
+		info.Count += count
+		if !slices.Contains(info.Objects, object) {
+			info.Objects = append(info.Objects, object)
+		}
+		if !first.IsZero() && (info.first.IsZero() || first.Before(info.first)) {
+			info.first = first
+		}
+		if last.After(info.last) {
+			info.last = last
+		}
+	}
+
+	for gi := range groups {
+		events := groups[gi].Events
+		for i := range events {
+			if !events[i].first.IsZero() {
+				events[i].FirstSeen = events[i].first.Format(time.RFC3339)
+			}
+			if !events[i].last.IsZero() {
+				events[i].LastSeen = events[i].last.Format(time.RFC3339)
+			}
+		}
+		sort.SliceStable(events, func(i, j int) bool {
+			if (events[i].Type == "Warning") != (events[j].Type == "Warning") {
+				return events[i].Type == "Warning"
+			}
+			return events[i].Count > events[j].Count
+		})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Warnings != groups[j].Warnings {
+			return groups[i].Warnings > groups[j].Warnings
+		}
+		return groups[i].Workload < groups[j].Workload
+	})
+	return groups
+}
+
+// occurrences returns when an event was first and last seen and how often
+func (e eventObject) occurrences() (first, last time.Time, count int) {
+	parse := func(values ...string) time.Time {
+		for _, v := range values {
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		}
+		return time.Time{}
+	}
+	count = max(e.Count, 1)
+	var observed string
+	if e.Series != nil {
+		count = max(e.Series.Count, count)
+		observed = e.Series.LastObservedTime
+	}
+	first = parse(e.FirstTimestamp, e.EventTime, e.Metadata.CreationTimestamp)
+	last = parse(observed, e.LastTimestamp, e.EventTime, e.Metadata.CreationTimestamp)
+	return first, last, count
+}
+
+// formatEvents renders the event groups, one line per deduplicated event
+func formatEvents(out eventsOutput, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Events in namespace %s\n", out.Namespace)
+	if len(out.Groups) == 0 {
+		b.WriteString("\nNo events found.\n")
+		return b.String()
+	}
+	for _, g := range out.Groups {
+		fmt.Fprintf(&b, "\n## %s\n", g.Workload)
+		for _, e := range g.Events {
+			icon := "ℹ️"
+			if e.Type == "Warning" {
+				icon = "⚠️"
+			}
+			fmt.Fprintf(&b, "%s %d× %s: %s", icon, e.Count, e.Reason, strings.TrimSpace(e.Message))
+			var details []string
+			if len(e.Objects) > 1 || e.Objects[0] != g.Workload {
+				details = append(details, strings.Join(e.Objects, ", "))
+			}
+			if !e.last.IsZero() {
+				details = append(details, "last seen "+formatAge(now.Sub(e.last))+" ago")
+			}
+			if len(details) > 0 {
+				fmt.Fprintf(&b, " (%s)", strings.Join(details, "; "))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
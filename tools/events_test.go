@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"devspace-mcp/executor"
+)
+
+func TestGroupEvents(t *testing.T) {
+	events, err := parseEventObjects(readFixture(t, "kubectl_events.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+
+	groups := groupEvents(events, nil, eventFilter{}, now)
+	var got []string
+	for _, g := range groups {
+		for _, e := range g.Events {
+			got = append(got, fmt.Sprintf("%s %d× %s %s", g.Workload, e.Count, e.Reason, strings.Join(e.Objects, ",")))
+		}
+	}
+	want := []string{
+		"deployment/worker 40× BackOff pod/worker-5b6f7c8d9-abcde",
+		"deployment/worker 1× Scheduled pod/worker-5b6f7c8d9-abcde",
+		"deployment/api 14× FailedScheduling pod/api-7d9f8b6c5d-x2kqp,pod/api-7d9f8b6c5d-b7n2m",
+		"deployment/api 1× ScalingReplicaSet deployment/api",
+		"statefulset/db 1× Pulled pod/db-0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("groups:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	api := groups[1].Events[0]
+	if api.FirstSeen != "2026-10-17T09:00:00Z" || api.LastSeen != "2026-10-17T09:45:00Z" {
+		t.Errorf("unexpected time window: %s - %s", api.FirstSeen, api.LastSeen)
+	}
+
+	filtered := groupEvents(events, nil, eventFilter{object: "deployment/api", since: time.Hour}, now)
+	if len(filtered) != 1 || len(filtered[0].Events) != 1 || filtered[0].Events[0].Reason != "FailedScheduling" {
+		t.Errorf("filter by workload and time window failed: %+v", filtered)
+	}
+	byPod := groupEvents(events, nil, eventFilter{object: "db-0"}, now)
+	if len(byPod) != 1 || byPod[0].Workload != "statefulset/db" {
+		t.Errorf("filter by pod name failed: %+v", byPod)
+	}
+}
+
+func TestPodOwners(t *testing.T) {
+	output := `{"items": [
+		{"metadata": {"name": "api-7d9f8b6c5d-x2kqp", "labels": {"pod-template-hash": "7d9f8b6c5d"},
+			"ownerReferences": [{"kind": "ReplicaSet", "name": "api-7d9f8b6c5d"}]}},
+		{"metadata": {"name": "backup-28812345-q8x7z", "ownerReferences": [{"kind": "Job", "name": "backup-28812345"}]}},
+		{"metadata": {"name": "debug"}}
+	]}`
+	owners := podOwners(output)
+	want := map[string]string{"api-7d9f8b6c5d-x2kqp": "deployment/api", "backup-28812345-q8x7z": "job/backup-28812345"}
+	if len(owners) != len(want) {
+		t.Fatalf("owners = %v, want %v", owners, want)
+	}
+	for pod, workload := range want {
+		if owners[pod] != workload {
+			t.Errorf("owner of %s = %s, want %s", pod, owners[pod], workload)
+		}
+	}
+}
+
+func TestDevspaceEventsHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "events"}, executor.Result{Stdout: readFixture(t, "kubectl_events.json")})
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stderr: "forbidden", ExitCode: 1})
+
+	result, _ := DevspaceEventsHandler(ctx, newRequest(map[string]any{
+		"namespace":    "dev",
+		"kube_context": "kind-dev",
+		"type":         "Warning",
+		"reason":       "FailedScheduling",
+	}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	calls := fake.Calls()
+	wantArgs := "get events -n dev --context kind-dev --field-selector type=Warning,reason=FailedScheduling -o json"
+	if len(calls) != 2 || strings.Join(calls[0].Args, " ") != wantArgs {
+		t.Fatalf("unexpected kubectl calls: %+v", calls)
+	}
+
+	// The fake ignores the field selector, so all fixture events are returned
+	text := resultText(result)
+	if !strings.Contains(text, "## deployment/api\n⚠️ 14× FailedScheduling: 0/3 nodes are available: 3 Insufficient memory. (pod/api-7d9f8b6c5d-x2kqp, pod/api-7d9f8b6c5d-b7n2m; last seen") {
+		t.Errorf("unexpected text: %s", text)
+	}
+	encoded, _ := json.Marshal(result.StructuredContent)
+	if !strings.Contains(string(encoded), `"workload":"deployment/api","warnings":14`) {
+		t.Errorf("unexpected structured content: %s", encoded)
+	}
+}
+
+func TestDevspaceEventsValidation(t *testing.T) {
+	_, ctx := useFakeRunner(t)
+	for _, args := range []map[string]any{
+		{},
+		{"namespace": "dev", "type": "Error"},
+		{"namespace": "dev", "since": "yesterday"},
+	} {
+		result, _ := DevspaceEventsHandler(ctx, newRequest(args))
+		if !result.IsError {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
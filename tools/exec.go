@@ -2,9 +2,9 @@ package tools
 
 import (
 	"context"
-	"time"
 
 	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -13,16 +13,21 @@ import (
 func DevspaceExecTool() mcp.Tool {
 	return mcp.NewTool("devspace_exec",
 		mcp.WithDescription("Execute a command in a container using DevSpace. Uses 'devspace enter' with non-interactive mode. Useful for running debugging commands, checking file contents, or testing connectivity inside pods."),
+		mcp.WithOutputSchema[execOutput](),
+		withCategory(serverconfig.CategoryExec),
 		mcp.WithString("command",
 			mcp.Required(),
 			mcp.Description("Command to execute in the container (e.g., 'ls -la', 'curl localhost:8080', 'cat /etc/hosts')"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace"),
 		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
 		mcp.WithString("pod",
 			mcp.Description("Specific pod name to execute command in"),
 		),
@@ -64,6 +69,14 @@ func DevspaceExecHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Cal
 		args = append(args, "--namespace", namespace)
 	}
 
+	// Add kube context if specified
+	if kubeContext := req.GetString("kube_context", ""); kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--kube-context", kubeContext)
+	}
+
 	// Add pod if specified
 	if pod := req.GetString("pod", ""); pod != "" {
 		if err := ValidateStringParam("pod", pod); err != nil {
@@ -111,11 +124,17 @@ func DevspaceExecHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Cal
 	workingDir := req.GetString("working_dir", "")
 
 	// Execute with extended timeout for exec commands
-	result := executor.ExecuteWithOptions(ctx, 5*time.Minute, workingDir, args...)
+	result := executeDevspace(ctx, commandTimeout("devspace_exec"), workingDir, args...)
 
 	if !result.Success() {
-		return mcp.NewToolResultError(EnhanceError(result)), nil
+		return errorResult(req, result), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	output := execOutput{
+		Command:  executor.Command{Binary: executor.DevspaceBinary, Args: args}.String(),
+		ExitCode: result.ExitCode,
+		Stdout:   result.Stdout,
+		Stderr:   result.Stderr,
+	}
+	return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
 }
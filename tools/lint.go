@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"devspace-mcp/config"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// lintOutput is the result of devspace_lint_config
+type lintOutput struct {
+	Config   string         `json:"config"`
+	Valid    bool           `json:"valid" jsonschema:"description=True when no errors were found; warnings do not make a config invalid"`
+	Errors   int            `json:"errors"`
+	Warnings int            `json:"warnings"`
+	Issues   []config.Issue `json:"issues"`
+}
+
+// DevspaceLintConfigTool returns the tool definition for linting devspace.yaml
+func DevspaceLintConfigTool() mcp.Tool {
+	return mcp.NewTool("devspace_lint_config",
+		mcp.WithDescription("Validate devspace.yaml against the v2beta1 schema and check it for semantic problems such as undefined images, dev selectors matching no deployment, duplicate local ports, missing sync paths, unused vars and profile patches on missing paths. Does not contact the cluster."),
+		mcp.WithOutputSchema[lintOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+	)
+}
+
+// DevspaceLintConfigHandler handles the lint config command
+func DevspaceLintConfigHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	loc, err := locateConfig(ctx, req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	issues, err := config.Lint(loc.Path)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	output := lintOutput{Config: loc.Path, Issues: issues}
+	if output.Issues == nil {
+		output.Issues = []config.Issue{}
+	}
+	for _, issue := range issues {
+		if issue.Severity == config.SeverityError {
+			output.Errors++
+		} else {
+			output.Warnings++
+		}
+	}
+	output.Valid = output.Errors == 0
+	return mcp.NewToolResultStructured(output, formatLint(output)), nil
+}
+
+// formatLint renders lint issues as file:line:column lines
+func formatLint(out lintOutput) string {
+	var b strings.Builder
+	switch {
+	case len(out.Issues) == 0:
+		b.WriteString("✅ No problems found\n")
+	case out.Valid:
+		fmt.Fprintf(&b, "⚠️ %d warnings\n", out.Warnings)
+	default:
+		fmt.Fprintf(&b, "❌ %d errors, %d warnings\n", out.Errors, out.Warnings)
+	}
+	for _, issue := range out.Issues {
+		icon := "⚠️"
+		if issue.Severity == config.SeverityError {
+			icon = "❌"
+		}
+		position := out.Config
+		if issue.Line > 0 {
+			position = fmt.Sprintf("%s:%d:%d", out.Config, issue.Line, issue.Column)
+		}
+		fmt.Fprintf(&b, "\n%s %s", icon, position)
+		if issue.Path != "" {
+			fmt.Fprintf(&b, " %s", issue.Path)
+		}
+		fmt.Fprintf(&b, ": %s [%s]", issue.Message, issue.Rule)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDevspaceLintConfigHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	dir := t.TempDir()
+	config := "version: v2beta1\nname: shop\nimages:\n  api:\n    image: shop/api\n    dockerfil: Dockerfile\nvars:\n  UNUSED: x\n"
+	if err := os.WriteFile(filepath.Join(dir, "devspace.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := DevspaceLintConfigHandler(ctx, newRequest(map[string]any{"working_dir": dir}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	if len(fake.Calls()) != 0 {
+		t.Errorf("linting must not run commands, got %+v", fake.Calls())
+	}
+
+	output := result.StructuredContent.(lintOutput)
+	if output.Valid || output.Errors != 1 || output.Warnings != 1 {
+		t.Errorf("unexpected summary: %+v", output)
+	}
+	text := resultText(result)
+	want := filepath.Join(dir, "devspace.yaml") + `:6:5 images.api: unknown field "dockerfil", did you mean "dockerfile"? [schema]`
+	if !strings.Contains(text, want) {
+		t.Errorf("text should contain %q, got %s", want, text)
+	}
+}
+
+func TestDevspaceLintConfigHandlerClean(t *testing.T) {
+	_, ctx := useFakeRunner(t)
+	dir := nativeProject(t)
+
+	result, _ := DevspaceLintConfigHandler(ctx, newRequest(map[string]any{"working_dir": dir}))
+	output := result.StructuredContent.(lintOutput)
+	if !output.Valid || len(output.Issues) != 0 {
+		t.Errorf("expected a clean config, got %+v", output.Issues)
+	}
+	if !strings.HasPrefix(resultText(result), "✅ No problems found") {
+		t.Errorf("unexpected text: %s", resultText(result))
+	}
+}
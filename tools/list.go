@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -12,6 +13,8 @@ import (
 func DevspaceListNamespacesTool() mcp.Tool {
 	return mcp.NewTool("devspace_list_namespaces",
 		mcp.WithDescription("List Kubernetes namespaces"),
+		mcp.WithOutputSchema[namespacesOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("kube_context",
 			mcp.Description("Kubernetes context to use"),
 		),
@@ -29,37 +32,43 @@ func DevspaceListNamespacesHandler(ctx context.Context, req mcp.CallToolRequest)
 		args = append(args, "--kube-context", kubeContext)
 	}
 
-	result := executor.Execute(ctx, args...)
+	result := executeDevspace(ctx, commandTimeout("devspace_list_namespaces"), "", args...)
 
 	if !result.Success() {
 		return mcp.NewToolResultError(result.FormatOutput()), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	output := namespacesOutput{Namespaces: parseNamespaces(result.Stdout)}
+	return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
 }
 
 // DevspaceListContextsTool returns the tool definition for listing contexts
 func DevspaceListContextsTool() mcp.Tool {
 	return mcp.NewTool("devspace_list_contexts",
 		mcp.WithDescription("List available Kubernetes contexts"),
+		mcp.WithOutputSchema[contextsOutput](),
+		withCategory(serverconfig.CategoryRead),
 	)
 }
 
 // DevspaceListContextsHandler handles the list contexts command
 func DevspaceListContextsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	result := executor.Execute(ctx, "list", "contexts")
+	result := executeDevspace(ctx, commandTimeout("devspace_list_contexts"), "", "list", "contexts")
 
 	if !result.Success() {
 		return mcp.NewToolResultError(result.FormatOutput()), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	output := contextsOutput{Contexts: parseContexts(result.Stdout)}
+	return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
 }
 
 // DevspaceListDeploymentsTool returns the tool definition for listing deployments
 func DevspaceListDeploymentsTool() mcp.Tool {
 	return mcp.NewTool("devspace_list_deployments",
 		mcp.WithDescription("List deployments and their status"),
+		mcp.WithOutputSchema[deploymentsOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace"),
 		),
@@ -67,7 +76,7 @@ func DevspaceListDeploymentsTool() mcp.Tool {
 			mcp.Description("Kubernetes context to use"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 	)
 }
@@ -91,21 +100,27 @@ func DevspaceListDeploymentsHandler(ctx context.Context, req mcp.CallToolRequest
 
 	workingDir := req.GetString("working_dir", "")
 
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
+	result := executeDevspace(ctx, commandTimeout("devspace_list_deployments"), workingDir, args...)
 
 	if !result.Success() {
 		return mcp.NewToolResultError(result.FormatOutput()), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	output := deploymentsOutput{Deployments: parseDeployments(result.Stdout)}
+	return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
 }
 
 // DevspaceListProfilesTool returns the tool definition for listing profiles
 func DevspaceListProfilesTool() mcp.Tool {
 	return mcp.NewTool("devspace_list_profiles",
 		mcp.WithDescription("List available DevSpace profiles from devspace.yaml"),
+		mcp.WithOutputSchema[profilesOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+		mcp.WithBoolean("offline",
+			mcp.Description(offlineDescription),
 		),
 	)
 }
@@ -114,24 +129,38 @@ func DevspaceListProfilesTool() mcp.Tool {
 func DevspaceListProfilesHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	workingDir := req.GetString("working_dir", "")
 
-	result := executor.ExecuteInDir(ctx, workingDir, "list", "profiles")
-
-	if !result.Success() {
-		return mcp.NewToolResultError(result.FormatOutput()), nil
+	var failed *executor.Result
+	if !req.GetBool("offline", false) {
+		result := executeDevspace(ctx, commandTimeout("devspace_list_profiles"), workingDir, "list", "profiles")
+		if result.Success() {
+			output := profilesOutput{Profiles: parseProfiles(result.Stdout), Source: sourceCLI}
+			return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
+		}
+		failed = &result
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	c, _, err := loadNative(ctx, req)
+	if err != nil {
+		return nativeError(err, failed), nil
+	}
+	output, text := nativeProfiles(c)
+	return nativeResult(output, text, failed), nil
 }
 
 // DevspaceListVarsTool returns the tool definition for listing variables
 func DevspaceListVarsTool() mcp.Tool {
 	return mcp.NewTool("devspace_list_vars",
 		mcp.WithDescription("List variables defined in the active devspace configuration"),
+		mcp.WithOutputSchema[varsOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("profile",
 			mcp.Description("Profile to use when resolving variables"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+		mcp.WithBoolean("offline",
+			mcp.Description(offlineDescription),
 		),
 	)
 }
@@ -149,11 +178,20 @@ func DevspaceListVarsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp
 
 	workingDir := req.GetString("working_dir", "")
 
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
-
-	if !result.Success() {
-		return mcp.NewToolResultError(result.FormatOutput()), nil
+	var failed *executor.Result
+	if !req.GetBool("offline", false) {
+		result := executeDevspace(ctx, commandTimeout("devspace_list_vars"), workingDir, args...)
+		if result.Success() {
+			output := varsOutput{Vars: parseVars(result.Stdout), Source: sourceCLI}
+			return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
+		}
+		failed = &result
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	resolved, err := resolveNative(ctx, req)
+	if err != nil {
+		return nativeError(err, failed), nil
+	}
+	output, text := nativeVars(resolved)
+	return nativeResult(output, text, failed), nil
 }
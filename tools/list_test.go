@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func TestDevspaceListDeploymentsHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"list", "deployments"}, executor.Result{Stdout: readFixture(t, "list_deployments.txt")})
+
+	result, err := DevspaceListDeploymentsHandler(ctx, newRequest(map[string]any{"namespace": "dev"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+
+	if !strings.Contains(resultText(result), "backend") {
+		t.Errorf("text content should keep the CLI table, got %s", resultText(result))
+	}
+
+	encoded, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"deployments":[{"name":"backend","type":"helm","deployed":true,"status":"Deployed"},` +
+		`{"name":"frontend","type":"kubectl","deployed":true,"status":"Deployed"},` +
+		`{"name":"worker","type":"helm","deployed":false,"status":"Skipped"},` +
+		`{"name":"db","type":"helm","deployed":true,"status":"Failed: timed out"}]}`
+	if string(encoded) != want {
+		t.Errorf("structured content = %s, want %s", encoded, want)
+	}
+}
+
+func TestDevspaceListDeploymentsHandlerEmpty(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"list", "deployments"}, executor.Result{Stdout: "info No deployments found\n"})
+
+	result, _ := DevspaceListDeploymentsHandler(ctx, newRequest(map[string]any{}))
+
+	encoded, _ := json.Marshal(result.StructuredContent)
+	if string(encoded) != `{"deployments":[]}` {
+		t.Errorf("structured content = %s, want an empty list", encoded)
+	}
+}
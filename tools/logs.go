@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -13,10 +14,15 @@ import (
 // DevspaceLogsTool returns the tool definition for getting pod logs
 func DevspaceLogsTool() mcp.Tool {
 	return mcp.NewTool("devspace_logs",
-		mcp.WithDescription("Get logs from a pod in the Kubernetes cluster with optional filtering by text or log level"),
+		mcp.WithDescription("Get logs from a pod in the Kubernetes cluster with optional filtering by text or log level. With a label selector or all_containers, the logs of every matching pod and container are fetched with kubectl and merged into one timestamp-ordered stream, each line prefixed with pod/container. previous, since, since_time and timestamps are not supported by devspace logs and are served by kubectl logs as well."),
+		mcp.WithOutputSchema[logsOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace"),
 		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
 		mcp.WithString("pod",
 			mcp.Description("Specific pod name to get logs from"),
 		),
@@ -24,10 +30,25 @@ func DevspaceLogsTool() mcp.Tool {
 			mcp.Description("Container name within the pod"),
 		),
 		mcp.WithString("label_selector",
-			mcp.Description("Label selector to filter pods (e.g., 'app=myapp')"),
+			mcp.Description("Label selector to filter pods (e.g., 'app=myapp'). The logs of all matching pods are merged by timestamp."),
+		),
+		mcp.WithBoolean("all_containers",
+			mcp.Description("Merge the logs of all containers of the pods instead of only the default or given container"),
+		),
+		mcp.WithBoolean("previous",
+			mcp.Description("Logs of the previous instance of the container, e.g. before a crash. Served by kubectl logs."),
+		),
+		mcp.WithString("since",
+			mcp.Description("Only logs newer than this duration (e.g., '10m', '2h', '1d'). Served by kubectl logs."),
+		),
+		mcp.WithString("since_time",
+			mcp.Description("Only logs after this RFC3339 time (e.g., '2026-10-17T09:00:00Z'). Served by kubectl logs."),
+		),
+		mcp.WithBoolean("timestamps",
+			mcp.Description("Prefix each line with its timestamp. Served by kubectl logs."),
 		),
 		mcp.WithNumber("lines",
-			mcp.Description("Maximum number of lines to return (default: 200, max: 10000)"),
+			mcp.Description("Maximum number of lines to return (default: 200, max: 10000). Merged logs split them evenly across containers."),
 		),
 		mcp.WithString("grep",
 			mcp.Description("Filter logs to only show lines containing this text (case-insensitive)"),
@@ -36,7 +57,7 @@ func DevspaceLogsTool() mcp.Tool {
 			mcp.Description("Filter logs by level: error, warn, or info"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 	)
 }
@@ -44,63 +65,166 @@ func DevspaceLogsTool() mcp.Tool {
 // DevspaceLogsHandler handles the logs command
 func DevspaceLogsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := []string{"logs"}
+	q := logsQuery{
+		namespace:     req.GetString("namespace", ""),
+		kubeContext:   req.GetString("kube_context", ""),
+		pod:           req.GetString("pod", ""),
+		container:     req.GetString("container", ""),
+		labelSelector: req.GetString("label_selector", ""),
+		allContainers: req.GetBool("all_containers", false),
+		previous:      req.GetBool("previous", false),
+		timestamps:    req.GetBool("timestamps", false),
+		grep:          req.GetString("grep", ""),
+		level:         req.GetString("grep_level", ""),
+	}
 
-	if namespace := req.GetString("namespace", ""); namespace != "" {
-		if err := ValidateStringParam("namespace", namespace); err != nil {
+	if q.namespace != "" {
+		if err := ValidateStringParam("namespace", q.namespace); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		args = append(args, "--namespace", namespace)
+		args = append(args, "--namespace", q.namespace)
 	}
-	if pod := req.GetString("pod", ""); pod != "" {
-		if err := ValidateStringParam("pod", pod); err != nil {
+	if q.kubeContext != "" {
+		if err := ValidateStringParam("kube_context", q.kubeContext); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		args = append(args, "--pod", pod)
+		args = append(args, "--kube-context", q.kubeContext)
 	}
-	if container := req.GetString("container", ""); container != "" {
-		if err := ValidateStringParam("container", container); err != nil {
+	if q.pod != "" {
+		if err := ValidateStringParam("pod", q.pod); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		args = append(args, "--container", container)
+		args = append(args, "--pod", q.pod)
 	}
-	if labelSelector := req.GetString("label_selector", ""); labelSelector != "" {
-		if err := ValidateStringParam("label_selector", labelSelector); err != nil {
+	if q.container != "" {
+		if err := ValidateStringParam("container", q.container); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		args = append(args, "--label-selector", labelSelector)
+		args = append(args, "--container", q.container)
+	}
+	if q.labelSelector != "" {
+		if err := ValidateStringParam("label_selector", q.labelSelector); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--label-selector", q.labelSelector)
 	}
 
-	lines := req.GetInt("lines", 200)
+	q.lines = req.GetInt("lines", 200)
 	// Ensure lines is positive and capped
-	if lines < 1 {
-		lines = 200
-	} else if lines > 10000 {
-		lines = 10000
+	if q.lines < 1 {
+		q.lines = 200
+	} else if q.lines > maxLogLines {
+		q.lines = maxLogLines
+	}
+	args = append(args, "--lines", fmt.Sprintf("%d", q.lines))
+
+	since := req.GetString("since", "")
+	sinceTime := req.GetString("since_time", "")
+	if since != "" && sinceTime != "" {
+		return mcp.NewToolResultError("since and since_time cannot be used together"), nil
+	}
+	if since != "" {
+		d, err := parseSince(since)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		q.since = d
+	}
+	if sinceTime != "" {
+		t, err := parseSinceTime(sinceTime)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		q.sinceTime = t
+	}
+
+	// devspace logs follows a single container of a single pod and only
+	// knows --lines
+	if q.labelSelector != "" || q.allContainers {
+		return aggregateLogs(ctx, req, q)
+	}
+	if q.needsKubectl() {
+		if q.pod == "" {
+			return mcp.NewToolResultError("previous, since, since_time and timestamps need a pod or label_selector: devspace logs does not support them and kubectl logs needs to know the pod"), nil
+		}
+		return kubectlLogs(ctx, req, q)
 	}
-	args = append(args, "--lines", fmt.Sprintf("%d", lines))
 
 	workingDir := req.GetString("working_dir", "")
 
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
+	result := executeDevspace(ctx, commandTimeout("devspace_logs"), workingDir, args...)
 
 	if !result.Success() {
-		return mcp.NewToolResultError(EnhanceError(result)), nil
+		return errorResult(req, result), nil
 	}
 
 	// Post-process output with filters if specified
 	output := result.Stdout
 
 	// Apply grep filter
-	if grepPattern := req.GetString("grep", ""); grepPattern != "" {
-		output = filterLines(output, grepPattern)
+	if q.grep != "" {
+		output = filterLines(output, q.grep)
 	}
 
 	// Apply level filter
-	if level := req.GetString("grep_level", ""); level != "" {
-		output = filterByLevel(output, level)
+	if q.level != "" {
+		output = filterByLevel(output, q.level)
+	}
+
+	logLines := splitLines(output)
+	return mcp.NewToolResultStructured(logsOutput{Lines: logLines, Count: len(logLines), Backend: logsBackendDevspace}, withBackend(output, logsBackendDevspace)), nil
+}
+
+// kubectlLogs reads the logs of a single pod with kubectl logs, for the
+// options devspace logs does not support
+func kubectlLogs(ctx context.Context, req mcp.CallToolRequest, q logsQuery) (*mcp.CallToolResult, error) {
+	args := append([]string{"logs", q.pod}, q.kubectlScope()...)
+	if q.container != "" {
+		args = append(args, "-c", q.container)
+	}
+	args = append(args, fmt.Sprintf("--tail=%d", q.lines))
+	args = append(args, q.kubectlFlags()...)
+	if q.timestamps {
+		args = append(args, "--timestamps")
 	}
 
-	return mcp.NewToolResultText(output), nil
+	result := executeKubectl(ctx, commandTimeout("devspace_logs"), args...)
+	if !result.Success() {
+		return errorResult(req, result), nil
+	}
+
+	output := result.Stdout
+	if q.grep != "" {
+		output = filterLines(output, q.grep)
+	}
+	if q.level != "" {
+		output = filterByLevel(output, q.level)
+	}
+
+	logLines := splitLines(output)
+	return mcp.NewToolResultStructured(logsOutput{Lines: logLines, Count: len(logLines), Backend: logsBackendKubectl}, withBackend(output, logsBackendKubectl)), nil
+}
+
+// withBackend appends the command that served a logs request to its output
+func withBackend(output, backend string) string {
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	if output != "" {
+		output += "\n"
+	}
+	return output + "Backend: " + backend + " logs"
+}
+
+// parseSinceTime parses an RFC3339 time, allowing minute precision such as
+// 2026-10-17T09:00Z
+func parseSinceTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid since_time %q: use an RFC3339 time such as 2026-10-17T09:00:00Z", value)
 }
 
 // filterLines filters log lines that contain the pattern (case-insensitive)
@@ -1,7 +1,11 @@
 package tools
 
 import (
+	"slices"
+	"strings"
 	"testing"
+
+	"devspace-mcp/executor"
 )
 
 func TestDevspaceLogsTool(t *testing.T) {
@@ -159,3 +163,173 @@ func TestFilterByLevelErrorPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestDevspaceLogsMergesPods(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "pods", "-n", "dev", "-l", "app=api"}, executor.Result{Stdout: readFixture(t, "kubectl_pods_api.json")})
+	fake.On("kubectl", []string{"logs", "api-7d9f-abcde", "-n", "dev", "-c", "api"}, executor.Result{Stdout: "" +
+		"2026-10-17T09:00:01.000000000Z GET /health 200\n" +
+		"2026-10-17T09:00:03.000000000Z ERROR db timeout\n" +
+		"    at query (db.go:42)\n"})
+	fake.On("kubectl", []string{"logs", "api-7d9f-fghij", "-n", "dev", "-c", "api"}, executor.Result{Stdout: "" +
+		"2026-10-17T09:00:02.000000000Z GET /orders 200\n" +
+		"2026-10-17T09:00:04.000000000Z ERROR payment declined\n"})
+
+	result, _ := DevspaceLogsHandler(ctx, newRequest(map[string]any{
+		"namespace": "dev", "label_selector": "app=api", "lines": 10,
+	}))
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	want := []string{
+		"[api-7d9f-abcde/api] GET /health 200",
+		"[api-7d9f-fghij/api] GET /orders 200",
+		"[api-7d9f-abcde/api] ERROR db timeout",
+		"[api-7d9f-abcde/api]     at query (db.go:42)",
+		"[api-7d9f-fghij/api] ERROR payment declined",
+	}
+	output := result.StructuredContent.(logsOutput)
+	if strings.Join(output.Lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines =\n%s\nwant\n%s", strings.Join(output.Lines, "\n"), strings.Join(want, "\n"))
+	}
+	if len(output.Sources) != 2 || output.Sources[0].Lines != 3 || output.Sources[1].Lines != 2 {
+		t.Errorf("sources = %+v", output.Sources)
+	}
+	for _, call := range fake.Calls() {
+		if call.Args[0] == "logs" && !slices.Contains(call.Args, "--tail=5") {
+			t.Errorf("each of 2 containers should get half of the 10 lines: %v", call.Args)
+		}
+	}
+
+	result, _ = DevspaceLogsHandler(ctx, newRequest(map[string]any{
+		"namespace": "dev", "label_selector": "app=api", "grep_level": "error",
+	}))
+	if got := result.StructuredContent.(logsOutput).Count; got != 2 {
+		t.Errorf("level filter should keep 2 lines, got %d", got)
+	}
+
+	result, _ = DevspaceLogsHandler(ctx, newRequest(map[string]any{
+		"namespace": "dev", "label_selector": "app=api", "previous": true, "timestamps": true,
+	}))
+	if line := result.StructuredContent.(logsOutput).Lines[0]; line != "[api-7d9f-abcde/api] 2026-10-17T09:00:01.000000000Z GET /health 200" {
+		t.Errorf("timestamps should be kept after the prefix, got %q", line)
+	}
+	calls := fake.Calls()
+	if args := calls[len(calls)-1].Args; !slices.Contains(args, "--previous") {
+		t.Errorf("previous should be passed to kubectl logs: %v", args)
+	}
+}
+
+func TestDevspaceLogsKubeContext(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: readFixture(t, "kubectl_pods_api.json")})
+	fake.On("kubectl", []string{"logs"}, executor.Result{Stdout: "2026-10-17T09:00:01Z ok\n"})
+	fake.On("devspace", []string{"logs"}, executor.Result{Stdout: "ok\n"})
+
+	DevspaceLogsHandler(ctx, newRequest(map[string]any{
+		"namespace": "dev", "kube_context": "kind-dev", "label_selector": "app=api",
+	}))
+	DevspaceLogsHandler(ctx, newRequest(map[string]any{
+		"namespace": "dev", "kube_context": "kind-dev", "pod": "api-7d9f-abcde",
+	}))
+	calls := fake.Calls()
+	for _, call := range calls[:len(calls)-1] {
+		if !strings.Contains(strings.Join(call.Args, " "), "-n dev --context kind-dev") {
+			t.Errorf("kubectl call should use the context: %v", call.Args)
+		}
+	}
+	if args := strings.Join(calls[len(calls)-1].Args, " "); !strings.Contains(args, "--kube-context kind-dev") {
+		t.Errorf("devspace logs should use the context: %s", args)
+	}
+}
+
+func TestDevspaceLogsAllContainers(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "pods", "-l", "app=api"}, executor.Result{Stdout: readFixture(t, "kubectl_pods_api.json")})
+	fake.On("kubectl", []string{"logs", "api-7d9f-abcde", "-c", "proxy"}, executor.Result{Stdout: "2026-10-17T09:00:02Z upstream connect error\n"})
+	fake.On("kubectl", []string{"logs", "api-7d9f-abcde", "-c", "api"}, executor.Result{Stdout: "2026-10-17T09:00:01Z listening on :8080\n"})
+	fake.On("kubectl", []string{"logs"}, executor.Result{Stderr: "container is waiting to start", ExitCode: 1})
+
+	result, _ := DevspaceLogsHandler(ctx, newRequest(map[string]any{
+		"label_selector": "app=api", "all_containers": true, "lines": 4,
+	}))
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	text := resultText(result)
+	if !strings.HasPrefix(text, "[api-7d9f-abcde/api] listening on :8080\n[api-7d9f-abcde/proxy] upstream connect error") {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+	if !strings.Contains(text, "⚠️ api-7d9f-fghij/proxy: container is waiting to start") {
+		t.Errorf("failed containers should be reported:\n%s", text)
+	}
+	for _, call := range fake.Calls() {
+		if call.Args[0] == "logs" && !slices.Contains(call.Args, "--tail=1") {
+			t.Errorf("4 containers should get one line each: %v", call.Args)
+		}
+	}
+}
+
+func TestDevspaceLogsBackend(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"logs"}, executor.Result{Stdout: "listening on :8080\n"})
+	fake.On("kubectl", []string{"logs", "api-0"}, executor.Result{Stdout: "2026-10-17T09:00:01Z panic: nil map\n"})
+
+	result, _ := DevspaceLogsHandler(ctx, newRequest(map[string]any{"pod": "api-0"}))
+	if output := result.StructuredContent.(logsOutput); output.Backend != "devspace" || output.Count != 1 {
+		t.Errorf("plain requests should use devspace logs, got %+v", output)
+	}
+	if text := resultText(result); !strings.HasSuffix(text, "listening on :8080\n\nBackend: devspace logs") {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+
+	result, _ = DevspaceLogsHandler(ctx, newRequest(map[string]any{
+		"namespace": "dev", "pod": "api-0", "container": "api", "previous": true,
+		"since_time": "2026-10-17T11:00+02:00", "timestamps": true, "lines": 50,
+	}))
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	if output := result.StructuredContent.(logsOutput); output.Backend != "kubectl" || output.Lines[0] != "2026-10-17T09:00:01Z panic: nil map" {
+		t.Errorf("unexpected output: %+v", output)
+	}
+	if text := resultText(result); !strings.HasSuffix(text, "Backend: kubectl logs") {
+		t.Errorf("text should name the backend:\n%s", text)
+	}
+	calls := fake.Calls()
+	want := "logs api-0 -n dev -c api --tail=50 --previous --since-time=2026-10-17T09:00:00Z --timestamps"
+	if got := strings.Join(calls[len(calls)-1].Args, " "); got != want {
+		t.Errorf("args = %s, want %s", got, want)
+	}
+
+	DevspaceLogsHandler(ctx, newRequest(map[string]any{"namespace": "dev", "kube_context": "kind-dev", "pod": "api-0", "previous": true}))
+	calls = fake.Calls()
+	if got := strings.Join(calls[len(calls)-1].Args, " "); !strings.HasPrefix(got, "logs api-0 -n dev --context kind-dev ") {
+		t.Errorf("kubectl logs should use the same context as devspace logs: %s", got)
+	}
+
+	DevspaceLogsHandler(ctx, newRequest(map[string]any{"pod": "api-0", "since": "1d"}))
+	calls = fake.Calls()
+	if args := calls[len(calls)-1].Args; !slices.Contains(args, "--since=24h0m0s") || slices.Contains(args, "--timestamps") {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestDevspaceLogsKubectlOptionErrors(t *testing.T) {
+	_, ctx := useFakeRunner(t)
+	tests := []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"previous": true}, "need a pod or label_selector"},
+		{map[string]any{"pod": "api-0", "since": "10m", "since_time": "2026-10-17T09:00:00Z"}, "cannot be used together"},
+		{map[string]any{"pod": "api-0", "since": "soon"}, "invalid since"},
+		{map[string]any{"pod": "api-0", "since_time": "yesterday"}, "invalid since_time"},
+	}
+	for _, tt := range tests {
+		result, _ := DevspaceLogsHandler(ctx, newRequest(tt.args))
+		if !result.IsError || !strings.Contains(resultText(result), tt.want) {
+			t.Errorf("%v: expected error containing %q, got %s", tt.args, tt.want, resultText(result))
+		}
+	}
+}
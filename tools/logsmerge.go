@@ -0,0 +1,284 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"devspace-mcp/executor"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// maxLogLines caps the lines devspace_logs returns
+const maxLogLines = 10000
+
+// logWorkers bounds the concurrent kubectl logs calls of a merged request
+const logWorkers = 4
+
+// Commands that serve devspace_logs
+const (
+	logsBackendDevspace = "devspace"
+	logsBackendKubectl  = "kubectl"
+)
+
+// defaultContainerAnnotation names the container kubectl picks by default
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// logsQuery holds the validated parameters of a devspace_logs request
+type logsQuery struct {
+	namespace     string
+	kubeContext   string
+	pod           string
+	container     string
+	labelSelector string
+	allContainers bool
+	lines         int
+	previous      bool
+	since         time.Duration
+	sinceTime     time.Time
+	timestamps    bool
+	grep          string
+	level         string
+}
+
+// needsKubectl reports whether the query uses options devspace logs does not
+// support
+func (q logsQuery) needsKubectl() bool {
+	return q.previous || q.since > 0 || !q.sinceTime.IsZero() || q.timestamps
+}
+
+// kubectlScope returns the kubectl flags selecting the namespace and context
+// of the query
+func (q logsQuery) kubectlScope() []string {
+	var scope []string
+	if q.namespace != "" {
+		scope = append(scope, "-n", q.namespace)
+	}
+	if q.kubeContext != "" {
+		scope = append(scope, "--context", q.kubeContext)
+	}
+	return scope
+}
+
+// kubectlFlags returns the kubectl logs flags of the previous, since and
+// since_time options
+func (q logsQuery) kubectlFlags() []string {
+	var flags []string
+	if q.previous {
+		flags = append(flags, "--previous")
+	}
+	if q.since > 0 {
+		flags = append(flags, "--since="+q.since.String())
+	}
+	if !q.sinceTime.IsZero() {
+		flags = append(flags, "--since-time="+q.sinceTime.UTC().Format(time.RFC3339))
+	}
+	return flags
+}
+
+// logSource is a container whose logs are part of a merged request
+type logSource struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Lines     int    `json:"lines" jsonschema:"description=Lines of this container in the merged output"`
+	Error     string `json:"error,omitempty"`
+}
+
+// logEntry is a line of a container log
+type logEntry struct {
+	time   time.Time
+	stamp  string
+	source int
+	text   string
+}
+
+// aggregateLogs fetches the logs of every matching pod and container with
+// kubectl and merges them into one stream ordered by timestamp
+func aggregateLogs(ctx context.Context, req mcp.CallToolRequest, q logsQuery) (*mcp.CallToolResult, error) {
+	timeout := commandTimeout("devspace_logs")
+	scope := q.kubectlScope()
+
+	podArgs := append([]string{"get", "pods"}, scope...)
+	if q.pod != "" {
+		podArgs = append(podArgs, "--field-selector", "metadata.name="+q.pod)
+	}
+	if q.labelSelector != "" {
+		podArgs = append(podArgs, "-l", q.labelSelector)
+	}
+	result := executeKubectl(ctx, timeout, append(podArgs, "-o", "json")...)
+	if !result.Success() {
+		return errorResult(req, result), nil
+	}
+	var list struct {
+		Items []podObject `yaml:"items"`
+	}
+	if err := yaml.Unmarshal([]byte(result.Stdout), &list); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("could not parse kubectl pods: %v", err)), nil
+	}
+
+	sources := logSources(list.Items, q.container, q.allContainers)
+	if len(sources) == 0 {
+		return mcp.NewToolResultStructured(logsOutput{Lines: []string{}, Backend: logsBackendKubectl, Sources: []logSource{}}, withBackend("No containers match.", logsBackendKubectl)), nil
+	}
+
+	// Every container gets an equal share of the lines, so one chatty
+	// container cannot crowd out the others
+	budget := max(q.lines/len(sources), 1)
+	entries, results := fetchLogs(ctx, timeout, scope, q, sources, budget)
+
+	var failed []executor.Result
+	for i, r := range results {
+		if !r.Success() {
+			sources[i].Error = strings.TrimSpace(errorText(r))
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == len(sources) {
+		return errorResult(req, failed[0]), nil
+	}
+
+	merged := mergeLogs(entries)
+	if q.grep != "" || q.level != "" {
+		merged = slices.DeleteFunc(merged, func(e logEntry) bool {
+			return !matchesLogFilters(e.text, q.grep, q.level)
+		})
+	}
+	if len(merged) > q.lines {
+		merged = merged[len(merged)-q.lines:]
+	}
+
+	output := logsOutput{Lines: make([]string, 0, len(merged)), Backend: logsBackendKubectl, Sources: sources}
+	for _, e := range merged {
+		s := sources[e.source]
+		sources[e.source].Lines++
+		line := fmt.Sprintf("[%s/%s] ", s.Pod, s.Container)
+		if q.timestamps && e.stamp != "" {
+			line += e.stamp + " "
+		}
+		output.Lines = append(output.Lines, line+e.text)
+	}
+	output.Count = len(output.Lines)
+	return mcp.NewToolResultStructured(output, formatMergedLogs(output)), nil
+}
+
+// logSources returns the containers to read: container if given, all
+// containers, or the default container of each pod
+func logSources(pods []podObject, container string, allContainers bool) []logSource {
+	var sources []logSource
+	for _, p := range pods {
+		var names []string
+		for _, c := range p.Spec.Containers {
+			names = append(names, c.Name)
+		}
+		switch {
+		case container != "":
+			if !slices.Contains(names, container) {
+				continue
+			}
+			names = []string{container}
+		case allContainers:
+		case p.Metadata.Annotations[defaultContainerAnnotation] != "":
+			names = []string{p.Metadata.Annotations[defaultContainerAnnotation]}
+		case len(names) > 0:
+			names = names[:1]
+		}
+		for _, name := range names {
+			sources = append(sources, logSource{Pod: p.Metadata.Name, Container: name})
+		}
+	}
+	return sources
+}
+
+// fetchLogs reads the last tail lines of every source with a bounded pool of
+// workers. Entries and results are indexed like sources.
+func fetchLogs(ctx context.Context, timeout time.Duration, scope []string, q logsQuery, sources []logSource, tail int) ([][]logEntry, []executor.Result) {
+	entries := make([][]logEntry, len(sources))
+	results := make([]executor.Result, len(sources))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(logWorkers, len(sources)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				args := append([]string{"logs", sources[i].Pod}, scope...)
+				args = append(args, "-c", sources[i].Container, fmt.Sprintf("--tail=%d", tail), "--timestamps")
+				args = append(args, q.kubectlFlags()...)
+				results[i] = executeKubectl(ctx, timeout, args...)
+				if results[i].Success() {
+					entries[i] = parseTimestampedLogs(results[i].Stdout, i)
+				}
+			}
+		}()
+	}
+	for i := range sources {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return entries, results
+}
+
+// parseTimestampedLogs splits the output of kubectl logs --timestamps into
+// entries. Lines without a timestamp continue the previous entry's time.
+func parseTimestampedLogs(output string, source int) []logEntry {
+	var entries []logEntry
+	var last time.Time
+	for _, line := range splitLines(output) {
+		entry := logEntry{source: source, text: line}
+		if stamp, rest, ok := strings.Cut(line, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+				last, entry.stamp, entry.text = t, stamp, rest
+			}
+		}
+		entry.time = last
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// mergeLogs interleaves the entries of all sources by time. Entries with the
+// same time keep the order of their sources and lines.
+func mergeLogs(entries [][]logEntry) []logEntry {
+	var merged []logEntry
+	for _, e := range entries {
+		merged = append(merged, e...)
+	}
+	slices.SortStableFunc(merged, func(a, b logEntry) int { return a.time.Compare(b.time) })
+	return merged
+}
+
+// matchesLogFilters reports whether a log line passes the grep and level
+// filters of devspace_logs
+func matchesLogFilters(text, grep, level string) bool {
+	if grep != "" && !containsIgnoreCase(text, grep) {
+		return false
+	}
+	return level == "" || filterByLevel(text, level) == text
+}
+
+// formatMergedLogs renders merged logs followed by the containers whose logs
+// could not be read
+func formatMergedLogs(out logsOutput) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(out.Lines, "\n"))
+	var failed []string
+	for _, s := range out.Sources {
+		if s.Error != "" {
+			failed = append(failed, fmt.Sprintf("⚠️ %s/%s: %s", s.Pod, s.Container, s.Error))
+		}
+	}
+	if len(failed) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(strings.Join(failed, "\n"))
+	}
+	return withBackend(b.String(), out.Backend)
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"devspace-mcp/config"
+	"devspace-mcp/executor"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of configuration tool results
+const (
+	sourceCLI    = "cli"
+	sourceNative = "native"
+)
+
+// offlineDescription documents the offline parameter of config tools
+const offlineDescription = "Parse devspace.yaml locally instead of running the devspace CLI. Faster and works without a cluster; variables set by commands or runtime values stay unresolved"
+
+// locateConfig returns the devspace config of the current call, located by
+// withProject or from the working_dir and config parameters
+func locateConfig(ctx context.Context, req mcp.CallToolRequest) (ConfigLocation, error) {
+	if loc, ok := configLocationFrom(ctx); ok {
+		return loc, nil
+	}
+	return LocateDevspaceConfig(req.GetString("working_dir", ""), req.GetString("config", ""))
+}
+
+// loadNative loads the devspace config of the current call without the CLI
+func loadNative(ctx context.Context, req mcp.CallToolRequest) (*config.Config, ConfigLocation, error) {
+	loc, err := locateConfig(ctx, req)
+	if err != nil {
+		return nil, loc, err
+	}
+	c, err := config.Load(loc.Path)
+	return c, loc, err
+}
+
+// resolveNative loads the devspace config of the current call and resolves
+// it with the profile parameter, like the devspace CLI would
+func resolveNative(ctx context.Context, req mcp.CallToolRequest) (*config.Resolved, error) {
+	c, loc, err := loadNative(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var profiles []string
+	if profile := req.GetString("profile", ""); profile != "" {
+		profiles = []string{profile}
+	}
+	return c.Resolve(config.Options{
+		Profiles: profiles,
+		Cache:    config.CachedVars(loc.Dir),
+	})
+}
+
+// nativeResult returns the result of a config tool that parsed devspace.yaml
+// locally. When the CLI was tried first, its failure is shown above the text.
+func nativeResult(output any, text string, cli *executor.Result) *mcp.CallToolResult {
+	if cli != nil {
+		text = fmt.Sprintf("⚠️ devspace CLI failed, showing the configuration parsed locally instead.\n%s\n\n%s",
+			strings.TrimSpace(cli.FormatOutput()), text)
+	}
+	return mcp.NewToolResultStructured(output, text)
+}
+
+// nativeError returns the error of a config tool when local parsing failed,
+// including the CLI failure that led to it
+func nativeError(err error, cli *executor.Result) *mcp.CallToolResult {
+	if cli == nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	return mcp.NewToolResultError(fmt.Sprintf("%s\n\nParsing devspace.yaml locally failed as well: %v", cli.FormatOutput(), err))
+}
+
+// nativeProfiles lists the profiles of the config without the CLI
+func nativeProfiles(c *config.Config) (profilesOutput, string) {
+	output := profilesOutput{Profiles: []profileInfo{}, Source: sourceNative}
+	var b strings.Builder
+	if len(c.Profiles()) == 0 {
+		b.WriteString("No profiles defined.\n")
+	}
+	for _, p := range c.Profiles() {
+		output.Profiles = append(output.Profiles, profileInfo{Name: p.Name, Description: p.Description})
+		fmt.Fprintf(&b, "- %s", p.Name)
+		if p.Description != "" {
+			fmt.Fprintf(&b, ": %s", p.Description)
+		}
+		if len(p.Parents) > 0 {
+			fmt.Fprintf(&b, " (parents: %s)", strings.Join(p.Parents, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return output, b.String()
+}
+
+// nativeVars lists the resolved variables without the CLI. Values of
+// password and secret vars are masked.
+func nativeVars(resolved *config.Resolved) (varsOutput, string) {
+	output := varsOutput{Vars: []varInfo{}, Source: sourceNative}
+	var b strings.Builder
+	if len(resolved.Vars) == 0 {
+		b.WriteString("No variables defined.\n")
+	}
+	for _, v := range resolved.Vars {
+		if v.Value != "" && (v.Password || secretVarPattern.MatchString(v.Name)) {
+			v.Value = placeholder(v.Name, v.Value)
+		}
+		output.Vars = append(output.Vars, varInfo{Name: v.Name, Value: v.Value, Unresolved: v.Unresolved})
+		if v.Unresolved != "" {
+			fmt.Fprintf(&b, "%s: unresolved, %s\n", v.Name, v.Unresolved)
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s (%s)\n", v.Name, v.Value, v.Source)
+	}
+	return output, b.String()
+}
+
+// nativePrint renders the resolved config without the CLI
+func nativePrint(resolved *config.Resolved) (printOutput, error) {
+	data, err := yaml.Marshal(resolved.Config)
+	if err != nil {
+		return printOutput{}, err
+	}
+	return printOutput{Config: resolved.Config, Output: string(data), Source: sourceNative}, nil
+}
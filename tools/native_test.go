@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+const nativeProjectConfig = `version: v2beta1
+name: shop
+vars:
+  IMAGE: registry.example.com/shop
+  VERSION:
+    command: git describe --tags
+images:
+  api:
+    image: ${IMAGE}
+    tags:
+      - ${VERSION}
+profiles:
+  - name: production
+    description: Production settings
+    patches:
+      - op: replace
+        path: images.api.image
+        value: prod.example.com/shop
+`
+
+// nativeProject creates a project with a config the native parser can resolve
+func nativeProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "devspace.yaml"), []byte(nativeProjectConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestListProfilesOffline(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	dir := nativeProject(t)
+
+	result, _ := DevspaceListProfilesHandler(ctx, newRequest(map[string]any{"working_dir": dir, "offline": true}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	if len(fake.Calls()) != 0 {
+		t.Errorf("offline must not run the CLI, got %+v", fake.Calls())
+	}
+	output := result.StructuredContent.(profilesOutput)
+	if output.Source != sourceNative || len(output.Profiles) != 1 || output.Profiles[0].Description != "Production settings" {
+		t.Errorf("unexpected output: %+v", output)
+	}
+}
+
+func TestListVarsFallsBackToNative(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"list", "vars"}, executor.Result{Stderr: "error: cannot connect to cluster", ExitCode: 1})
+	dir := nativeProject(t)
+
+	result, _ := DevspaceListVarsHandler(ctx, newRequest(map[string]any{"working_dir": dir}))
+	if result.IsError {
+		t.Fatalf("expected fallback, got %s", resultText(result))
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "devspace CLI failed") || !strings.Contains(text, "cannot connect to cluster") {
+		t.Errorf("text should explain the fallback, got %s", text)
+	}
+	output := result.StructuredContent.(varsOutput)
+	want := []varInfo{
+		{Name: "IMAGE", Value: "registry.example.com/shop"},
+		{Name: "VERSION", Unresolved: "set by a command"},
+	}
+	if output.Source != sourceNative || len(output.Vars) != len(want) || output.Vars[0] != want[0] || output.Vars[1] != want[1] {
+		t.Errorf("unexpected output: %+v", output)
+	}
+}
+
+func TestPrintUsesCLIFirst(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"print"}, executor.Result{Stdout: "version: v2beta1\nname: shop\n"})
+
+	result, _ := DevspacePrintHandler(ctx, newRequest(map[string]any{"working_dir": nativeProject(t)}))
+	if output := result.StructuredContent.(printOutput); output.Source != sourceCLI {
+		t.Errorf("source = %s, want cli", output.Source)
+	}
+}
+
+func TestPrintOfflineAppliesProfile(t *testing.T) {
+	_, ctx := useFakeRunner(t)
+
+	result, _ := DevspacePrintHandler(ctx, newRequest(map[string]any{
+		"working_dir": nativeProject(t),
+		"profile":     "production",
+		"offline":     true,
+	}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	if !strings.Contains(resultText(result), "image: prod.example.com/shop") {
+		t.Errorf("profile patch not applied: %s", resultText(result))
+	}
+}
+
+func TestPrintReportsBothFailures(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"print"}, executor.Result{Stderr: "profile unknown", ExitCode: 1})
+
+	result, _ := DevspacePrintHandler(ctx, newRequest(map[string]any{
+		"working_dir": nativeProject(t),
+		"profile":     "unknown",
+	}))
+	if !result.IsError {
+		t.Fatal("expected an error")
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "profile unknown") || !strings.Contains(text, `profile "unknown" not found`) {
+		t.Errorf("error should include both failures, got %s", text)
+	}
+}
+
+func TestListVarsOfflineMasksSecrets(t *testing.T) {
+	_, ctx := useFakeRunner(t)
+	dir := t.TempDir()
+	config := `version: v2beta1
+vars:
+  IMAGE: registry.example.com/shop
+  DB_PASS:
+    password: true
+    default: hunter22
+  GITHUB_TOKEN: ghp-value-42
+`
+	if err := os.WriteFile(filepath.Join(dir, "devspace.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := DevspaceListVarsHandler(ctx, newRequest(map[string]any{"working_dir": dir, "offline": true}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	text := resultText(result)
+	for _, secret := range []string{"hunter22", "ghp-value-42"} {
+		if strings.Contains(text, secret) {
+			t.Errorf("secret %q not masked:\n%s", secret, text)
+		}
+	}
+	for _, v := range result.StructuredContent.(varsOutput).Vars {
+		switch v.Name {
+		case "IMAGE":
+			if v.Value != "registry.example.com/shop" {
+				t.Errorf("IMAGE = %q, want it unmasked", v.Value)
+			}
+		default:
+			if !strings.HasPrefix(v.Value, "[REDACTED:"+v.Name+":") {
+				t.Errorf("%s = %q, want a placeholder", v.Name, v.Value)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"devspace-mcp/errorpatterns"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errorPatternsOutput is the result of devspace_list_error_patterns
+type errorPatternsOutput struct {
+	Dirs     []string                `json:"dirs" jsonschema:"description=User directories loaded on top of the built-in patterns"`
+	Patterns []errorpatterns.Pattern `json:"patterns"`
+}
+
+// DevspaceListErrorPatternsTool returns the tool definition for listing the
+// active error patterns
+func DevspaceListErrorPatternsTool() mcp.Tool {
+	return mcp.NewTool("devspace_list_error_patterns",
+		mcp.WithDescription("List the error patterns used to classify failed commands and suggest fixes: the built-in patterns plus those loaded from user pattern packs, with the file each comes from"),
+		mcp.WithOutputSchema[errorPatternsOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithString("tool",
+			mcp.Description("Only patterns applied to errors of this tool (e.g., 'devspace_deploy')"),
+		),
+		mcp.WithString("category",
+			mcp.Description("Only patterns of this category (e.g., 'auth', 'connectivity', 'workload')"),
+		),
+	)
+}
+
+// DevspaceListErrorPatternsHandler handles the list error patterns request
+func DevspaceListErrorPatternsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tool := req.GetString("tool", "")
+	category := req.GetString("category", "")
+	for name, value := range map[string]string{"tool": tool, "category": category} {
+		if value == "" {
+			continue
+		}
+		if err := ValidateStringParam(name, value); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if tool != "" && !strings.HasPrefix(tool, "devspace_") {
+		tool = "devspace_" + tool
+	}
+
+	output := errorPatternsOutput{Dirs: nonNil(errorPatterns.Dirs()), Patterns: []errorpatterns.Pattern{}}
+	for _, p := range errorPatterns.Patterns() {
+		if (tool == "" || p.AppliesTo(tool)) && (category == "" || p.Category == category) {
+			output.Patterns = append(output.Patterns, p)
+		}
+	}
+	return mcp.NewToolResultStructured(output, formatErrorPatterns(output)), nil
+}
+
+// formatErrorPatterns renders one block per pattern
+func formatErrorPatterns(out errorPatternsOutput) string {
+	var b strings.Builder
+	b.WriteString("# Error Patterns\n\n")
+	if len(out.Dirs) > 0 {
+		fmt.Fprintf(&b, "User directories: %s\n\n", strings.Join(out.Dirs, ", "))
+	}
+	if len(out.Patterns) == 0 {
+		b.WriteString("No patterns match.\n")
+		return b.String()
+	}
+	for _, p := range out.Patterns {
+		fmt.Fprintf(&b, "## %s (%s, %s)\n", p.Code, p.Category, p.Severity)
+		var conditions []string
+		if p.Pattern != "" {
+			conditions = append(conditions, fmt.Sprintf("contains %q", p.Pattern))
+		}
+		if p.Regex != "" {
+			conditions = append(conditions, "matches /"+p.Regex+"/")
+		}
+		if len(p.ExitCodes) > 0 {
+			conditions = append(conditions, fmt.Sprintf("exit code in %v", p.ExitCodes))
+		}
+		fmt.Fprintf(&b, "Match: %s\n", strings.Join(conditions, " and "))
+		if len(p.Tools) > 0 {
+			fmt.Fprintf(&b, "Tools: %s\n", strings.Join(p.Tools, ", "))
+		}
+		for _, s := range p.Suggestions {
+			fmt.Fprintf(&b, "💡 %s\n", s)
+		}
+		if p.Remediation != "" {
+			fmt.Fprintf(&b, "🔧 %s\n", p.Remediation)
+		}
+		fmt.Fprintf(&b, "Source: %s\n\n", p.Source)
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+const quotaPack = `
+patterns:
+  - code: REGISTRY_QUOTA
+    category: resources
+    severity: error
+    regex: 'quota exceeded for project (?P<project>[\w-]+)'
+    tools: [build]
+    suggestions:
+      - "Registry project {{project}} is over its storage quota. Delete old tags."
+    remediation: "registry-cli prune --project {{project}}"
+`
+
+// useErrorPatterns loads a pattern pack for the duration of a test
+func useErrorPatterns(t *testing.T, pack string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "org.yaml"), []byte(pack), 0644); err != nil {
+		t.Fatal(err)
+	}
+	previous := errorPatterns
+	t.Cleanup(func() { errorPatterns = previous })
+	if err := SetErrorPatternDirs([]string{dir}); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestListErrorPatterns(t *testing.T) {
+	dir := useErrorPatterns(t, quotaPack)
+
+	result, _ := DevspaceListErrorPatternsHandler(context.Background(), newRequest(map[string]any{"category": "resources"}))
+	output := result.StructuredContent.(errorPatternsOutput)
+	var codes []string
+	for _, p := range output.Patterns {
+		codes = append(codes, p.Code)
+	}
+	if strings.Join(codes, ",") != "DISK_FULL,PROCESS_KILLED,REGISTRY_QUOTA" {
+		t.Errorf("codes = %v", codes)
+	}
+	if len(output.Dirs) != 1 || output.Dirs[0] != dir {
+		t.Errorf("dirs = %v", output.Dirs)
+	}
+	text := resultText(result)
+	if !strings.Contains(text, "## REGISTRY_QUOTA (resources, error)") || !strings.Contains(text, "Source: "+filepath.Join(dir, "org.yaml")) {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+
+	result, _ = DevspaceListErrorPatternsHandler(context.Background(), newRequest(map[string]any{"tool": "deploy", "category": "resources"}))
+	if output := result.StructuredContent.(errorPatternsOutput); len(output.Patterns) != 2 {
+		t.Errorf("REGISTRY_QUOTA only applies to build, got %+v", output.Patterns)
+	}
+}
+
+func TestUserPatternClassifiesToolErrors(t *testing.T) {
+	useErrorPatterns(t, quotaPack)
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"build"}, executor.Result{
+		Stderr:   "push registry.example.com/shop/api:v2: denied: quota exceeded for project shop-team",
+		ExitCode: 1,
+	})
+
+	req := newRequest(map[string]any{})
+	req.Params.Name = "devspace_build"
+	result, _ := DevspaceBuildHandler(ctx, req)
+	text := resultText(result)
+	for _, part := range []string{
+		"💡 Suggestion: Registry project shop-team is over its storage quota.",
+		"🔧 Try: registry-cli prune --project shop-team",
+		"Error code: REGISTRY_QUOTA (resources, error)",
+	} {
+		if !strings.Contains(text, part) {
+			t.Errorf("text should contain %q, got:\n%s", part, text)
+		}
+	}
+}
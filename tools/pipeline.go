@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DevspaceRunPipelineTool returns the tool definition for running a pipeline
+func DevspaceRunPipelineTool() mcp.Tool {
+	return mcp.NewTool("devspace_run_pipeline",
+		mcp.WithDescription("Run a pipeline defined in devspace.yaml (e.g. integration, seed-db, e2e) using 'devspace run-pipeline'. Pipelines can build, deploy and run arbitrary steps, so this may take several minutes."),
+		mcp.WithOutputSchema[commandOutput](),
+		withCategory(serverconfig.CategoryMutate),
+		mcp.WithString("pipeline",
+			mcp.Required(),
+			mcp.Description("Name of the pipeline to run (as defined under 'pipelines' in devspace.yaml)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
+		mcp.WithString("profile",
+			mcp.Description("Profile to use"),
+		),
+		mcp.WithString("dependency",
+			mcp.Description("Comma-separated dependencies to run; all others are skipped"),
+		),
+		mcp.WithString("skip_dependency",
+			mcp.Description("Comma-separated dependencies to skip"),
+		),
+		mcp.WithBoolean("skip_build",
+			mcp.Description("Skip building images"),
+		),
+		mcp.WithBoolean("skip_deploy",
+			mcp.Description("Skip deploying"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Comma-separated tags to use for all built images"),
+		),
+		mcp.WithNumber("max_concurrent_builds",
+			mcp.Description("Maximum number of images built in parallel (0 for unlimited)"),
+		),
+		mcp.WithBoolean("render",
+			mcp.Description("Render manifests and print them instead of deploying"),
+		),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+	)
+}
+
+// DevspaceRunPipelineHandler handles the run-pipeline command
+func DevspaceRunPipelineHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pipeline := req.GetString("pipeline", "")
+	if pipeline == "" {
+		return mcp.NewToolResultError("pipeline parameter is required"), nil
+	}
+	if err := ValidateCommandName(pipeline); err != nil {
+		return mcp.NewToolResultError(strings.Replace(err.Error(), "command name", "pipeline name", 1)), nil
+	}
+
+	args := []string{"run-pipeline", pipeline}
+
+	if namespace := req.GetString("namespace", ""); namespace != "" {
+		if err := ValidateStringParam("namespace", namespace); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--namespace", namespace)
+	}
+	if kubeContext := req.GetString("kube_context", ""); kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--kube-context", kubeContext)
+	}
+	if profile := req.GetString("profile", ""); profile != "" {
+		if err := ValidateStringParam("profile", profile); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--profile", profile)
+	}
+
+	var err error
+	if args, err = appendListFlag(args, "dependency", "--dependency", req.GetString("dependency", "")); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if args, err = appendListFlag(args, "skip_dependency", "--skip-dependency", req.GetString("skip_dependency", "")); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if args, err = appendListFlag(args, "tag", "--tag", req.GetString("tag", "")); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if req.GetBool("skip_build", false) {
+		args = append(args, "--skip-build")
+	}
+	if req.GetBool("skip_deploy", false) {
+		args = append(args, "--skip-deploy")
+	}
+	if _, exists := req.GetArguments()["max_concurrent_builds"]; exists {
+		maxBuilds := req.GetInt("max_concurrent_builds", 0)
+		if maxBuilds < 0 {
+			return mcp.NewToolResultError("max_concurrent_builds cannot be negative"), nil
+		}
+		args = append(args, "--max-concurrent-builds", fmt.Sprintf("%d", maxBuilds))
+	}
+	if req.GetBool("render", false) {
+		args = append(args, "--render")
+	}
+
+	workingDir := req.GetString("working_dir", "")
+
+	// Pipelines can build and deploy, use long running timeout
+	result := executeDevspaceStreaming(ctx, req, longRunningTimeout("devspace_run_pipeline"), workingDir, args...)
+
+	if !result.Success() {
+		return errorResult(req, result), nil
+	}
+
+	return mcp.NewToolResultStructured(newCommandOutput(args, result), result.FormatOutput()), nil
+}
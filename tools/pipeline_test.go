@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func TestDevspaceRunPipelineTool(t *testing.T) {
+	tool := DevspaceRunPipelineTool()
+
+	if tool.Name != "devspace_run_pipeline" {
+		t.Errorf("expected tool name 'devspace_run_pipeline', got %s", tool.Name)
+	}
+
+	isRequired := false
+	for _, req := range tool.InputSchema.Required {
+		if req == "pipeline" {
+			isRequired = true
+			break
+		}
+	}
+	if !isRequired {
+		t.Error("pipeline parameter should be required")
+	}
+}
+
+func TestDevspaceRunPipelineHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"run-pipeline"}, executor.Result{Stdout: "pipeline integration finished"})
+
+	result, err := DevspaceRunPipelineHandler(ctx, newRequest(map[string]any{
+		"pipeline":              "integration",
+		"namespace":             "ci",
+		"profile":               "ci",
+		"dependency":            "db,cache",
+		"skip_dependency":       "frontend",
+		"skip_build":            true,
+		"tag":                   "sha-abc123",
+		"max_concurrent_builds": 2,
+		"render":                true,
+		"working_dir":           "/work/api",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+
+	call := fake.Calls()[0]
+	want := []string{"run-pipeline", "integration", "--namespace", "ci", "--profile", "ci",
+		"--dependency", "db", "--dependency", "cache", "--skip-dependency", "frontend",
+		"--tag", "sha-abc123", "--skip-build", "--max-concurrent-builds", "2", "--render"}
+	if !reflect.DeepEqual(call.Args, want) {
+		t.Errorf("args = %v, want %v", call.Args, want)
+	}
+	if call.Timeout != executor.LongRunningTimeout {
+		t.Errorf("timeout = %v, want %v", call.Timeout, executor.LongRunningTimeout)
+	}
+	if call.Dir != "/work/api" {
+		t.Errorf("dir = %q, want /work/api", call.Dir)
+	}
+}
+
+func TestDevspaceRunPipelineHandlerMaxConcurrentBuildsZero(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"run-pipeline"}, executor.Result{})
+
+	DevspaceRunPipelineHandler(ctx, newRequest(map[string]any{
+		"pipeline":              "seed-db",
+		"max_concurrent_builds": 0,
+	}))
+
+	if got := strings.Join(fake.Calls()[0].Args, " "); got != "run-pipeline seed-db --max-concurrent-builds 0" {
+		t.Errorf("explicit 0 should be passed through, got %q", got)
+	}
+}
+
+func TestDevspaceRunPipelineHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"missing pipeline", map[string]any{}},
+		{"pipeline with shell characters", map[string]any{"pipeline": "e2e;rm"}},
+		{"pipeline flag injection", map[string]any{"pipeline": "--help"}},
+		{"dependency flag injection", map[string]any{"pipeline": "e2e", "dependency": "db,--force-purge"}},
+		{"negative concurrency", map[string]any{"pipeline": "e2e", "max_concurrent_builds": -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, ctx := useFakeRunner(t)
+			result, _ := DevspaceRunPipelineHandler(ctx, newRequest(tt.args))
+			if !result.IsError {
+				t.Error("expected validation error")
+			}
+			if len(fake.Calls()) != 0 {
+				t.Error("devspace should not be invoked")
+			}
+		})
+	}
+}
+
+func TestDevspaceRunPipelineHandlerEnhancesErrors(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"run-pipeline"}, executor.Result{Stderr: "error: token has expired", ExitCode: 1})
+
+	result, _ := DevspaceRunPipelineHandler(ctx, newRequest(map[string]any{"pipeline": "e2e"}))
+	if !result.IsError || !strings.Contains(resultText(result), "aws sso login") {
+		t.Errorf("expected enhanced error, got %s", resultText(result))
+	}
+}
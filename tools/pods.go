@@ -2,9 +2,10 @@ package tools
 
 import (
 	"context"
-	"os/exec"
+	"time"
 
 	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -13,10 +14,14 @@ import (
 func DevspaceListPodsTool() mcp.Tool {
 	return mcp.NewTool("devspace_list_pods",
 		mcp.WithDescription("Lists pods in a Kubernetes namespace using kubectl. Useful for inspecting running pods, checking their status, and identifying pod names for use with other tools."),
+		mcp.WithOutputSchema[podsOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("namespace",
-			mcp.Required(),
 			mcp.Description("Kubernetes namespace to list pods from"),
 		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
 		mcp.WithString("label_selector",
 			mcp.Description("Filter pods by labels (e.g., 'app=myapp,tier=frontend')"),
 		),
@@ -45,7 +50,7 @@ func DevspaceListPodsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp
 		// Use specific namespace
 		namespace := req.GetString("namespace", "")
 		if namespace == "" {
-			return mcp.NewToolResultError("namespace parameter is required when all_namespaces is false"), nil
+			return mcp.NewToolResultError("namespace parameter is required when all_namespaces is false; pass it or set it with devspace_set_context"), nil
 		}
 		if err := ValidateStringParam("namespace", namespace); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
@@ -53,6 +58,14 @@ func DevspaceListPodsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp
 		args = append(args, "-n", namespace)
 	}
 
+	// Add kube context if specified
+	if kubeContext := req.GetString("kube_context", ""); kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--context", kubeContext)
+	}
+
 	// Add label selector if specified
 	if labelSelector := req.GetString("label_selector", ""); labelSelector != "" {
 		if err := ValidateStringParam("label_selector", labelSelector); err != nil {
@@ -77,42 +90,20 @@ func DevspaceListPodsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp
 	args = append(args, "-o", output)
 
 	// Execute kubectl command
-	result := executeKubectl(ctx, args...)
+	result := executeKubectl(ctx, commandTimeout("devspace_list_pods"), args...)
 
 	if !result.Success() {
-		return mcp.NewToolResultError(EnhanceError(result)), nil
+		return errorResult(req, result), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	return mcp.NewToolResultStructured(podsOutput{Pods: parsePods(result.Stdout, output)}, result.FormatOutput()), nil
 }
 
-// executeKubectl runs a kubectl command with the given arguments
-func executeKubectl(ctx context.Context, args ...string) executor.Result {
-	ctx, cancel := context.WithTimeout(ctx, executor.DefaultTimeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "kubectl", args...)
-
-	var stdout, stderr string
-	if output, err := cmd.CombinedOutput(); err != nil {
-		stderr = string(output)
-		exitCode := -1
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		}
-		return executor.Result{
-			Stdout:   "",
-			Stderr:   stderr,
-			ExitCode: exitCode,
-			Error:    err.Error(),
-		}
-	} else {
-		stdout = string(output)
-	}
-
-	return executor.Result{
-		Stdout:   stdout,
-		Stderr:   stderr,
-		ExitCode: 0,
-	}
+// executeKubectl runs a kubectl command through the runner of the call
+func executeKubectl(ctx context.Context, timeout time.Duration, args ...string) executor.Result {
+	return runnerFrom(ctx).Run(ctx, executor.Command{
+		Binary:  executor.KubectlBinary,
+		Args:    args,
+		Timeout: timeout,
+	})
 }
@@ -1,7 +1,10 @@
 package tools
 
 import (
+	"reflect"
 	"testing"
+
+	"devspace-mcp/executor"
 )
 
 func TestDevspaceListPodsTool(t *testing.T) {
@@ -17,17 +20,16 @@ func TestDevspaceListPodsTool(t *testing.T) {
 		t.Error("tool description should not be empty")
 	}
 
-	// Verify namespace is in required parameters
-	isRequired := false
+	// Verify namespace is accepted but optional, as it may come from the
+	// session context
+	if _, ok := tool.InputSchema.Properties["namespace"]; !ok {
+		t.Error("tool should accept a namespace parameter")
+	}
 	for _, req := range tool.InputSchema.Required {
 		if req == "namespace" {
-			isRequired = true
-			break
+			t.Error("namespace parameter should not be required")
 		}
 	}
-	if !isRequired {
-		t.Error("namespace parameter should be required")
-	}
 }
 
 func TestDevspaceListPodsValidation(t *testing.T) {
@@ -120,3 +122,37 @@ func TestDevspaceListPodsValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestDevspaceListPodsHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("kubectl", []string{"get", "pods"}, executor.Result{Stdout: "NAME   READY   STATUS\napi-0  1/1     Running"})
+
+	result, err := DevspaceListPodsHandler(ctx, newRequest(map[string]any{
+		"namespace":      "dev",
+		"label_selector": "app=api",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", resultText(result))
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 invocation, got %d", len(calls))
+	}
+	want := []string{"get", "pods", "-n", "dev", "-l", "app=api", "-o", "wide"}
+	if !reflect.DeepEqual(calls[0].Args, want) {
+		t.Errorf("args = %v, want %v", calls[0].Args, want)
+	}
+
+	structured, ok := result.StructuredContent.(podsOutput)
+	if !ok {
+		t.Fatalf("expected podsOutput structured content, got %T", result.StructuredContent)
+	}
+	wantPods := []podInfo{{Name: "api-0", Phase: "Running", Ready: "1/1"}}
+	if !reflect.DeepEqual(structured.Pods, wantPods) {
+		t.Errorf("pods = %+v, want %+v", structured.Pods, wantPods)
+	}
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"devspace-mcp/policy"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// activePolicy is the protected context policy, nil when none is configured
+var activePolicy *policy.Policy
+
+// SetPolicy installs the policy checked by every tool that accepts a
+// kube_context or namespace, and by devspace_run. It must be called before RegisterAll so the
+// confirm parameter is added to those tools.
+func SetPolicy(p *policy.Policy) {
+	activePolicy = p
+}
+
+// guardTool wraps the handler of a tool that accepts kube_context or
+// namespace with the policy check, and declares the confirm parameter.
+// Other tools are returned unchanged.
+func guardTool(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	if activePolicy == nil || !targetsCluster(tool) {
+		return tool, handler
+	}
+
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, schema := range tool.InputSchema.Properties {
+		properties[name] = schema
+	}
+	properties["confirm"] = map[string]any{
+		"type":        "string",
+		"description": "Confirmation token required by the server policy for protected kube contexts and namespaces",
+	}
+	tool.InputSchema.Properties = properties
+
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := checkPolicy(ctx, tool.Name, req); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return handler(ctx, req)
+	}
+}
+
+// implicitClusterTools act on the kubeconfig current context without
+// accepting a kube_context or namespace. devspace_run executes arbitrary
+// commands of devspace.yaml.
+var implicitClusterTools = []string{"devspace_run"}
+
+// targetsCluster reports whether the tool accepts a kube context or namespace,
+// or acts on the current context anyway. The session context tools only
+// store them and are not guarded.
+func targetsCluster(tool mcp.Tool) bool {
+	if managesContext(tool) {
+		return false
+	}
+	if slices.Contains(implicitClusterTools, tool.Name) {
+		return true
+	}
+	_, hasContext := tool.InputSchema.Properties["kube_context"]
+	_, hasNamespace := tool.InputSchema.Properties["namespace"]
+	return hasContext || hasNamespace
+}
+
+// checkPolicy checks a tool call against the active policy. A missing
+// kube_context or namespace is resolved from the kubeconfig, because that is
+// where devspace and kubectl will act.
+func checkPolicy(ctx context.Context, tool string, req mcp.CallToolRequest) error {
+	flags := make(map[string]bool)
+	for name, value := range req.GetArguments() {
+		if b, ok := value.(bool); ok && b {
+			flags[name] = true
+		}
+	}
+	if !activePolicy.Applies(tool, flags) {
+		return nil
+	}
+
+	r := policy.Request{
+		Tool:          tool,
+		Flags:         flags,
+		KubeContext:   req.GetString("kube_context", ""),
+		Namespace:     req.GetString("namespace", ""),
+		AllNamespaces: req.GetBool("all_namespaces", false),
+		Confirm:       req.GetString("confirm", ""),
+	}
+
+	if r.KubeContext == "" {
+		result := executeKubectl(ctx, commandTimeout(tool), "config", "current-context")
+		if !result.Success() {
+			return fmt.Errorf("cannot determine the current kube context to check the server policy: %s", strings.TrimSpace(result.FormatOutput()))
+		}
+		r.KubeContext = strings.TrimSpace(result.Stdout)
+	}
+	if r.Namespace == "" && !r.AllNamespaces {
+		result := executeKubectl(ctx, commandTimeout(tool), "config", "view", "--minify", "--context", r.KubeContext, "-o", "jsonpath={..namespace}")
+		r.Namespace = strings.TrimSpace(result.Stdout)
+		if !result.Success() || r.Namespace == "" {
+			r.Namespace = "default"
+		}
+	}
+
+	return activePolicy.Check(r)
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/policy"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// usePolicy installs a policy and registers all tools on a fresh server
+func usePolicy(t *testing.T, doc string, r executor.Runner) *server.MCPServer {
+	t.Helper()
+	p, err := policy.Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousPolicy := activePolicy
+	t.Cleanup(func() { activePolicy = previousPolicy })
+
+	SetPolicy(p)
+	useProject(t)
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	RegisterAll(s, r)
+	return s
+}
+
+const stagingPolicy = `
+rules:
+  - name: shared-staging
+    kube_contexts: ["staging"]
+    deny: [purge, deploy:force_deploy]
+    confirm: [deploy]
+`
+
+func TestPolicyDeniesPurgeOnCurrentContext(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	s := usePolicy(t, stagingPolicy, fake)
+	fake.On("kubectl", []string{"config", "current-context"}, executor.Result{Stdout: "staging\n"})
+	fake.On("kubectl", []string{"config", "view"}, executor.Result{Stdout: "team-a"})
+
+	result, err := s.GetTool("devspace_purge").Handler(ctx, newRequest(map[string]any{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected purge to be denied")
+	}
+	text := resultText(result)
+	if !strings.Contains(text, `policy rule "shared-staging" denies devspace_purge`) || !strings.Contains(text, `namespace "team-a"`) {
+		t.Errorf("unexpected error: %s", text)
+	}
+	for _, call := range fake.Calls() {
+		if call.Binary == executor.DevspaceBinary {
+			t.Errorf("devspace should not be invoked, got %v", call.Args)
+		}
+	}
+}
+
+func TestPolicyRequiresConfirmation(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	s := usePolicy(t, stagingPolicy, fake)
+	fake.On("devspace", []string{"deploy"}, executor.Result{Stdout: "deployed"})
+	handler := s.GetTool("devspace_deploy").Handler
+
+	result, _ := handler(ctx, newRequest(map[string]any{"kube_context": "staging", "namespace": "team-a"}))
+	if !result.IsError || !strings.Contains(resultText(result), `confirm="shared-staging"`) {
+		t.Fatalf("expected a confirmation error, got %s", resultText(result))
+	}
+
+	result, _ = handler(ctx, newRequest(map[string]any{"kube_context": "staging", "namespace": "team-a", "confirm": "shared-staging"}))
+	if result.IsError {
+		t.Fatalf("confirmed deploy should run, got %s", resultText(result))
+	}
+
+	result, _ = handler(ctx, newRequest(map[string]any{"kube_context": "staging", "namespace": "team-a", "confirm": "shared-staging", "force_deploy": true}))
+	if !result.IsError || !strings.Contains(resultText(result), "deploy:force_deploy") {
+		t.Fatalf("force deploy should be denied despite the token, got %s", resultText(result))
+	}
+
+	if len(fake.Calls()) != 1 {
+		t.Errorf("expected exactly one devspace invocation, got %d", len(fake.Calls()))
+	}
+}
+
+func TestPolicyAllowsOtherContexts(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	s := usePolicy(t, stagingPolicy, fake)
+	fake.On("devspace", []string{"purge"}, executor.Result{Stdout: "purged"})
+
+	result, _ := s.GetTool("devspace_purge").Handler(ctx, newRequest(map[string]any{"kube_context": "kind-dev", "namespace": "dev"}))
+	if result.IsError {
+		t.Fatalf("purge on an unprotected context should run, got %s", resultText(result))
+	}
+}
+
+func TestPolicyFailsClosedWithoutContext(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	s := usePolicy(t, stagingPolicy, fake)
+	fake.On("kubectl", []string{"config", "current-context"}, executor.Result{Stderr: "error: current-context is not set", ExitCode: 1})
+
+	result, _ := s.GetTool("devspace_purge").Handler(ctx, newRequest(map[string]any{}))
+	if !result.IsError || !strings.Contains(resultText(result), "cannot determine the current kube context") {
+		t.Fatalf("expected the call to be refused, got %s", resultText(result))
+	}
+}
+
+func TestPolicyDeniesRunOnCurrentContext(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	s := usePolicy(t, "rules:\n  - name: production\n    kube_contexts: [\"prod\"]\n    deny: [run]\n", fake)
+	fake.On("kubectl", []string{"config", "current-context"}, executor.Result{Stdout: "prod\n"})
+	fake.On("kubectl", []string{"config", "view"}, executor.Result{Stdout: "shop"})
+
+	result, _ := s.GetTool("devspace_run").Handler(ctx, newRequest(map[string]any{"command": "migrate"}))
+	if !result.IsError || !strings.Contains(resultText(result), "production") {
+		t.Fatalf("expected run to be denied, got %s", resultText(result))
+	}
+	for _, call := range fake.Calls() {
+		if call.Binary == "devspace" {
+			t.Errorf("devspace should not run: %v", call.Args)
+		}
+	}
+}
+
+func TestPolicyGuardsClusterTools(t *testing.T) {
+	s := usePolicy(t, stagingPolicy, nil)
+
+	for name, tool := range s.ListTools() {
+		_, hasConfirm := tool.Tool.InputSchema.Properties["confirm"]
+		if hasConfirm != targetsCluster(tool.Tool) {
+			t.Errorf("tool %s: confirm parameter = %v, targets cluster = %v", name, hasConfirm, targetsCluster(tool.Tool))
+		}
+	}
+	for _, name := range []string{"devspace_purge", "devspace_deploy", "devspace_exec", "devspace_list_pods", "devspace_port_forward", "devspace_run"} {
+		if !targetsCluster(s.GetTool(name).Tool) {
+			t.Errorf("tool %s should be guarded", name)
+		}
+	}
+}
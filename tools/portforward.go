@@ -0,0 +1,551 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// portForwardOutputLines is the number of output lines retained per forward
+const portForwardOutputLines = 200
+
+// portForwardMaxFailures is the number of consecutive quick failures after
+// which a forward is no longer restarted
+const portForwardMaxFailures = 5
+
+// portForwardStableAfter is how long a tunnel has to stay up before its
+// failure counter is reset
+var portForwardStableAfter = 30 * time.Second
+
+// portForwardBackoff returns the delay before the given restart attempt
+var portForwardBackoff = func(attempt int) time.Duration {
+	d := time.Second << (attempt - 1)
+	if d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d
+}
+
+// portForward is a kubectl port-forward tunnel managed by this server
+type portForward struct {
+	ID          string
+	Namespace   string
+	KubeContext string
+	Resource    string
+	Address     string
+	LocalPort   int
+	RemotePort  int
+	Args        []string
+
+	mu        sync.Mutex
+	proc      *executor.Background
+	restarts  int
+	failures  int
+	lastExit  string
+	stopped   bool
+	gaveUp    bool
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	createdAt time.Time
+}
+
+// state describes the current tunnel state
+func (f *portForward) state() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case f.stopped:
+		return "stopped"
+	case f.gaveUp:
+		return "failed"
+	case f.proc != nil && f.proc.Running():
+		return "active"
+	default:
+		return "restarting"
+	}
+}
+
+// portForwardInfo is the structured form of a port forward
+type portForwardInfo struct {
+	ID          string `json:"id"`
+	Resource    string `json:"resource"`
+	Namespace   string `json:"namespace,omitempty"`
+	KubeContext string `json:"kube_context,omitempty"`
+	Address     string `json:"address"`
+	LocalPort   int    `json:"local_port"`
+	RemotePort  int    `json:"remote_port"`
+	State       string `json:"state"`
+	Restarts    int    `json:"restarts"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+// info returns the structured form of the forward
+func (f *portForward) info() portForwardInfo {
+	state := f.state()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info := portForwardInfo{
+		ID:          f.ID,
+		Resource:    f.Resource,
+		Namespace:   f.Namespace,
+		KubeContext: f.KubeContext,
+		Address:     f.Address,
+		LocalPort:   f.LocalPort,
+		RemotePort:  f.RemotePort,
+		State:       state,
+		Restarts:    f.restarts,
+	}
+	if state != "active" {
+		info.LastError = f.lastExit
+	}
+	return info
+}
+
+// describe renders a single line summary of the forward
+func (f *portForward) describe() string {
+	state := f.state()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	line := fmt.Sprintf("%s  %s:%d -> %s:%d  namespace=%s  state=%s  restarts=%d",
+		f.ID, f.Address, f.LocalPort, f.Resource, f.RemotePort, displayNamespace(f.Namespace), state, f.restarts)
+	if f.KubeContext != "" {
+		line += "  context=" + f.KubeContext
+	}
+	if f.lastExit != "" && state != "active" {
+		line += "  last_error=" + f.lastExit
+	}
+	return line
+}
+
+// lastOutput returns recent kubectl output of the current process
+func (f *portForward) lastOutput(n int) []executor.Line {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.proc == nil {
+		return nil
+	}
+	return f.proc.Output().Tail(n)
+}
+
+// supervise waits for the tunnel process and restarts it when it drops
+func (f *portForward) supervise() {
+	defer close(f.doneCh)
+
+	for {
+		f.mu.Lock()
+		proc := f.proc
+		f.mu.Unlock()
+
+		select {
+		case <-f.stopCh:
+			proc.Stop(executor.DefaultStopGrace)
+			return
+		case <-proc.Done():
+		}
+
+		f.mu.Lock()
+		f.lastExit = lastLineOrExit(proc)
+		if time.Since(proc.StartedAt()) >= portForwardStableAfter {
+			f.failures = 0
+		}
+		f.failures++
+		if f.failures > portForwardMaxFailures {
+			f.gaveUp = true
+			f.mu.Unlock()
+			return
+		}
+		attempt := f.failures
+		f.mu.Unlock()
+
+		select {
+		case <-f.stopCh:
+			return
+		case <-time.After(portForwardBackoff(attempt)):
+		}
+
+		next, err := startProcess(executor.Command{Binary: executor.KubectlBinary, Args: f.Args}, portForwardOutputLines)
+
+		f.mu.Lock()
+		if err != nil {
+			f.lastExit = err.Error()
+			f.gaveUp = true
+			f.mu.Unlock()
+			return
+		}
+		f.proc = next
+		f.restarts++
+		f.mu.Unlock()
+	}
+}
+
+// lastLineOrExit summarizes why a tunnel process ended
+func lastLineOrExit(proc *executor.Background) string {
+	if lines := proc.Output().Tail(1); len(lines) == 1 {
+		return lines[0].Text
+	}
+	return fmt.Sprintf("exit code %d", proc.ExitCode())
+}
+
+// portForwardManager tracks all port forwards started by this server
+type portForwardManager struct {
+	mu       sync.Mutex
+	forwards map[string]*portForward
+	nextID   int
+}
+
+// portForwards holds all port forwards of the running server
+var portForwards = newPortForwardManager()
+
+func newPortForwardManager() *portForwardManager {
+	return &portForwardManager{forwards: make(map[string]*portForward)}
+}
+
+// start checks the local port, launches kubectl and begins supervision
+func (m *portForwardManager) start(f *portForward) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, existing := range m.forwards {
+		if existing.LocalPort != f.LocalPort || existing.Address != f.Address {
+			continue
+		}
+		// A forward that gave up no longer holds its port and is replaced
+		if state := existing.state(); state != "stopped" && state != "failed" {
+			return fmt.Errorf("local port %d is already used by port forward %s", f.LocalPort, existing.ID)
+		}
+		delete(m.forwards, id)
+	}
+	if err := checkPortFree(f.Address, f.LocalPort); err != nil {
+		return err
+	}
+
+	proc, err := startProcess(executor.Command{Binary: executor.KubectlBinary, Args: f.Args}, portForwardOutputLines)
+	if err != nil {
+		return fmt.Errorf("failed to start kubectl port-forward: %w", err)
+	}
+
+	m.nextID++
+	f.ID = fmt.Sprintf("pf-%d", m.nextID)
+	f.proc = proc
+	f.createdAt = time.Now()
+	f.stopCh = make(chan struct{})
+	f.doneCh = make(chan struct{})
+	m.forwards[f.ID] = f
+
+	go f.supervise()
+	return nil
+}
+
+// get returns the forward with the given ID
+func (m *portForwardManager) get(id string) (*portForward, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.forwards[id]
+	if !ok {
+		return nil, fmt.Errorf("port forward %q not found. Use devspace_port_forward_list to list active forwards", id)
+	}
+	return f, nil
+}
+
+// list returns all forwards ordered by creation
+func (m *portForwardManager) list() []*portForward {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	forwards := make([]*portForward, 0, len(m.forwards))
+	for _, f := range m.forwards {
+		forwards = append(forwards, f)
+	}
+	sort.Slice(forwards, func(i, j int) bool { return forwards[i].createdAt.Before(forwards[j].createdAt) })
+	return forwards
+}
+
+// stop terminates a forward and forgets it
+func (m *portForwardManager) stop(id string) (*portForward, error) {
+	f, err := m.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	alreadyStopped := f.stopped
+	f.stopped = true
+	f.mu.Unlock()
+	if !alreadyStopped {
+		close(f.stopCh)
+	}
+	<-f.doneCh
+
+	// A forward that gave up has no supervisor left to stop the process
+	f.mu.Lock()
+	proc := f.proc
+	f.mu.Unlock()
+	proc.Stop(executor.DefaultStopGrace)
+
+	m.mu.Lock()
+	delete(m.forwards, id)
+	m.mu.Unlock()
+	return f, nil
+}
+
+// stopAll terminates every forward, used on server shutdown
+func (m *portForwardManager) stopAll() {
+	var wg sync.WaitGroup
+	for _, f := range m.list() {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			m.stop(id)
+		}(f.ID)
+	}
+	wg.Wait()
+}
+
+// checkPortFree verifies that nothing is listening on the local port
+func checkPortFree(address string, port int) error {
+	l, err := net.Listen("tcp", net.JoinHostPort(address, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("local port %d on %s is not available: %v", port, address, err)
+	}
+	return l.Close()
+}
+
+// freePort asks the OS for an unused local port
+func freePort(address string) (int, error) {
+	l, err := net.Listen("tcp", net.JoinHostPort(address, "0"))
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// DevspacePortForwardTool returns the tool definition for starting a port forward
+func DevspacePortForwardTool() mcp.Tool {
+	return mcp.NewTool("devspace_port_forward",
+		mcp.WithDescription("Forward a local port to a pod, service or deployment using a managed background 'kubectl port-forward'. The tunnel is restarted automatically if it drops. Use devspace_port_forward_list and devspace_port_forward_stop to manage it."),
+		mcp.WithOutputSchema[portForwardInfo](),
+		withCategory(serverconfig.CategoryMutate),
+		mcp.WithString("resource",
+			mcp.Required(),
+			mcp.Description("Target to forward to (e.g., 'pod/api-0', 'svc/api', 'deployment/api')"),
+		),
+		mcp.WithNumber("remote_port",
+			mcp.Required(),
+			mcp.Description("Port on the target to forward to"),
+		),
+		mcp.WithNumber("local_port",
+			mcp.Description("Local port to listen on (default: a free port is chosen)"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
+		mcp.WithString("address",
+			mcp.Description("Local address to bind (default: 127.0.0.1)"),
+		),
+	)
+}
+
+// DevspacePortForwardHandler handles starting a port forward
+func DevspacePortForwardHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resource := req.GetString("resource", "")
+	if resource == "" {
+		return mcp.NewToolResultError("resource parameter is required"), nil
+	}
+	if err := ValidateStringParam("resource", resource); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	remotePort := req.GetInt("remote_port", 0)
+	if remotePort < 1 || remotePort > 65535 {
+		return mcp.NewToolResultError("remote_port must be between 1 and 65535"), nil
+	}
+
+	address := req.GetString("address", "127.0.0.1")
+	if net.ParseIP(address) == nil && address != "localhost" {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid address %q", address)), nil
+	}
+
+	localPort := req.GetInt("local_port", 0)
+	if localPort < 0 || localPort > 65535 {
+		return mcp.NewToolResultError("local_port must be between 1 and 65535"), nil
+	}
+	if localPort == 0 {
+		port, err := freePort(address)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("could not find a free local port: %v", err)), nil
+		}
+		localPort = port
+	}
+
+	args := []string{"port-forward"}
+
+	namespace := req.GetString("namespace", "")
+	if namespace != "" {
+		if err := ValidateStringParam("namespace", namespace); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "-n", namespace)
+	}
+	kubeContext := req.GetString("kube_context", "")
+	if kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--context", kubeContext)
+	}
+	args = append(args, "--address", address, resource, fmt.Sprintf("%d:%d", localPort, remotePort))
+
+	f := &portForward{
+		Namespace:   namespace,
+		KubeContext: kubeContext,
+		Resource:    resource,
+		Address:     address,
+		LocalPort:   localPort,
+		RemotePort:  remotePort,
+		Args:        args,
+	}
+	if err := portForwards.start(f); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// kubectl prints "Forwarding from ..." once the listener is up, or
+	// exits quickly when the target does not exist
+	waitForForward(ctx, f, devStartupWait)
+
+	var out strings.Builder
+	out.WriteString(f.describe() + "\n")
+	if lines := f.lastOutput(10); len(lines) > 0 {
+		out.WriteString("\n" + formatLines(lines))
+	}
+	if f.state() != "active" {
+		// Stop restarting a forward that never worked so a corrected
+		// retry can use its port right away
+		portForwards.stop(f.ID)
+		out.WriteString("\nThe port forward was stopped.\n")
+		return errorResult(req, executor.Result{Stderr: out.String(), ExitCode: 1}), nil
+	}
+	return mcp.NewToolResultStructured(f.info(), out.String()), nil
+}
+
+// waitForForward waits until kubectl reports the listener, exits, or the
+// timeout elapses
+func waitForForward(ctx context.Context, f *portForward, timeout time.Duration) {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		f.mu.Lock()
+		proc := f.proc
+		f.mu.Unlock()
+
+		for _, l := range proc.Output().Tail(5) {
+			if strings.HasPrefix(l.Text, "Forwarding from") {
+				return
+			}
+		}
+
+		select {
+		case <-proc.Done():
+			return
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DevspacePortForwardListTool returns the tool definition for listing port forwards
+func DevspacePortForwardListTool() mcp.Tool {
+	return mcp.NewTool("devspace_port_forward_list",
+		mcp.WithDescription("List port forwards managed by this server with their state and restart count"),
+		mcp.WithOutputSchema[portForwardsOutput](),
+		withCategory(serverconfig.CategoryRead),
+	)
+}
+
+// DevspacePortForwardListHandler handles listing port forwards
+func DevspacePortForwardListHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultStructured(portForwardsOutput{Forwards: portForwardInfos()}, describePortForwards()), nil
+}
+
+// portForwardInfos returns the structured form of all managed forwards
+func portForwardInfos() []portForwardInfo {
+	infos := []portForwardInfo{}
+	for _, f := range portForwards.list() {
+		infos = append(infos, f.info())
+	}
+	return infos
+}
+
+// describePortForwards lists all managed forwards, one per line
+func describePortForwards() string {
+	forwards := portForwards.list()
+	if len(forwards) == 0 {
+		return "No active port forwards. Start one with devspace_port_forward."
+	}
+
+	lines := make([]string, 0, len(forwards))
+	for _, f := range forwards {
+		lines = append(lines, f.describe())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DevspacePortForwardStopTool returns the tool definition for stopping a port forward
+func DevspacePortForwardStopTool() mcp.Tool {
+	return mcp.NewTool("devspace_port_forward_stop",
+		mcp.WithDescription("Stop a port forward started with devspace_port_forward"),
+		mcp.WithOutputSchema[portForwardInfo](),
+		withCategory(serverconfig.CategoryMutate),
+		mcp.WithString("forward_id",
+			mcp.Required(),
+			mcp.Description("ID of the port forward (e.g., 'pf-1')"),
+		),
+	)
+}
+
+// DevspacePortForwardStopHandler handles stopping a port forward
+func DevspacePortForwardStopHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id := req.GetString("forward_id", "")
+	if id == "" {
+		return mcp.NewToolResultError("forward_id parameter is required"), nil
+	}
+
+	f, err := portForwards.stop(id)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	return mcp.NewToolResultStructured(f.info(), f.describe()), nil
+}
+
+// displayNamespace renders a namespace, showing the kubeconfig default for an
+// empty value
+func displayNamespace(namespace string) string {
+	if namespace == "" {
+		return "(default)"
+	}
+	return namespace
+}
@@ -0,0 +1,282 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"devspace-mcp/executor"
+)
+
+// useShellPortForward makes port forwards run script with sh instead of kubectl
+func useShellPortForward(t *testing.T, script string) func() []executor.Command {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	var mu sync.Mutex
+	var started []executor.Command
+	previousStart, previousWait, previousBackoff, previousForwards := startProcess, devStartupWait, portForwardBackoff, portForwards
+	startProcess = func(c executor.Command, lines int) (*executor.Background, error) {
+		mu.Lock()
+		started = append(started, c)
+		mu.Unlock()
+		c.Binary = "sh"
+		c.Args = []string{"-c", script}
+		return executor.StartBackground(c, lines)
+	}
+	devStartupWait = time.Second
+	portForwardBackoff = func(int) time.Duration { return 10 * time.Millisecond }
+	portForwards = newPortForwardManager()
+	t.Cleanup(func() {
+		portForwards.stopAll()
+		startProcess, devStartupWait, portForwardBackoff, portForwards = previousStart, previousWait, previousBackoff, previousForwards
+	})
+
+	return func() []executor.Command {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]executor.Command(nil), started...)
+	}
+}
+
+func TestPortForwardLifecycle(t *testing.T) {
+	started := useShellPortForward(t, "echo 'Forwarding from 127.0.0.1:1 -> 80'; sleep 60")
+	ctx := context.Background()
+
+	result, _ := DevspacePortForwardHandler(ctx, newRequest(map[string]any{
+		"resource":     "svc/api",
+		"remote_port":  80,
+		"namespace":    "dev",
+		"kube_context": "kind-dev",
+	}))
+	if result.IsError {
+		t.Fatalf("port forward failed: %s", resultText(result))
+	}
+	if !strings.Contains(resultText(result), "pf-1") || !strings.Contains(resultText(result), "state=active") {
+		t.Errorf("unexpected output: %s", resultText(result))
+	}
+
+	args := started()[0].Args
+	if args[0] != "port-forward" || args[len(args)-2] != "svc/api" || !strings.HasSuffix(args[len(args)-1], ":80") {
+		t.Errorf("unexpected kubectl args: %v", args)
+	}
+	if !strings.Contains(strings.Join(args, " "), "-n dev --context kind-dev --address 127.0.0.1") {
+		t.Errorf("namespace/context/address flags missing: %v", args)
+	}
+
+	result, _ = DevspacePortForwardListHandler(ctx, newRequest(nil))
+	if !strings.Contains(resultText(result), "svc/api") {
+		t.Errorf("list should contain forward, got %s", resultText(result))
+	}
+
+	result, _ = DevspacePortForwardStopHandler(ctx, newRequest(map[string]any{"forward_id": "pf-1"}))
+	if result.IsError || !strings.Contains(resultText(result), "state=stopped") {
+		t.Errorf("unexpected stop output: %s", resultText(result))
+	}
+	if len(portForwards.list()) != 0 {
+		t.Error("stopped forward should be forgotten")
+	}
+}
+
+func TestPortForwardRestartsDroppedTunnel(t *testing.T) {
+	started := useShellPortForward(t, "echo 'Forwarding from 127.0.0.1:1 -> 80'; sleep 0.2; echo 'lost connection to pod' >&2; exit 1")
+
+	result, _ := DevspacePortForwardHandler(context.Background(), newRequest(map[string]any{
+		"resource":    "pod/api-0",
+		"remote_port": 8080,
+	}))
+	if result.IsError {
+		t.Fatalf("port forward failed: %s", resultText(result))
+	}
+
+	f, err := portForwards.get("pf-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarts := func() int {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.restarts
+	}
+
+	// The restart counter is updated after the new process has started
+	deadline := time.Now().Add(5 * time.Second)
+	for restarts() < 2 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if restarts() < 2 {
+		t.Fatalf("restarts = %d, want at least 2 (started %d times)", restarts(), len(started()))
+	}
+	for _, c := range started() {
+		if strings.Join(c.Args, " ") != strings.Join(started()[0].Args, " ") {
+			t.Errorf("restart should reuse the original arguments, got %v", c.Args)
+		}
+	}
+}
+
+func TestPortForwardGivesUpAfterRepeatedFailures(t *testing.T) {
+	ready := filepath.Join(t.TempDir(), "ready")
+	if err := os.WriteFile(ready, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The tunnel works until ready is removed and every restart fails
+	useShellPortForward(t, fmt.Sprintf("if [ -f %[1]s ]; then echo 'Forwarding from 127.0.0.1:1 -> 80'; while [ -f %[1]s ]; do sleep 0.05; done; fi; echo 'error: services \"api\" not found' >&2; exit 1", ready))
+
+	result, _ := DevspacePortForwardHandler(context.Background(), newRequest(map[string]any{
+		"resource":    "svc/api",
+		"remote_port": 80,
+	}))
+	if result.IsError {
+		t.Fatalf("port forward failed: %s", resultText(result))
+	}
+	if err := os.Remove(ready); err != nil {
+		t.Fatal(err)
+	}
+
+	f, _ := portForwards.get("pf-1")
+	deadline := time.Now().Add(5 * time.Second)
+	for f.state() != "failed" && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if f.state() != "failed" {
+		t.Errorf("state = %s, want failed", f.state())
+	}
+	if !strings.Contains(f.describe(), "not found") {
+		t.Errorf("describe should include the last error: %s", f.describe())
+	}
+}
+
+func TestPortForwardReusesPortOfFailedForward(t *testing.T) {
+	ready := filepath.Join(t.TempDir(), "ready")
+	if err := os.WriteFile(ready, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The forward works until ready is removed and every restart fails
+	useShellPortForward(t, fmt.Sprintf("if [ -f %[1]s ]; then echo 'Forwarding from 127.0.0.1:1 -> 80'; while [ -f %[1]s ]; do sleep 0.05; done; fi; echo 'error: pod not running' >&2; exit 1", ready))
+	port, err := freePort("127.0.0.1")
+	if err != nil {
+		t.Skipf("cannot pick a port: %v", err)
+	}
+	args := map[string]any{"resource": "pod/api-0", "remote_port": 80, "local_port": port}
+
+	if result, _ := DevspacePortForwardHandler(context.Background(), newRequest(args)); result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(result))
+	}
+	f, _ := portForwards.get("pf-1")
+	if err := os.Remove(ready); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for f.state() != "failed" && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if f.state() != "failed" {
+		t.Fatalf("state = %s, want failed", f.state())
+	}
+
+	if err := os.WriteFile(ready, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, _ := DevspacePortForwardHandler(context.Background(), newRequest(args))
+	if result.IsError {
+		t.Fatalf("a failed forward should not block its port: %s", resultText(result))
+	}
+	forwards := portForwards.list()
+	if len(forwards) != 1 || forwards[0].ID != "pf-2" || forwards[0].state() != "active" {
+		t.Errorf("the failed forward should be replaced, got %v", forwards)
+	}
+}
+
+func TestPortForwardRetryAfterFailedStart(t *testing.T) {
+	ready := filepath.Join(t.TempDir(), "ready")
+	useShellPortForward(t, fmt.Sprintf("if [ -f %s ]; then echo 'Forwarding from 127.0.0.1:1 -> 80'; sleep 60; else echo 'error: pod not running' >&2; exit 1; fi", ready))
+	port, err := freePort("127.0.0.1")
+	if err != nil {
+		t.Skipf("cannot pick a port: %v", err)
+	}
+	args := map[string]any{"resource": "pod/api-0", "remote_port": 80, "local_port": port}
+
+	result, _ := DevspacePortForwardHandler(context.Background(), newRequest(args))
+	if !result.IsError || !strings.Contains(resultText(result), "pod not running") {
+		t.Fatalf("expected the start to fail, got %s", resultText(result))
+	}
+	if forwards := portForwards.list(); len(forwards) != 0 {
+		t.Errorf("a forward that never started should be removed, got %v", forwards)
+	}
+
+	if err := os.WriteFile(ready, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := DevspacePortForwardHandler(context.Background(), newRequest(args)); result.IsError {
+		t.Fatalf("a retry should get the port straight away: %s", resultText(result))
+	}
+}
+
+func TestPortForwardRejectsBusyPort(t *testing.T) {
+	started := useShellPortForward(t, "sleep 60")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer l.Close()
+	busy := l.Addr().(*net.TCPAddr).Port
+
+	result, _ := DevspacePortForwardHandler(context.Background(), newRequest(map[string]any{
+		"resource":    "svc/api",
+		"remote_port": 80,
+		"local_port":  busy,
+	}))
+	if !result.IsError || !strings.Contains(resultText(result), "not available") {
+		t.Errorf("expected busy port error, got %s", resultText(result))
+	}
+	if len(started()) != 0 {
+		t.Error("kubectl should not be started for a busy port")
+	}
+}
+
+func TestPortForwardValidation(t *testing.T) {
+	useShellPortForward(t, "sleep 60")
+
+	tests := []map[string]any{
+		{"remote_port": 80},
+		{"resource": "svc/api"},
+		{"resource": "svc/api", "remote_port": 70000},
+		{"resource": "--address=0.0.0.0", "remote_port": 80},
+		{"resource": "svc/api", "remote_port": 80, "address": "not an ip"},
+	}
+	for _, args := range tests {
+		result, _ := DevspacePortForwardHandler(context.Background(), newRequest(args))
+		if !result.IsError {
+			t.Errorf("expected validation error for %v", args)
+		}
+	}
+}
+
+func TestDevspaceListPortsShowsActiveForwards(t *testing.T) {
+	useShellPortForward(t, "echo 'Forwarding from 127.0.0.1:1 -> 80'; sleep 60")
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"list", "ports"}, executor.Result{Stdout: "Image   LocalPort   RemotePort\napi     8080        80"})
+
+	DevspacePortForwardHandler(ctx, newRequest(map[string]any{"resource": "svc/api", "remote_port": 80}))
+
+	result, _ := DevspaceListPortsHandler(ctx, newRequest(map[string]any{"working_dir": "/work"}))
+	text := resultText(result)
+	if !strings.Contains(text, "8080        80") || !strings.Contains(text, "## Active Port Forwards") || !strings.Contains(text, "pf-1") {
+		t.Errorf("expected configured and active forwards, got:\n%s", text)
+	}
+
+	result, _ = DevspaceListPortsHandler(ctx, newRequest(map[string]any{"working_dir": "/work", "output": "json"}))
+	if strings.Contains(resultText(result), "Active Port Forwards") {
+		t.Error("json output should not include the active forwards section")
+	}
+}
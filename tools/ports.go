@@ -3,7 +3,7 @@ package tools
 import (
 	"context"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -11,10 +11,11 @@ import (
 // DevspaceListPortsTool returns the tool definition for listing port forwards
 func DevspaceListPortsTool() mcp.Tool {
 	return mcp.NewTool("devspace_list_ports",
-		mcp.WithDescription("Lists configured port forwarding rules from devspace.yaml. Shows which local ports will be forwarded to which container ports when running devspace dev."),
+		mcp.WithDescription("Lists configured port forwarding rules from devspace.yaml. Shows which local ports will be forwarded to which container ports when running devspace dev. In table output, port forwards started with devspace_port_forward are listed as well."),
+		mcp.WithOutputSchema[portsOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("working_dir",
-			mcp.Required(),
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 		mcp.WithString("output",
 			mcp.Description("Output format: table (default) or json"),
@@ -26,14 +27,15 @@ func DevspaceListPortsTool() mcp.Tool {
 func DevspaceListPortsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	workingDir := req.GetString("working_dir", "")
 	if workingDir == "" {
-		return mcp.NewToolResultError("working_dir parameter is required"), nil
+		return mcp.NewToolResultError("working_dir parameter is required; pass it or set it with devspace_set_context"), nil
 	}
 
 	// Build args
 	args := []string{"list", "ports"}
 
 	// Add output format if specified
-	if output := req.GetString("output", ""); output == "json" {
+	output := req.GetString("output", "")
+	if output == "json" {
 		if err := ValidateStringParam("output", output); err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -41,11 +43,19 @@ func DevspaceListPortsHandler(ctx context.Context, req mcp.CallToolRequest) (*mc
 	}
 
 	// Execute command
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
+	result := executeDevspace(ctx, commandTimeout("devspace_list_ports"), workingDir, args...)
 
 	if !result.Success() {
-		return mcp.NewToolResultError(EnhanceError(result)), nil
+		return errorResult(req, result), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	structured := portsOutput{ActiveForwards: portForwardInfos()}
+	if output == "json" {
+		structured.Ports = parsePortsJSON(result.Stdout)
+		// JSON output must stay machine readable
+		return mcp.NewToolResultStructured(structured, result.FormatOutput()), nil
+	}
+
+	structured.Ports = parsePorts(result.Stdout)
+	return mcp.NewToolResultStructured(structured, result.FormatOutput()+"\n\n## Active Port Forwards\n"+describePortForwards()), nil
 }
@@ -17,15 +17,14 @@ func TestDevspaceListPortsTool(t *testing.T) {
 		t.Error("tool description should not be empty")
 	}
 
-	// Verify working_dir is in required parameters
-	isRequired := false
+	// Verify working_dir is accepted but optional, as it may come from the
+	// session context
+	if _, ok := tool.InputSchema.Properties["working_dir"]; !ok {
+		t.Error("tool should accept a working_dir parameter")
+	}
 	for _, req := range tool.InputSchema.Required {
 		if req == "working_dir" {
-			isRequired = true
-			break
+			t.Error("working_dir parameter should not be required")
 		}
 	}
-	if !isRequired {
-		t.Error("working_dir parameter should be required")
-	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -12,6 +13,8 @@ import (
 func DevspacePrintTool() mcp.Tool {
 	return mcp.NewTool("devspace_print",
 		mcp.WithDescription("Print the resolved devspace configuration as YAML"),
+		mcp.WithOutputSchema[printOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("profile",
 			mcp.Description("Profile to apply when resolving the configuration"),
 		),
@@ -19,7 +22,10 @@ func DevspacePrintTool() mcp.Tool {
 			mcp.Description("Only print the configuration without additional info"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+		mcp.WithBoolean("offline",
+			mcp.Description(offlineDescription),
 		),
 	)
 }
@@ -40,11 +46,27 @@ func DevspacePrintHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 
 	workingDir := req.GetString("working_dir", "")
 
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
-
-	if !result.Success() {
-		return mcp.NewToolResultError(result.FormatOutput()), nil
+	var failed *executor.Result
+	if !req.GetBool("offline", false) {
+		result := executeDevspace(ctx, commandTimeout("devspace_print"), workingDir, args...)
+		if result.Success() {
+			output := printOutput{
+				Config: parsePrintedConfig(result.Stdout),
+				Output: result.FormatOutput(),
+				Source: sourceCLI,
+			}
+			return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
+		}
+		failed = &result
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	resolved, err := resolveNative(ctx, req)
+	if err != nil {
+		return nativeError(err, failed), nil
+	}
+	output, err := nativePrint(resolved)
+	if err != nil {
+		return nativeError(err, failed), nil
+	}
+	return nativeResult(output, output.Output, failed), nil
 }
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"devspace-mcp/executor"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// progressLogger is the logger name attached to streamed log notifications
+const progressLogger = "devspace"
+
+// outputForwarder returns a line callback that relays command output to the
+// calling MCP client. Each line is sent as a notifications/message log event
+// and, when the request carries a progress token, as a notifications/progress
+// update. Secrets are masked before sending. Returns nil when there is no
+// client session to notify.
+func outputForwarder(ctx context.Context, req mcp.CallToolRequest) executor.LineFunc {
+	s := server.ServerFromContext(ctx)
+	if s == nil {
+		return nil
+	}
+
+	var token mcp.ProgressToken
+	if req.Params.Meta != nil {
+		token = req.Params.Meta.ProgressToken
+	}
+
+	redactor := redactorFor(req)
+	lines := 0
+	return func(stream executor.Stream, line string) {
+		lines++
+		line, _ = redactor.Redact(line)
+
+		level := mcp.LoggingLevelInfo
+		if stream == executor.Stderr {
+			level = mcp.LoggingLevelWarning
+		}
+		// Notifications are best effort: a client that does not accept
+		// them still receives the full output in the tool result.
+		_ = s.SendLogMessageToClient(ctx, mcp.NewLoggingMessageNotification(level, progressLogger, line))
+
+		if token != nil {
+			_ = s.SendNotificationToClient(ctx, "notifications/progress", map[string]any{
+				"progressToken": token,
+				"progress":      lines,
+				"message":       line,
+			})
+		}
+	}
+}
+
+// executeDevspaceStreaming runs a devspace command and forwards its output to
+// the client line by line while it is running
+func executeDevspaceStreaming(ctx context.Context, req mcp.CallToolRequest, timeout time.Duration, workingDir string, args ...string) executor.Result {
+	return runnerFrom(ctx).Run(ctx, executor.Command{
+		Binary:  executor.DevspaceBinary,
+		Args:    args,
+		Dir:     workingDir,
+		Env:     devspaceEnv(ctx),
+		Timeout: timeout,
+		OnLine:  outputForwarder(ctx, req),
+	})
+}
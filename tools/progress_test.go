@@ -0,0 +1,85 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"devspace-mcp/executor"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// notifySession is a minimal client session that captures notifications
+type notifySession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notifySession) Initialize()       {}
+func (s *notifySession) Initialized() bool { return true }
+func (s *notifySession) SessionID() string { return "test-session" }
+func (s *notifySession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *notifySession) SetLogLevel(level mcp.LoggingLevel) {}
+func (s *notifySession) GetLogLevel() mcp.LoggingLevel      { return mcp.LoggingLevelDebug }
+
+func TestDevspaceBuildHandlerStreamsOutput(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"build"}, executor.Result{
+		Stdout: "build api\npush api\n",
+		Stderr: "cache miss\n",
+	})
+
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true), server.WithLogging())
+	s.AddTool(DevspaceBuildTool(), DevspaceBuildHandler)
+
+	session := &notifySession{notifications: make(chan mcp.JSONRPCNotification, 16)}
+	ctx = s.WithContext(ctx, session)
+
+	msg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"devspace_build","arguments":{},"_meta":{"progressToken":"tok-1"}}}`
+	response := s.HandleMessage(ctx, json.RawMessage(msg))
+	if _, ok := response.(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("expected JSON-RPC response, got %#v", response)
+	}
+	close(session.notifications)
+
+	var logs, progress []map[string]any
+	for n := range session.notifications {
+		switch n.Method {
+		case "notifications/message":
+			logs = append(logs, n.Params.AdditionalFields)
+		case "notifications/progress":
+			progress = append(progress, n.Params.AdditionalFields)
+		}
+	}
+
+	if len(logs) != 3 {
+		t.Fatalf("expected 3 log notifications, got %d", len(logs))
+	}
+	if logs[0]["data"] != "build api" || logs[0]["level"] != mcp.LoggingLevelInfo {
+		t.Errorf("unexpected first log notification: %v", logs[0])
+	}
+	if logs[2]["data"] != "cache miss" || logs[2]["level"] != mcp.LoggingLevelWarning {
+		t.Errorf("stderr line should be logged as warning: %v", logs[2])
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("expected 3 progress notifications, got %d", len(progress))
+	}
+	for i, p := range progress {
+		if p["progressToken"] != "tok-1" {
+			t.Errorf("progress token = %v, want tok-1", p["progressToken"])
+		}
+		if p["progress"] != i+1 {
+			t.Errorf("progress = %v, want %d", p["progress"], i+1)
+		}
+	}
+}
+
+func TestOutputForwarderWithoutServer(t *testing.T) {
+	if fn := outputForwarder(context.Background(), newRequest(nil)); fn != nil {
+		t.Error("expected no forwarder outside of an MCP request")
+	}
+}
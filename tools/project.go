@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"devspace-mcp/workspace"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// configEnvVar is the environment variable devspace reads the config path from
+const configEnvVar = "DEVSPACE_CONFIG"
+
+// projectOptionalTools work without a devspace config or report a missing
+// config themselves, so they are not refused when none is found
+var projectOptionalTools = map[string]bool{
+	"devspace_analyze": true,
+	"devspace_exec":    true,
+	"devspace_logs":    true,
+	"devspace_status":  true,
+	"devspace_sync":    true,
+}
+
+// ConfigLocation is a devspace config file and the directory devspace runs in
+type ConfigLocation struct {
+	// Dir is the directory containing the config file
+	Dir string
+	// Path is the absolute path of the config file
+	Path string
+}
+
+// isDefault reports whether devspace finds the config in Dir on its own
+func (l ConfigLocation) isDefault() bool {
+	return l.Path == filepath.Join(l.Dir, workspace.ConfigFileNames[0])
+}
+
+// LocateDevspaceConfig finds the devspace config for workingDir. An explicit
+// config path, relative to workingDir, takes precedence over DEVSPACE_CONFIG.
+// Otherwise workingDir and its parents are searched for devspace.yaml and
+// devspace.yml. An empty workingDir means the current directory. The error
+// lists every candidate that was checked.
+func LocateDevspaceConfig(workingDir, config string) (ConfigLocation, error) {
+	if workingDir == "" {
+		var err error
+		workingDir, err = os.Getwd()
+		if err != nil {
+			return ConfigLocation{}, fmt.Errorf("could not determine current directory: %w", err)
+		}
+	}
+	dir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return ConfigLocation{}, fmt.Errorf("invalid working_dir %s: %w", workingDir, err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return ConfigLocation{}, fmt.Errorf("working_dir %s is not a directory", workingDir)
+	}
+
+	source := "config parameter"
+	if config == "" {
+		config, source = os.Getenv(configEnvVar), configEnvVar
+	}
+	if config != "" {
+		path := config
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			return ConfigLocation{}, fmt.Errorf("devspace config %s from %s not found", path, source)
+		}
+		return ConfigLocation{Dir: filepath.Dir(path), Path: path}, nil
+	}
+
+	var checked []string
+	for {
+		for _, name := range workspace.ConfigFileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return ConfigLocation{Dir: dir, Path: path}, nil
+			}
+			checked = append(checked, path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return ConfigLocation{}, fmt.Errorf("devspace.yaml not found in %s or its parent directories. Use the working_dir or config parameter to specify the project location. Checked:\n  %s",
+		workingDir, strings.Join(checked, "\n  "))
+}
+
+// configMetaKey is the result _meta field holding the config path used
+const configMetaKey = "config"
+
+// configLocationKey is the context key of the located devspace config
+type configLocationKey struct{}
+
+// configLocationFrom returns the devspace config located for the current call
+func configLocationFrom(ctx context.Context) (ConfigLocation, bool) {
+	loc, ok := ctx.Value(configLocationKey{}).(ConfigLocation)
+	return loc, ok
+}
+
+// devspaceEnv returns the environment that points devspace at the config
+// located for the current call when it would not find it on its own
+func devspaceEnv(ctx context.Context) []string {
+	if loc, ok := configLocationFrom(ctx); ok && !loc.isDefault() {
+		return []string{configEnvVar + "=" + loc.Path}
+	}
+	return nil
+}
+
+// withProject locates the devspace config of tools accepting a working_dir
+// before they run. The call runs in the directory of the config, so tools can
+// be pointed at any subdirectory of a project. Tools that need a config are
+// refused with the list of checked paths when none is found.
+func withProject(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	if _, ok := tool.InputSchema.Properties["working_dir"]; !ok || managesContext(tool) {
+		return tool, handler
+	}
+
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, schema := range tool.InputSchema.Properties {
+		properties[name] = schema
+	}
+	properties["config"] = map[string]any{
+		"type":        "string",
+		"description": "Path of the devspace config file relative to working_dir (like devspace --config). By default devspace.yaml or devspace.yml is searched in working_dir and its parents",
+	}
+	tool.InputSchema.Properties = properties
+
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		config := req.GetString("config", "")
+		if err := ValidateStringParam("config", config); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		loc, err := LocateDevspaceConfig(req.GetString("working_dir", ""), config)
+		if err != nil {
+			if projectOptionalTools[tool.Name] {
+				return handler(ctx, req)
+			}
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		args := make(map[string]any, len(req.GetArguments())+2)
+		for k, v := range req.GetArguments() {
+			args[k] = v
+		}
+		args["working_dir"] = loc.Dir
+		args["config"] = loc.Path
+		req.Params.Arguments = args
+
+		result, err := handler(context.WithValue(ctx, configLocationKey{}, loc), req)
+		if result != nil && err == nil {
+			for i, content := range result.Content {
+				if text, ok := content.(mcp.TextContent); ok {
+					text.Text += "\n\n📄 Config: " + loc.Path
+					result.Content[i] = text
+					break
+				}
+			}
+			if result.Meta == nil {
+				result.Meta = &mcp.Meta{}
+			}
+			if result.Meta.AdditionalFields == nil {
+				result.Meta.AdditionalFields = make(map[string]any)
+			}
+			result.Meta.AdditionalFields[configMetaKey] = loc.Path
+		}
+		return result, err
+	}
+}
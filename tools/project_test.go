@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
+)
+
+// projectDir creates a project directory with a devspace config of the given name
+func projectDir(t *testing.T, name string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("version: v2beta1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// useProject points DEVSPACE_CONFIG at a new project for calls without a
+// working_dir and returns the project directory
+func useProject(t *testing.T) string {
+	t.Helper()
+	dir := projectDir(t, "devspace.yaml")
+	t.Setenv(configEnvVar, filepath.Join(dir, "devspace.yaml"))
+	return dir
+}
+
+func TestLocateDevspaceConfig(t *testing.T) {
+	root := projectDir(t, "devspace.yaml")
+	nested := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	yml := projectDir(t, "devspace.yml")
+	custom := projectDir(t, "devspace-ci.yaml")
+
+	tests := []struct {
+		name       string
+		workingDir string
+		config     string
+		env        string
+		wantPath   string
+		wantErr    string
+	}{
+		{name: "in working dir", workingDir: root, wantPath: filepath.Join(root, "devspace.yaml")},
+		{name: "in parent", workingDir: nested, wantPath: filepath.Join(root, "devspace.yaml")},
+		{name: "alternate name", workingDir: yml, wantPath: filepath.Join(yml, "devspace.yml")},
+		{name: "config parameter", workingDir: custom, config: "devspace-ci.yaml", wantPath: filepath.Join(custom, "devspace-ci.yaml")},
+		{name: "environment", workingDir: nested, env: filepath.Join(custom, "devspace-ci.yaml"), wantPath: filepath.Join(custom, "devspace-ci.yaml")},
+		{name: "parameter before environment", workingDir: root, config: "devspace.yaml", env: filepath.Join(custom, "devspace-ci.yaml"), wantPath: filepath.Join(root, "devspace.yaml")},
+		{name: "missing config parameter", workingDir: root, config: "other.yaml", wantErr: "from config parameter not found"},
+		{name: "missing working dir", workingDir: filepath.Join(root, "missing"), wantErr: "is not a directory"},
+		{name: "not found", workingDir: custom, wantErr: filepath.Join(custom, "devspace.yml")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(configEnvVar, tt.env)
+			loc, err := LocateDevspaceConfig(tt.workingDir, tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if loc.Path != tt.wantPath || loc.Dir != filepath.Dir(tt.wantPath) {
+				t.Errorf("located %+v, want %s", loc, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestLocateDevspaceConfigListsCandidates(t *testing.T) {
+	t.Setenv(configEnvVar, "")
+	dir := t.TempDir()
+	_, err := LocateDevspaceConfig(dir, "")
+	if err == nil {
+		t.Skip("a devspace config exists above the temp directory")
+	}
+	for _, want := range []string{filepath.Join(dir, "devspace.yaml"), filepath.Join(filepath.Dir(dir), "devspace.yml")} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error should list %s: %v", want, err)
+		}
+	}
+}
+
+func TestToolsRunInConfigDir(t *testing.T) {
+	t.Setenv(configEnvVar, "")
+	s, fake := useConfig(t, serverconfig.Default())
+	fake.On("devspace", []string{"list", "profiles"}, executor.Result{Stdout: "Name  Active\n"})
+	fake.On("devspace", []string{"logs"}, executor.Result{Stdout: "log line\n"})
+
+	root := projectDir(t, "devspace.yml")
+	nested := filepath.Join(root, "src")
+	if err := os.Mkdir(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := s.GetTool("devspace_list_profiles").Handler(context.Background(), newRequest(map[string]any{"working_dir": nested}))
+	if result.IsError {
+		t.Fatalf("list profiles failed: %s", resultText(result))
+	}
+	call := fake.Calls()[0]
+	if call.Dir != root {
+		t.Errorf("dir = %s, want %s", call.Dir, root)
+	}
+	if want := configEnvVar + "=" + filepath.Join(root, "devspace.yml"); !slices.Contains(call.Env, want) {
+		t.Errorf("env = %v, want %s", call.Env, want)
+	}
+	if !strings.Contains(resultText(result), "📄 Config: "+filepath.Join(root, "devspace.yml")) {
+		t.Errorf("result should name the config: %s", resultText(result))
+	}
+
+	empty := t.TempDir()
+	result, _ = s.GetTool("devspace_list_profiles").Handler(context.Background(), newRequest(map[string]any{"working_dir": empty}))
+	if !result.IsError || !strings.Contains(resultText(result), filepath.Join(empty, "devspace.yaml")) {
+		t.Errorf("expected an error listing the checked paths, got %s", resultText(result))
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("devspace should not run without a config, got %d calls", len(fake.Calls()))
+	}
+
+	// Logs work without a config
+	result, _ = s.GetTool("devspace_logs").Handler(context.Background(), newRequest(map[string]any{"working_dir": empty}))
+	if result.IsError || len(fake.Calls()) != 2 {
+		t.Errorf("logs should run without a config, got %s", resultText(result))
+	}
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"devspace-mcp/serverconfig"
+	"devspace-mcp/workspace"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// projectRegistry holds the devspace projects found under the project roots
+var projectRegistry = workspace.NewRegistry(nil)
+
+// SetProjects installs the project registry. It must be called before
+// RegisterAll so tools accept the project parameter.
+func SetProjects(r *workspace.Registry) {
+	projectRegistry = r
+}
+
+// lookupProject finds a registered project, rescanning the roots once when
+// it is not known yet
+func lookupProject(name string) (workspace.Project, error) {
+	if len(projectRegistry.Roots()) == 0 {
+		return workspace.Project{}, fmt.Errorf("no project roots configured; set projects.roots in the server configuration")
+	}
+	p, err := projectRegistry.Lookup(name)
+	if err != nil {
+		if _, scanErr := projectRegistry.Scan(); scanErr == nil {
+			p, err = projectRegistry.Lookup(name)
+		}
+	}
+	return p, err
+}
+
+// withProjectParam lets tools accepting a working_dir be pointed at a
+// registered project by name instead
+func withProjectParam(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	if _, ok := tool.InputSchema.Properties["working_dir"]; !ok || managesContext(tool) || len(projectRegistry.Roots()) == 0 {
+		return tool, handler
+	}
+
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, schema := range tool.InputSchema.Properties {
+		properties[name] = schema
+	}
+	properties["project"] = map[string]any{
+		"type":        "string",
+		"description": "Name or directory of a project listed by devspace_list_projects; an alternative to working_dir",
+	}
+	tool.InputSchema.Properties = properties
+
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		name := req.GetString("project", "")
+		if name == "" {
+			return handler(ctx, req)
+		}
+		if req.GetString("working_dir", "") != "" {
+			return mcp.NewToolResultError("pass either project or working_dir, not both"), nil
+		}
+		p, err := lookupProject(name)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		args := make(map[string]any, len(req.GetArguments())+2)
+		for k, v := range req.GetArguments() {
+			args[k] = v
+		}
+		args["working_dir"] = p.Dir
+		if req.GetString("config", "") == "" {
+			args["config"] = p.ConfigPath
+		}
+		req.Params.Arguments = args
+		return handler(ctx, req)
+	}
+}
+
+// projectsOutput is the result of devspace_list_projects
+type projectsOutput struct {
+	Roots    []string            `json:"roots"`
+	Projects []workspace.Project `json:"projects"`
+}
+
+// DevspaceListProjectsTool returns the tool definition for listing projects
+func DevspaceListProjectsTool() mcp.Tool {
+	return mcp.NewTool("devspace_list_projects",
+		mcp.WithDescription("List the devspace projects found under the configured project roots with their profiles, pipelines and dependencies. Pass a project name as the project parameter of other tools instead of working_dir."),
+		mcp.WithOutputSchema[projectsOutput](),
+		withCategory(serverconfig.CategoryRead),
+		mcp.WithBoolean("refresh",
+			mcp.Description("Rescan the project roots before listing instead of using the periodically refreshed registry"),
+		),
+	)
+}
+
+// DevspaceListProjectsHandler handles listing projects
+func DevspaceListProjectsHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	out := projectsOutput{Roots: projectRegistry.Roots(), Projects: []workspace.Project{}}
+	if len(out.Roots) == 0 {
+		return mcp.NewToolResultStructured(out, "No project roots configured. Set projects.roots in the server configuration or pass --project-roots."), nil
+	}
+
+	if req.GetBool("refresh", false) || projectRegistry.Scanned().IsZero() {
+		if _, err := projectRegistry.Scan(); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	out.Projects = projectRegistry.Projects()
+	return mcp.NewToolResultStructured(out, formatProjects(out)), nil
+}
+
+// formatProjects renders the projects as text, one block per project
+func formatProjects(out projectsOutput) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Projects (%d)\n\nRoots: %s\n", len(out.Projects), strings.Join(out.Roots, ", "))
+	if len(out.Projects) == 0 {
+		b.WriteString("\nNo devspace.yaml found under the project roots.\n")
+	}
+	for _, p := range out.Projects {
+		fmt.Fprintf(&b, "\n## %s\n%s\n", p.Name, p.ConfigPath)
+		if p.Error != "" {
+			fmt.Fprintf(&b, "❌ %s\n", p.Error)
+			continue
+		}
+		for _, list := range []struct {
+			label string
+			items []string
+		}{
+			{"Profiles", p.Profiles},
+			{"Pipelines", p.Pipelines},
+			{"Dependencies", p.Dependencies},
+		} {
+			if len(list.items) > 0 {
+				fmt.Fprintf(&b, "%s: %s\n", list.label, strings.Join(list.items, ", "))
+			}
+		}
+	}
+	return b.String()
+}
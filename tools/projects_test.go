@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
+	"devspace-mcp/workspace"
+)
+
+// useProjects installs a registry of the projects under root
+func useProjects(t *testing.T, root string) {
+	t.Helper()
+	previous := projectRegistry
+	t.Cleanup(func() { projectRegistry = previous })
+
+	r := workspace.NewRegistry([]string{root})
+	if _, err := r.Scan(); err != nil {
+		t.Fatal(err)
+	}
+	SetProjects(r)
+}
+
+func TestListProjects(t *testing.T) {
+	root := t.TempDir()
+	api := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(api, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "name: api\nprofiles:\n  - name: staging\npipelines:\n  dev:\n    run: start_dev --all\n"
+	if err := os.WriteFile(filepath.Join(api, "devspace.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	useProjects(t, root)
+	s, _ := useConfig(t, serverconfig.Default())
+
+	result, err := s.GetTool("devspace_list_projects").Handler(context.Background(), newRequest(map[string]any{}))
+	if err != nil || result.IsError {
+		t.Fatalf("list projects failed: %v %s", err, resultText(result))
+	}
+	out := result.StructuredContent.(projectsOutput)
+	if len(out.Projects) != 1 || out.Projects[0].Name != "api" || out.Projects[0].Dir != api {
+		t.Fatalf("unexpected projects: %+v", out.Projects)
+	}
+	for _, want := range []string{"## api", "Profiles: staging", "Pipelines: dev"} {
+		if !strings.Contains(resultText(result), want) {
+			t.Errorf("text should contain %q: %s", want, resultText(result))
+		}
+	}
+
+	// A project added later is found by a refresh
+	worker := filepath.Join(root, "services", "worker")
+	if err := os.MkdirAll(worker, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worker, "devspace.yaml"), []byte("name: worker\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, _ = s.GetTool("devspace_list_projects").Handler(context.Background(), newRequest(map[string]any{"refresh": true}))
+	if n := len(result.StructuredContent.(projectsOutput).Projects); n != 2 {
+		t.Errorf("expected 2 projects after refresh, got %d", n)
+	}
+}
+
+func TestProjectParameter(t *testing.T) {
+	t.Setenv(configEnvVar, "")
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	if err := os.Mkdir(api, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(api, "devspace.yml"), []byte("name: api\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	useProjects(t, root)
+	s, fake := useConfig(t, serverconfig.Default())
+	fake.On("devspace", []string{"list", "profiles"}, executor.Result{Stdout: "Name  Active\n"})
+
+	if _, ok := s.GetTool("devspace_list_profiles").Tool.InputSchema.Properties["project"]; !ok {
+		t.Fatal("tools accepting working_dir should accept project")
+	}
+
+	handler := s.GetTool("devspace_list_profiles").Handler
+	result, _ := handler(context.Background(), newRequest(map[string]any{"project": "api"}))
+	if result.IsError {
+		t.Fatalf("list profiles failed: %s", resultText(result))
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].Dir != api {
+		t.Errorf("expected one call in %s, got %+v", api, calls)
+	}
+
+	for name, args := range map[string]map[string]any{
+		"unknown project": {"project": "web"},
+		"both":            {"project": "api", "working_dir": api},
+	} {
+		if result, _ := handler(context.Background(), newRequest(args)); !result.IsError {
+			t.Errorf("%s: expected an error, got %s", name, resultText(result))
+		}
+	}
+}
+
+func TestProjectParameterRequiresRoots(t *testing.T) {
+	previous := projectRegistry
+	t.Cleanup(func() { projectRegistry = previous })
+	SetProjects(workspace.NewRegistry(nil))
+	s, _ := useConfig(t, serverconfig.Default())
+
+	if _, ok := s.GetTool("devspace_deploy").Tool.InputSchema.Properties["project"]; ok {
+		t.Error("project should not be offered without project roots")
+	}
+	result, _ := s.GetTool("devspace_list_projects").Handler(context.Background(), newRequest(map[string]any{}))
+	if !strings.Contains(resultText(result), "No project roots configured") {
+		t.Errorf("unexpected result: %s", resultText(result))
+	}
+}
@@ -3,7 +3,7 @@ package tools
 import (
 	"context"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -12,6 +12,8 @@ import (
 func DevspacePurgeTool() mcp.Tool {
 	return mcp.NewTool("devspace_purge",
 		mcp.WithDescription("WARNING: Destructive operation. Delete all deployed Kubernetes resources for the project. This cannot be undone."),
+		mcp.WithOutputSchema[commandOutput](),
+		withCategory(serverconfig.CategoryDestructive),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace"),
 		),
@@ -25,7 +27,7 @@ func DevspacePurgeTool() mcp.Tool {
 			mcp.Description("Force purge even if resources are in use"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 	)
 }
@@ -58,11 +60,11 @@ func DevspacePurgeHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 
 	workingDir := req.GetString("working_dir", "")
 
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
+	result := executeDevspace(ctx, commandTimeout("devspace_purge"), workingDir, args...)
 
 	if !result.Success() {
 		return mcp.NewToolResultError(result.FormatOutput()), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	return mcp.NewToolResultStructured(newCommandOutput(args, result), result.FormatOutput()), nil
 }
@@ -0,0 +1,328 @@
+package tools
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedPrefix starts every placeholder
+const redactedPrefix = "[REDACTED:"
+
+// minSecretLength is the shortest var value masked literally. Shorter
+// values would mask unrelated text all over the output.
+const minSecretLength = 4
+
+// secretVarPattern matches var names treated as secret even without
+// password: true, like DB_PASSWORD, GITHUB_TOKEN, AWS_ACCESS_KEY_ID or
+// CREDENTIALS. KEY only counts as a whole word so KEYCLOAK_URL is kept.
+var secretVarPattern = regexp.MustCompile(`(?i)secret|passw(?:or)?d|token|credential|apikey|(?:^|_)keys?(?:_|$)`)
+
+// redactPattern masks matches of re. When the expression has a capture
+// group only the first group is masked, so context like "Bearer " stays.
+type redactPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// builtinRedactPatterns are the token formats masked by default
+var builtinRedactPatterns = []redactPattern{
+	{"aws_access_key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"aws_secret_key", regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=|]\s*["']?([A-Za-z0-9/+=]{40})`)},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)},
+	{"bearer_token", regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/-]+=*)`)},
+	{"basic_auth", regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+:([^\s/@]+)@`)},
+}
+
+// redactKey keys the placeholder hashes so they cannot be used to guess
+// short secrets. Placeholders are stable for the lifetime of the server.
+var redactKey = func() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}()
+
+// Redactor masks secrets in tool output
+type Redactor struct {
+	patterns []redactPattern
+	// values maps literal secret values to their kind
+	values map[string]string
+}
+
+// activeRedactor is applied to the output of every tool
+var activeRedactor = &Redactor{patterns: builtinRedactPatterns}
+
+// SetRedactPatterns adds user regular expressions to the built-in token
+// formats. An expression with a capture group only masks the first group.
+func SetRedactPatterns(patterns []string) error {
+	r := &Redactor{patterns: append([]redactPattern{}, builtinRedactPatterns...)}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("invalid redact pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, redactPattern{"custom", re})
+	}
+	activeRedactor = r
+	return nil
+}
+
+// forProject returns a redactor that also masks the secret vars of the
+// devspace config located for workingDir and config. Values are taken from
+// the config, the environment and the devspace var cache.
+func (r *Redactor) forProject(workingDir, config string) *Redactor {
+	loc, err := LocateDevspaceConfig(workingDir, config)
+	if err != nil {
+		return r
+	}
+	names := secretVars(loc.Path)
+	if len(names) == 0 {
+		return r
+	}
+
+	p := &Redactor{patterns: append([]redactPattern{}, r.patterns...), values: make(map[string]string)}
+	cached := cachedVars(loc.Dir)
+	for name, value := range names {
+		for _, v := range []string{value, os.Getenv(name), cached[name]} {
+			if len(v) >= minSecretLength {
+				p.values[v] = name
+			}
+		}
+		// Mask the value wherever the var is printed with its name, as in
+		// the 'devspace list vars' table, env output and YAML
+		p.patterns = append(p.patterns, redactPattern{name,
+			regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b["']?(?:[ \t]*[:=|][ \t]*|[ \t]{2,})["']?([^\s"'|]+)`)})
+	}
+	return p
+}
+
+// Redact masks all secrets in text and returns the number of items masked
+func (r *Redactor) Redact(text string) (string, int) {
+	count := 0
+
+	// Replace longer values first so a value containing another is masked whole
+	values := make([]string, 0, len(r.values))
+	for v := range r.values {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, v := range values {
+		if n := strings.Count(text, v); n > 0 {
+			text = strings.ReplaceAll(text, v, placeholder(r.values[v], v))
+			count += n
+		}
+	}
+
+	for _, p := range r.patterns {
+		var n int
+		text, n = p.replace(text)
+		count += n
+	}
+	return text, count
+}
+
+// replace masks all matches of the pattern in text
+func (p redactPattern) replace(text string) (string, int) {
+	matches := p.re.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, 0
+	}
+
+	var b strings.Builder
+	last, count := 0, 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 {
+			start, end = m[2], m[3]
+		}
+		// Skip empty groups, values that are already placeholders and
+		// matches inside a placeholder
+		if start < 0 || start == end || strings.HasPrefix(text[start:end], redactedPrefix) ||
+			strings.HasSuffix(text[:m[0]], redactedPrefix) {
+			continue
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(placeholder(p.kind, text[start:end]))
+		last = end
+		count++
+	}
+	b.WriteString(text[last:])
+	return b.String(), count
+}
+
+// placeholder returns the stable replacement for a secret value
+func placeholder(kind, value string) string {
+	mac := hmac.New(sha256.New, redactKey)
+	mac.Write([]byte(value))
+	return redactedPrefix + kind + ":" + hex.EncodeToString(mac.Sum(nil))[:8] + "]"
+}
+
+// secretVars returns the vars of the devspace config at path that are
+// marked with password: true or named like a secret, with their configured
+// value or default
+func secretVars(path string) map[string]string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var config struct {
+		Vars yaml.Node `yaml:"vars"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil
+	}
+
+	type varDef struct {
+		Value    any  `yaml:"value"`
+		Default  any  `yaml:"default"`
+		Password bool `yaml:"password"`
+	}
+	secrets := make(map[string]string)
+	add := func(name string, node *yaml.Node) {
+		var def varDef
+		if node.Kind == yaml.ScalarNode {
+			def.Value = node.Value
+		} else if err := node.Decode(&def); err != nil {
+			return
+		}
+		if !def.Password && !secretVarPattern.MatchString(name) {
+			return
+		}
+		value := def.Value
+		if value == nil {
+			value = def.Default
+		}
+		if value == nil {
+			secrets[name] = ""
+		} else {
+			secrets[name] = fmt.Sprint(value)
+		}
+	}
+
+	switch config.Vars.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(config.Vars.Content); i += 2 {
+			add(config.Vars.Content[i].Value, config.Vars.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		// Older configs list vars with a name field
+		for _, node := range config.Vars.Content {
+			var def struct {
+				Name string `yaml:"name"`
+			}
+			if node.Decode(&def) == nil && def.Name != "" {
+				add(def.Name, node)
+			}
+		}
+	}
+	return secrets
+}
+
+// cachedVars returns the var values devspace remembers in .devspace/cache.yaml
+func cachedVars(workingDir string) map[string]string {
+	data, err := os.ReadFile(filepath.Join(workingDir, ".devspace", "cache.yaml"))
+	if err != nil {
+		return nil
+	}
+	var cache struct {
+		Vars map[string]string `yaml:"vars"`
+	}
+	if err := yaml.Unmarshal(data, &cache); err != nil {
+		return nil
+	}
+	return cache.Vars
+}
+
+// redactorFor returns the redactor for a tool call
+func redactorFor(req mcp.CallToolRequest) *Redactor {
+	return activeRedactor.forProject(req.GetString("working_dir", ""), req.GetString("config", ""))
+}
+
+// redactTool wraps a handler so secrets are masked in its text and
+// structured results. The number of masked items is appended to the text
+// and reported as the "redactions" field of the result metadata.
+func redactTool(tool mcp.Tool, handler server.ToolHandlerFunc) (mcp.Tool, server.ToolHandlerFunc) {
+	return tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if result == nil || err != nil {
+			return result, err
+		}
+		redactResult(result, redactorFor(req))
+		return result, nil
+	}
+}
+
+// redactResult masks secrets in a tool result in place
+func redactResult(result *mcp.CallToolResult, r *Redactor) {
+	count := 0
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			var n int
+			text.Text, n = r.Redact(text.Text)
+			result.Content[i] = text
+			count += n
+		}
+	}
+
+	if result.StructuredContent != nil {
+		if n := redactStructured(result, r); n > count {
+			count = n
+		}
+	}
+
+	if count == 0 {
+		return
+	}
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			text.Text += fmt.Sprintf("\n\n🔒 Redacted %d secret value(s)", count)
+			result.Content[i] = text
+			break
+		}
+	}
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields["redactions"] = count
+}
+
+// redactStructured masks secrets in the strings of the structured content.
+// The strings are redacted after decoding because JSON escapes characters
+// like < and " that secret values may contain. The content is only
+// rewritten when something was masked.
+func redactStructured(result *mcp.CallToolResult, r *Redactor) int {
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return 0
+	}
+	var content any
+	if err := json.Unmarshal(data, &content); err != nil {
+		return 0
+	}
+	count := 0
+	content = mapStrings(content, func(s string) string {
+		s, n := r.Redact(s)
+		count += n
+		return s
+	})
+	if count > 0 {
+		result.StructuredContent = content
+	}
+	return count
+}
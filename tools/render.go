@@ -0,0 +1,451 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Diff states reported for a rendered resource
+const (
+	diffUnchanged = "unchanged"
+	diffChanged   = "changed"
+	diffNew       = "new"
+	diffUnknown   = "unknown"
+)
+
+// secretMask replaces Secret values, like kubectl diff does
+const secretMask = "***"
+
+// secretDataFields hold the values of a Secret
+var secretDataFields = []string{"data", "stringData"}
+
+// documentSeparatorPattern splits a multi-document YAML stream
+var documentSeparatorPattern = regexp.MustCompile(`(?m)^---\s*$`)
+
+// manifestStartPattern matches the first line of a manifest when devspace
+// log lines precede it in the same document
+var manifestStartPattern = regexp.MustCompile(`(?m)^(apiVersion|kind):`)
+
+// renderedResource is a single Kubernetes object from the render output
+type renderedResource struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+	Manifest   string `json:"manifest,omitempty"`
+	object     map[string]any
+
+	DiffState string `json:"diff_state,omitempty" jsonschema:"enum=unchanged,enum=changed,enum=new,enum=unknown"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+// id returns the Kind/name identifier of the resource
+func (r renderedResource) id() string {
+	return r.Kind + "/" + r.Name
+}
+
+// kubectlType returns the fully qualified resource type for kubectl, e.g.
+// "Deployment.v1.apps", so that kinds with the same name in different API
+// groups are not confused
+func (r renderedResource) kubectlType() string {
+	group, version, found := strings.Cut(r.APIVersion, "/")
+	if !found {
+		return r.Kind
+	}
+	return r.Kind + "." + version + "." + group
+}
+
+// splitManifests parses rendered output into individual resources. Documents
+// that do not look like Kubernetes objects (log lines, empty documents) are
+// skipped and List objects are expanded into their items.
+func splitManifests(output string) []renderedResource {
+	var resources []renderedResource
+
+	for _, doc := range documentSeparatorPattern.Split(output, -1) {
+		var object map[string]any
+		if err := yaml.Unmarshal([]byte(doc), &object); err != nil || object["kind"] == nil {
+			loc := manifestStartPattern.FindStringIndex(doc)
+			if loc == nil {
+				continue
+			}
+			doc = doc[loc[0]:]
+			object = nil
+			if err := yaml.Unmarshal([]byte(doc), &object); err != nil {
+				continue
+			}
+		}
+		resources = append(resources, resourcesFromObject(object, doc)...)
+	}
+	return resources
+}
+
+// resourcesFromObject converts a decoded document into resources
+func resourcesFromObject(object map[string]any, manifest string) []renderedResource {
+	kind, _ := object["kind"].(string)
+	apiVersion, _ := object["apiVersion"].(string)
+	if kind == "" || apiVersion == "" {
+		return nil
+	}
+
+	if strings.HasSuffix(kind, "List") {
+		var resources []renderedResource
+		items, _ := object["items"].([]any)
+		for _, item := range items {
+			itemObject, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			encoded, err := yaml.Marshal(itemObject)
+			if err != nil {
+				continue
+			}
+			resources = append(resources, resourcesFromObject(itemObject, string(encoded))...)
+		}
+		return resources
+	}
+
+	r := renderedResource{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Manifest:   strings.TrimSpace(manifest) + "\n",
+		object:     object,
+	}
+	if metadata, ok := object["metadata"].(map[string]any); ok {
+		r.Name, _ = metadata["name"].(string)
+		r.Namespace, _ = metadata["namespace"].(string)
+	}
+	return []renderedResource{r}
+}
+
+// diffResource compares a rendered resource with the live object using
+// 'kubectl diff'. If kubectl diff is unavailable (e.g. server-side dry-run
+// is not permitted) it falls back to fetching the live object and comparing
+// the fields set in the rendered manifest.
+func diffResource(ctx context.Context, r *renderedResource, namespace, kubeContext string) {
+	var scope []string
+	if r.Namespace != "" {
+		namespace = r.Namespace
+	}
+	if namespace != "" {
+		scope = append(scope, "-n", namespace)
+	}
+	if kubeContext != "" {
+		scope = append(scope, "--context", kubeContext)
+	}
+
+	result := runnerFrom(ctx).Run(ctx, executor.Command{
+		Binary:  executor.KubectlBinary,
+		Args:    append(append([]string{"diff"}, scope...), "-f", "-"),
+		Stdin:   r.Manifest,
+		Timeout: commandTimeout("devspace_render"),
+	})
+
+	// kubectl diff exits 0 without differences and 1 when differences were found
+	switch {
+	case result.Error == "" && result.ExitCode == 0:
+		r.DiffState = diffUnchanged
+		return
+	case result.Error == "" && result.ExitCode == 1:
+		r.DiffState = diffChanged
+		r.Diff = strings.TrimSpace(result.Stdout)
+		return
+	}
+
+	live := runnerFrom(ctx).Run(ctx, executor.Command{
+		Binary:  executor.KubectlBinary,
+		Args:    append(append([]string{"get", r.kubectlType(), r.Name}, scope...), "-o", "yaml"),
+		Timeout: commandTimeout("devspace_render"),
+	})
+	if !live.Success() {
+		if containsIgnoreCase(live.Stderr, "NotFound") || containsIgnoreCase(live.Stderr, "not found") {
+			r.DiffState = diffNew
+			return
+		}
+		r.DiffState = diffUnknown
+		r.Diff = "kubectl diff failed: " + strings.TrimSpace(result.FormatOutput())
+		return
+	}
+
+	var liveObject map[string]any
+	if err := yaml.Unmarshal([]byte(live.Stdout), &liveObject); err != nil {
+		r.DiffState = diffUnknown
+		r.Diff = "cannot parse live object: " + err.Error()
+		return
+	}
+
+	rendered := r.object
+	if r.Kind == "Secret" {
+		rendered, liveObject = maskSecretValues(rendered, liveObject)
+	}
+
+	var differences []string
+	compareFields(rendered, liveObject, "", &differences)
+	if len(differences) == 0 {
+		r.DiffState = diffUnchanged
+		return
+	}
+	r.DiffState = diffChanged
+	r.Diff = "Fields that differ from the live object (kubectl diff unavailable):\n" + strings.Join(differences, "\n")
+}
+
+// compareFields records every field set in rendered whose value differs from
+// live. Fields only present in live (defaults, status, server-managed
+// metadata) are ignored.
+func compareFields(rendered, live any, path string, differences *[]string) {
+	switch want := rendered.(type) {
+	case map[string]any:
+		got, ok := live.(map[string]any)
+		if !ok {
+			*differences = append(*differences, fmt.Sprintf("%s: live value is %s", displayPath(path), describeValue(live)))
+			return
+		}
+		keys := make([]string, 0, len(want))
+		for key := range want {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := path + "." + key
+			if path == ".metadata" && key != "labels" && key != "annotations" {
+				continue
+			}
+			if _, exists := got[key]; !exists {
+				*differences = append(*differences, fmt.Sprintf("%s: missing in live object", displayPath(child)))
+				continue
+			}
+			compareFields(want[key], got[key], child, differences)
+		}
+	case []any:
+		got, ok := live.([]any)
+		if !ok || len(got) != len(want) {
+			*differences = append(*differences, fmt.Sprintf("%s: rendered %d items, live has %s", displayPath(path), len(want), describeValue(live)))
+			return
+		}
+		for i := range want {
+			compareFields(want[i], got[i], fmt.Sprintf("%s[%d]", path, i), differences)
+		}
+	default:
+		if fmt.Sprint(rendered) != fmt.Sprint(live) {
+			*differences = append(*differences, fmt.Sprintf("%s: rendered %v, live %v", displayPath(path), rendered, live))
+		}
+	}
+}
+
+// maskSecretValues returns copies of a rendered and a live Secret whose data
+// and stringData values are masked. Values that differ stay distinguishable
+// without being shown.
+func maskSecretValues(rendered, live map[string]any) (map[string]any, map[string]any) {
+	rendered, live = maps.Clone(rendered), maps.Clone(live)
+	for _, field := range secretDataFields {
+		want, _ := rendered[field].(map[string]any)
+		got, _ := live[field].(map[string]any)
+		maskedWant, maskedGot := make(map[string]any, len(want)), make(map[string]any, len(got))
+		for key, value := range want {
+			maskedWant[key] = secretMask
+			if other, ok := got[key]; ok && fmt.Sprint(other) != fmt.Sprint(value) {
+				maskedWant[key] = secretMask + " (rendered)"
+			}
+		}
+		for key, value := range got {
+			maskedGot[key] = secretMask
+			if other, ok := want[key]; ok && fmt.Sprint(other) != fmt.Sprint(value) {
+				maskedGot[key] = secretMask + " (live)"
+			}
+		}
+		if _, ok := rendered[field]; ok {
+			rendered[field] = maskedWant
+		}
+		if _, ok := live[field]; ok {
+			live[field] = maskedGot
+		}
+	}
+	return rendered, live
+}
+
+// maskedManifest returns the manifest of a resource for display, with the
+// values of Secrets masked
+func (r renderedResource) maskedManifest() string {
+	if r.Kind != "Secret" {
+		return r.Manifest
+	}
+	masked, _ := maskSecretValues(r.object, nil)
+	encoded, err := yaml.Marshal(masked)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// displayPath strips the leading dot from a field path
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return strings.TrimPrefix(path, ".")
+}
+
+// describeValue summarizes a live value for a type or length mismatch
+func describeValue(value any) string {
+	switch v := value.(type) {
+	case []any:
+		return fmt.Sprintf("%d items", len(v))
+	case map[string]any:
+		return "an object"
+	case nil:
+		return "empty"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// DevspaceRenderTool returns the tool definition for rendering manifests
+func DevspaceRenderTool() mcp.Tool {
+	return mcp.NewTool("devspace_render",
+		mcp.WithDescription("Render the Kubernetes manifests that a deploy would apply, without deploying, using 'devspace deploy --render' (or 'devspace run-pipeline <pipeline> --render'). Returns the manifests split per resource. With diff=true each resource is compared against the live cluster so you can see exactly what a devspace_deploy would change."),
+		mcp.WithOutputSchema[renderOutput](),
+		withCategory(serverconfig.CategoryMutate),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace to render for"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
+		mcp.WithString("profile",
+			mcp.Description("Profile to use"),
+		),
+		mcp.WithString("pipeline",
+			mcp.Description("Render through this pipeline instead of the deploy command"),
+		),
+		mcp.WithBoolean("skip_build",
+			mcp.Description("Skip building images (rendered image tags may then differ from a real deploy)"),
+		),
+		mcp.WithBoolean("diff",
+			mcp.Description("Compare each rendered resource with the live object in the cluster (default: false)"),
+		),
+		mcp.WithBoolean("show_manifests",
+			mcp.Description("Include the rendered manifests in the output (default: true)"),
+		),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+	)
+}
+
+// DevspaceRenderHandler handles rendering manifests and diffing them against the cluster
+func DevspaceRenderHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := []string{"deploy", "--render"}
+	if pipeline := req.GetString("pipeline", ""); pipeline != "" {
+		if err := ValidateCommandName(pipeline); err != nil {
+			return mcp.NewToolResultError(strings.Replace(err.Error(), "command name", "pipeline name", 1)), nil
+		}
+		args = []string{"run-pipeline", pipeline, "--render"}
+	}
+
+	namespace := req.GetString("namespace", "")
+	if namespace != "" {
+		if err := ValidateStringParam("namespace", namespace); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--namespace", namespace)
+	}
+	kubeContext := req.GetString("kube_context", "")
+	if kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--kube-context", kubeContext)
+	}
+	if profile := req.GetString("profile", ""); profile != "" {
+		if err := ValidateStringParam("profile", profile); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args = append(args, "--profile", profile)
+	}
+	if req.GetBool("skip_build", false) {
+		args = append(args, "--skip-build")
+	}
+
+	workingDir := req.GetString("working_dir", "")
+
+	// Rendering may build images first, use long running timeout
+	result := executeDevspace(ctx, longRunningTimeout("devspace_render"), workingDir, args...)
+
+	if !result.Success() {
+		return errorResult(req, result), nil
+	}
+
+	resources := splitManifests(result.Stdout)
+	if len(resources) == 0 {
+		return mcp.NewToolResultStructured(renderOutput{Resources: []renderedResource{}}, "No Kubernetes resources were rendered.\n\n## Output\n"+result.FormatOutput()), nil
+	}
+
+	diff := req.GetBool("diff", false)
+	if diff {
+		for i := range resources {
+			diffResource(ctx, &resources[i], namespace, kubeContext)
+		}
+	}
+
+	// Manifests were sent to kubectl diff as rendered; only the output masks
+	// Secret values
+	showManifests := req.GetBool("show_manifests", true)
+	for i := range resources {
+		resources[i].Manifest = resources[i].maskedManifest()
+		if !showManifests {
+			resources[i].Manifest = ""
+		}
+	}
+	text := formatRendered(resources, diff, showManifests)
+	return mcp.NewToolResultStructured(renderOutput{Resources: resources}, text), nil
+}
+
+// formatRendered renders the resource list, diffs and manifests as markdown
+func formatRendered(resources []renderedResource, diff, showManifests bool) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## Rendered Resources (%d)\n", len(resources))
+	counts := map[string]int{}
+	for _, r := range resources {
+		line := "- " + r.id()
+		if r.Namespace != "" {
+			line += " (namespace: " + r.Namespace + ")"
+		}
+		if diff {
+			line += " - " + r.DiffState
+			counts[r.DiffState]++
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if diff {
+		fmt.Fprintf(&b, "\n%d changed, %d new, %d unchanged", counts[diffChanged], counts[diffNew], counts[diffUnchanged])
+		if counts[diffUnknown] > 0 {
+			fmt.Fprintf(&b, ", %d could not be compared", counts[diffUnknown])
+		}
+		b.WriteString("\n")
+
+		for _, r := range resources {
+			if r.Diff == "" {
+				continue
+			}
+			fmt.Fprintf(&b, "\n## Diff: %s\n```diff\n%s\n```\n", r.id(), r.Diff)
+		}
+	}
+
+	if showManifests {
+		for _, r := range resources {
+			fmt.Fprintf(&b, "\n## Manifest: %s\n```yaml\n%s```\n", r.id(), r.Manifest)
+		}
+	}
+	return b.String()
+}
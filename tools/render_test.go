@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func TestSplitManifests(t *testing.T) {
+	resources := splitManifests(readFixture(t, "render_deploy.txt"))
+
+	var ids []string
+	for _, r := range resources {
+		ids = append(ids, r.id())
+	}
+	want := []string{"Service/api", "Deployment/api", "ConfigMap/api-config"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("resources = %v, want %v", ids, want)
+	}
+
+	if resources[1].Namespace != "dev" {
+		t.Errorf("Deployment namespace = %q, want dev", resources[1].Namespace)
+	}
+	if resources[1].kubectlType() != "Deployment.v1.apps" {
+		t.Errorf("kubectlType() = %q, want Deployment.v1.apps", resources[1].kubectlType())
+	}
+	if resources[0].kubectlType() != "Service" {
+		t.Errorf("kubectlType() = %q, want Service", resources[0].kubectlType())
+	}
+	if strings.Contains(resources[0].Manifest, "info Using") {
+		t.Errorf("log lines should be stripped from the manifest:\n%s", resources[0].Manifest)
+	}
+	if !strings.Contains(resources[2].Manifest, "LOG_LEVEL: debug") {
+		t.Errorf("List items should keep their content:\n%s", resources[2].Manifest)
+	}
+}
+
+func TestCompareFields(t *testing.T) {
+	rendered := map[string]any{
+		"metadata": map[string]any{"name": "api", "labels": map[string]any{"app": "api"}},
+		"spec":     map[string]any{"replicas": 2, "ports": []any{80}},
+	}
+	live := map[string]any{
+		"metadata": map[string]any{"name": "api", "uid": "123", "labels": map[string]any{"app": "api"}},
+		"spec":     map[string]any{"replicas": 3, "ports": []any{80}, "revisionHistoryLimit": 10},
+		"status":   map[string]any{"readyReplicas": 3},
+	}
+
+	var differences []string
+	compareFields(rendered, live, "", &differences)
+	want := []string{"spec.replicas: rendered 2, live 3"}
+	if !reflect.DeepEqual(differences, want) {
+		t.Errorf("differences = %v, want %v", differences, want)
+	}
+}
+
+func TestDevspaceRenderHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"deploy", "--render"}, executor.Result{Stdout: readFixture(t, "render_deploy.txt")})
+
+	result, err := DevspaceRenderHandler(ctx, newRequest(map[string]any{
+		"namespace":    "dev",
+		"kube_context": "kind-dev",
+		"profile":      "staging",
+		"skip_build":   true,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+
+	call := fake.Calls()[0]
+	want := []string{"deploy", "--render", "--namespace", "dev", "--kube-context", "kind-dev", "--profile", "staging", "--skip-build"}
+	if !reflect.DeepEqual(call.Args, want) {
+		t.Errorf("args = %v, want %v", call.Args, want)
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("kubectl should not be called without diff, got %d calls", len(fake.Calls()))
+	}
+
+	text := resultText(result)
+	for _, expected := range []string{"## Rendered Resources (3)", "- Deployment/api (namespace: dev)", "## Manifest: Service/api", "image: registry.example.com/api:dev-abc123"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, text)
+		}
+	}
+}
+
+func TestDevspaceRenderHandlerPipeline(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"run-pipeline"}, executor.Result{Stdout: readFixture(t, "render_deploy.txt")})
+
+	DevspaceRenderHandler(ctx, newRequest(map[string]any{"pipeline": "deploy-all"}))
+
+	if got := strings.Join(fake.Calls()[0].Args, " "); got != "run-pipeline deploy-all --render" {
+		t.Errorf("args = %q", got)
+	}
+}
+
+func TestDevspaceRenderHandlerDiff(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"deploy", "--render"}, executor.Result{Stdout: readFixture(t, "render_deploy.txt")})
+	// Deployment/api carries its own namespace, the other resources use the parameter
+	fake.On("kubectl", []string{"diff", "-n", "dev"}, executor.Result{
+		Stdout:   "--- /tmp/LIVE/apps.v1.Deployment.dev.api\n+++ /tmp/MERGED/apps.v1.Deployment.dev.api\n-  replicas: 1\n+  replicas: 2",
+		ExitCode: 1,
+	})
+	fake.On("kubectl", []string{"diff", "-n", "staging"}, executor.Result{Stderr: "error: forbidden", ExitCode: 2})
+	fake.On("kubectl", []string{"get", "Service", "api"}, executor.Result{Stdout: "apiVersion: v1\nkind: Service\nmetadata:\n  name: api\n  labels:\n    app: api\nspec:\n  ports:\n  - port: 80\n    targetPort: 8080\n  selector:\n    app: api\n"})
+	fake.On("kubectl", []string{"get", "ConfigMap"}, executor.Result{Stderr: `Error from server (NotFound): configmaps "api-config" not found`, ExitCode: 1})
+
+	result, _ := DevspaceRenderHandler(ctx, newRequest(map[string]any{
+		"namespace":      "staging",
+		"diff":           true,
+		"show_manifests": false,
+	}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+
+	text := resultText(result)
+	for _, expected := range []string{
+		"- Service/api - unchanged",
+		"- Deployment/api (namespace: dev) - changed",
+		"- ConfigMap/api-config - new",
+		"1 changed, 1 new, 1 unchanged",
+		"## Diff: Deployment/api",
+		"+  replicas: 2",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, "## Manifest:") {
+		t.Error("manifests should be omitted when show_manifests is false")
+	}
+
+	for _, call := range fake.Calls() {
+		if len(call.Args) > 0 && call.Args[0] == "diff" && !strings.Contains(call.Stdin, "kind:") {
+			t.Errorf("kubectl diff should receive the manifest on stdin, got %q", call.Stdin)
+		}
+	}
+}
+
+func TestDevspaceRenderHandlerMasksSecrets(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	rendered := `apiVersion: v1
+kind: Secret
+metadata:
+  name: api-credentials
+data:
+  DB_PASSWORD: cmVuZGVyZWQtcGFzc3dvcmQ=
+  API_KEY: c2FtZS1rZXk=
+stringData:
+  TOKEN: rendered-token
+`
+	fake.On("devspace", []string{"deploy", "--render"}, executor.Result{Stdout: rendered})
+	fake.On("kubectl", []string{"diff"}, executor.Result{Stderr: "error: forbidden", ExitCode: 2})
+	fake.On("kubectl", []string{"get", "Secret", "api-credentials"}, executor.Result{Stdout: `apiVersion: v1
+kind: Secret
+metadata:
+  name: api-credentials
+data:
+  DB_PASSWORD: bGl2ZS1wYXNzd29yZA==
+  API_KEY: c2FtZS1rZXk=
+  TOKEN: bGl2ZS10b2tlbg==
+`})
+
+	result, _ := DevspaceRenderHandler(ctx, newRequest(map[string]any{"diff": true}))
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+	text := resultText(result)
+	for _, secret := range []string{"cmVuZGVyZWQtcGFzc3dvcmQ=", "bGl2ZS1wYXNzd29yZA==", "c2FtZS1rZXk=", "rendered-token", "bGl2ZS10b2tlbg=="} {
+		if strings.Contains(text, secret) {
+			t.Errorf("output contains the secret value %s:\n%s", secret, text)
+		}
+	}
+	for _, expected := range []string{
+		"data.DB_PASSWORD: rendered *** (rendered), live *** (live)",
+		"stringData: missing in live object",
+		"## Manifest: Secret/api-credentials",
+		"DB_PASSWORD: '***'",
+	} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected %q in output:\n%s", expected, text)
+		}
+	}
+	if strings.Contains(text, "API_KEY: rendered") {
+		t.Errorf("equal values should not be reported:\n%s", text)
+	}
+	if manifest := result.StructuredContent.(renderOutput).Resources[0].Manifest; strings.Contains(manifest, "rendered-token") {
+		t.Errorf("structured manifest should be masked: %s", manifest)
+	}
+	for _, call := range fake.Calls() {
+		if call.Args[0] == "diff" && !strings.Contains(call.Stdin, "rendered-token") {
+			t.Error("kubectl diff should still receive the real manifest")
+		}
+	}
+}
+
+func TestDevspaceRenderHandlerErrors(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	result, _ := DevspaceRenderHandler(ctx, newRequest(map[string]any{"namespace": "--all"}))
+	if !result.IsError || len(fake.Calls()) != 0 {
+		t.Error("expected validation error without invoking devspace")
+	}
+
+	fake.On("devspace", []string{"deploy"}, executor.Result{Stderr: "error: token has expired", ExitCode: 1})
+	result, _ = DevspaceRenderHandler(ctx, newRequest(map[string]any{}))
+	if !result.IsError || !strings.Contains(resultText(result), "aws sso login") {
+		t.Errorf("expected enhanced error, got %s", resultText(result))
+	}
+}
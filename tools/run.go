@@ -4,7 +4,7 @@ import (
 	"context"
 	"strings"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -13,6 +13,8 @@ import (
 func DevspaceRunTool() mcp.Tool {
 	return mcp.NewTool("devspace_run",
 		mcp.WithDescription("Execute a predefined command from devspace.yaml"),
+		mcp.WithOutputSchema[commandOutput](),
+		withCategory(serverconfig.CategoryExec),
 		mcp.WithString("command",
 			mcp.Description("Name of the command to run (as defined in devspace.yaml)"),
 			mcp.Required(),
@@ -21,7 +23,7 @@ func DevspaceRunTool() mcp.Tool {
 			mcp.Description("Arguments to pass to the command (space-separated)"),
 		),
 		mcp.WithString("working_dir",
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 	)
 }
@@ -53,11 +55,11 @@ func DevspaceRunHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 		}
 	}
 
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
+	result := executeDevspace(ctx, commandTimeout("devspace_run"), workingDir, args...)
 
 	if !result.Success() {
 		return mcp.NewToolResultError(result.FormatOutput()), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	return mcp.NewToolResultStructured(newCommandOutput(args, result), result.FormatOutput()), nil
 }
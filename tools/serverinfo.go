@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolStatus describes whether a tool was registered
+type toolStatus struct {
+	Name       string `json:"name"`
+	Category   string `json:"category" jsonschema:"enum=read,enum=mutate,enum=destructive,enum=exec"`
+	Registered bool   `json:"registered"`
+}
+
+// toolStatuses records every tool RegisterAll considered, in registration order
+var toolStatuses []toolStatus
+
+// policyRuleInfo is a protected context rule without its confirmation token
+type policyRuleInfo struct {
+	Name         string   `json:"name"`
+	KubeContexts []string `json:"kube_contexts,omitempty"`
+	Namespaces   []string `json:"namespaces,omitempty"`
+	Deny         []string `json:"deny,omitempty"`
+	Confirm      []string `json:"confirm,omitempty"`
+}
+
+// serverInfoOutput is the result of devspace_server_info
+type serverInfoOutput struct {
+	ReadOnly       bool                  `json:"read_only"`
+	Categories     []string              `json:"categories" jsonschema:"description=Tool categories being registered; empty when tools are not filtered by category"`
+	EnabledTools   []string              `json:"enabled_tools"`
+	DisabledTools  []string              `json:"disabled_tools"`
+	Tools          []toolStatus          `json:"tools"`
+	PolicyRules    []policyRuleInfo      `json:"policy_rules"`
+	RedactPatterns int                   `json:"redact_patterns" jsonschema:"description=Number of user patterns masked in addition to the built-in token formats"`
+	Binaries       serverconfig.Binaries `json:"binaries"`
+	Timeouts       map[string]string     `json:"timeouts"`
+	Defaults       serverconfig.Defaults `json:"defaults"`
+	MaxOutputBytes int                   `json:"max_output_bytes"`
+}
+
+// DevspaceServerInfoTool returns the tool definition for reporting the server configuration
+func DevspaceServerInfoTool() mcp.Tool {
+	return mcp.NewTool("devspace_server_info",
+		mcp.WithDescription("Show which tools this server exposes and the active policy: read-only mode, tool categories, allow/deny lists, protected contexts, timeouts and defaults"),
+		mcp.WithOutputSchema[serverInfoOutput](),
+		withCategory(serverconfig.CategoryRead),
+	)
+}
+
+// DevspaceServerInfoHandler handles the server info request
+func DevspaceServerInfoHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	c := serverConfig
+	info := serverInfoOutput{
+		ReadOnly:       c.Tools.ReadOnly,
+		Categories:     nonNil(c.Tools.AllowedCategories()),
+		EnabledTools:   nonNil(c.Tools.Enable),
+		DisabledTools:  nonNil(c.Tools.Disable),
+		Tools:          append([]toolStatus{}, toolStatuses...),
+		PolicyRules:    []policyRuleInfo{},
+		RedactPatterns: len(activeRedactor.patterns) - len(builtinRedactPatterns),
+		Binaries:       c.Binaries,
+		Timeouts: map[string]string{
+			"default":      c.Timeouts.Default.String(),
+			"long_running": c.Timeouts.LongRunning.String(),
+		},
+		Defaults:       c.Defaults,
+		MaxOutputBytes: c.MaxOutputBytes,
+	}
+	for tool, timeout := range c.Timeouts.Tools {
+		info.Timeouts[tool] = timeout.String()
+	}
+	if activePolicy != nil {
+		for _, r := range activePolicy.Rules {
+			info.PolicyRules = append(info.PolicyRules, policyRuleInfo{
+				Name:         r.Name,
+				KubeContexts: r.KubeContexts,
+				Namespaces:   r.Namespaces,
+				Deny:         r.Deny,
+				Confirm:      r.Confirm,
+			})
+		}
+	}
+
+	return mcp.NewToolResultStructured(info, formatServerInfo(info)), nil
+}
+
+// formatServerInfo renders the server info as text
+func formatServerInfo(info serverInfoOutput) string {
+	var b strings.Builder
+	b.WriteString("# DevSpace MCP Server\n\n")
+
+	b.WriteString("## Tool Selection\n")
+	mode := "all categories"
+	if info.ReadOnly {
+		mode = "read-only"
+	} else if len(info.Categories) > 0 {
+		mode = "categories: " + strings.Join(info.Categories, ", ")
+	}
+	fmt.Fprintf(&b, "Mode: %s\n", mode)
+	if len(info.EnabledTools) > 0 {
+		fmt.Fprintf(&b, "Enabled: %s\n", strings.Join(info.EnabledTools, ", "))
+	}
+	if len(info.DisabledTools) > 0 {
+		fmt.Fprintf(&b, "Disabled: %s\n", strings.Join(info.DisabledTools, ", "))
+	}
+	b.WriteString("\n")
+	for _, t := range info.Tools {
+		mark := "✅"
+		if !t.Registered {
+			mark = "🚫"
+		}
+		fmt.Fprintf(&b, "%s %s (%s)\n", mark, t.Name, t.Category)
+	}
+
+	b.WriteString("\n## Protected Contexts\n")
+	if len(info.PolicyRules) == 0 {
+		b.WriteString("No policy loaded\n")
+	}
+	for _, r := range info.PolicyRules {
+		fmt.Fprintf(&b, "- %s: contexts %s, namespaces %s", r.Name, patternList(r.KubeContexts), patternList(r.Namespaces))
+		if len(r.Deny) > 0 {
+			fmt.Fprintf(&b, ", deny %s", strings.Join(r.Deny, ", "))
+		}
+		if len(r.Confirm) > 0 {
+			fmt.Fprintf(&b, ", confirm %s", strings.Join(r.Confirm, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n## Timeouts\n")
+	names := make([]string, 0, len(info.Timeouts))
+	for name := range info.Timeouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s: %s\n", name, info.Timeouts[name])
+	}
+
+	b.WriteString("\n## Defaults\n")
+	for _, d := range [][2]string{
+		{"working_dir", info.Defaults.WorkingDir},
+		{"namespace", info.Defaults.Namespace},
+		{"kube_context", info.Defaults.KubeContext},
+		{"profile", info.Defaults.Profile},
+	} {
+		if d[1] != "" {
+			fmt.Fprintf(&b, "%s: %s\n", d[0], d[1])
+		}
+	}
+	fmt.Fprintf(&b, "devspace binary: %s\nkubectl binary: %s\n", info.Binaries.Devspace, info.Binaries.Kubectl)
+	if info.MaxOutputBytes > 0 {
+		fmt.Fprintf(&b, "max output: %d bytes\n", info.MaxOutputBytes)
+	}
+	if info.RedactPatterns > 0 {
+		fmt.Fprintf(&b, "custom redact patterns: %d\n", info.RedactPatterns)
+	}
+	return b.String()
+}
+
+// patternList renders glob patterns, where an empty list matches anything
+func patternList(patterns []string) string {
+	if len(patterns) == 0 {
+		return "*"
+	}
+	return strings.Join(patterns, ", ")
+}
+
+// nonNil returns an empty slice instead of nil so it is encoded as []
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
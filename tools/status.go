@@ -6,7 +6,7 @@ import (
 	"strings"
 	"time"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -15,13 +15,17 @@ import (
 func DevspaceStatusTool() mcp.Tool {
 	return mcp.NewTool("devspace_status",
 		mcp.WithDescription("Get comprehensive DevSpace environment health status. Aggregates information from multiple sources including devspace.yaml validation, deployments, analysis, sync paths, and port forwards."),
+		mcp.WithOutputSchema[statusOutput](),
+		withCategory(serverconfig.CategoryRead),
 		mcp.WithString("working_dir",
-			mcp.Required(),
-			mcp.Description("Working directory containing devspace.yaml"),
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
 		),
 		mcp.WithString("namespace",
 			mcp.Description("Kubernetes namespace to check"),
 		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
 	)
 }
 
@@ -29,31 +33,49 @@ func DevspaceStatusTool() mcp.Tool {
 func DevspaceStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	workingDir := req.GetString("working_dir", "")
 	if workingDir == "" {
-		return mcp.NewToolResultError("working_dir parameter is required"), nil
+		return mcp.NewToolResultError("working_dir parameter is required; pass it or set it with devspace_set_context"), nil
 	}
 
 	namespace := req.GetString("namespace", "")
+	// scope targets the cluster checks at the namespace and kube context
+	var scope []string
+	if namespace != "" {
+		scope = append(scope, "--namespace", namespace)
+	}
+	if kubeContext := req.GetString("kube_context", ""); kubeContext != "" {
+		if err := ValidateStringParam("kube_context", kubeContext); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		scope = append(scope, "--kube-context", kubeContext)
+	}
+	// Every check is bounded by the tool timeout
+	timeout := commandTimeout("devspace_status")
 
 	var status strings.Builder
 	status.WriteString("# DevSpace Environment Status\n\n")
+	structured := statusOutput{
+		Deployments: []deploymentInfo{},
+		SyncPaths:   []syncPathInfo{},
+		Ports:       []portInfo{},
+	}
 
 	// 1. Check devspace.yaml exists
 	status.WriteString("## Configuration\n")
-	if err := ValidateDevspaceYaml(workingDir); err != nil {
+	if _, err := LocateDevspaceConfig(workingDir, req.GetString("config", "")); err != nil {
 		status.WriteString("❌ " + err.Error() + "\n")
 		// If no devspace.yaml, can't continue with other checks
-		return mcp.NewToolResultText(status.String()), nil
+		structured.Warnings = append(structured.Warnings, err.Error())
+		return mcp.NewToolResultStructured(structured, status.String()), nil
 	}
+	structured.ConfigFound = true
 	status.WriteString("✅ devspace.yaml found\n\n")
 
 	// 2. Get deployments status
 	status.WriteString("## Deployments\n")
-	args := []string{"list", "deployments"}
-	if namespace != "" {
-		args = append(args, "--namespace", namespace)
-	}
-	result := executor.ExecuteInDir(ctx, workingDir, args...)
+	args := append([]string{"list", "deployments"}, scope...)
+	result := executeDevspace(ctx, timeout, workingDir, args...)
 	if result.Success() {
+		structured.Deployments = parseDeployments(result.Stdout)
 		output := strings.TrimSpace(result.Stdout)
 		if output != "" {
 			status.WriteString(output + "\n")
@@ -61,33 +83,34 @@ func DevspaceStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.C
 			status.WriteString("No deployments found\n")
 		}
 	} else {
+		structured.Warnings = append(structured.Warnings, "Could not fetch deployments: "+result.Stderr)
 		status.WriteString(fmt.Sprintf("⚠️  Could not fetch deployments: %s\n", result.Stderr))
 	}
 	status.WriteString("\n")
 
 	// 3. Run analyze
 	status.WriteString("## Analysis\n")
-	args = []string{"analyze", "--timeout=30"}
-	if namespace != "" {
-		args = append(args, "--namespace", namespace)
-	}
-	result = executor.ExecuteWithOptions(ctx, 35*time.Second, workingDir, args...)
+	args = append([]string{"analyze", fmt.Sprintf("--timeout=%d", analyzeWait(timeout))}, scope...)
+	result = executeDevspace(ctx, timeout, workingDir, args...)
 	if result.Success() {
 		output := strings.TrimSpace(result.Stdout)
+		structured.Analysis = output
 		if output == "" {
 			status.WriteString("✅ No issues detected\n")
 		} else {
 			status.WriteString(output + "\n")
 		}
 	} else {
+		structured.Warnings = append(structured.Warnings, "Analysis failed: "+result.Stderr)
 		status.WriteString(fmt.Sprintf("⚠️  Analysis failed: %s\n", result.Stderr))
 	}
 	status.WriteString("\n")
 
 	// 4. List configured sync paths
 	status.WriteString("## Configured Sync Paths\n")
-	result = executor.ExecuteInDir(ctx, workingDir, "list", "sync")
+	result = executeDevspace(ctx, timeout, workingDir, "list", "sync")
 	if result.Success() {
+		structured.SyncPaths = parseSyncPaths(result.Stdout)
 		output := strings.TrimSpace(result.Stdout)
 		if output != "" {
 			status.WriteString(output + "\n")
@@ -95,14 +118,16 @@ func DevspaceStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.C
 			status.WriteString("No sync paths configured\n")
 		}
 	} else {
+		structured.Warnings = append(structured.Warnings, "Could not fetch sync paths: "+result.Stderr)
 		status.WriteString(fmt.Sprintf("⚠️  Could not fetch sync paths: %s\n", result.Stderr))
 	}
 	status.WriteString("\n")
 
 	// 5. List configured ports
 	status.WriteString("## Configured Port Forwards\n")
-	result = executor.ExecuteInDir(ctx, workingDir, "list", "ports")
+	result = executeDevspace(ctx, timeout, workingDir, "list", "ports")
 	if result.Success() {
+		structured.Ports = parsePorts(result.Stdout)
 		output := strings.TrimSpace(result.Stdout)
 		if output != "" {
 			status.WriteString(output + "\n")
@@ -110,8 +135,19 @@ func DevspaceStatusHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.C
 			status.WriteString("No port forwards configured\n")
 		}
 	} else {
+		structured.Warnings = append(structured.Warnings, "Could not fetch port forwards: "+result.Stderr)
 		status.WriteString(fmt.Sprintf("⚠️  Could not fetch port forwards: %s\n", result.Stderr))
 	}
 
-	return mcp.NewToolResultText(status.String()), nil
+	return mcp.NewToolResultStructured(structured, status.String()), nil
+}
+
+// analyzeWait returns how many seconds analyze may wait for resources so
+// that it finishes within timeout
+func analyzeWait(timeout time.Duration) int {
+	wait := int((timeout - 5*time.Second) / time.Second)
+	if wait < 1 {
+		wait = 1
+	}
+	return wait
 }
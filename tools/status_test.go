@@ -1,7 +1,12 @@
 package tools
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"devspace-mcp/executor"
 )
 
 func TestDevspaceStatusTool(t *testing.T) {
@@ -17,15 +22,72 @@ func TestDevspaceStatusTool(t *testing.T) {
 		t.Error("tool description should not be empty")
 	}
 
-	// Verify working_dir is in required parameters
-	isRequired := false
+	// Verify working_dir is accepted but optional, as it may come from the
+	// session context
+	if _, ok := tool.InputSchema.Properties["working_dir"]; !ok {
+		t.Error("tool should accept a working_dir parameter")
+	}
 	for _, req := range tool.InputSchema.Required {
 		if req == "working_dir" {
-			isRequired = true
-			break
+			t.Error("working_dir parameter should not be required")
 		}
 	}
-	if !isRequired {
-		t.Error("working_dir parameter should be required")
+}
+
+func TestDevspaceStatusHandler(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "devspace.yaml"), []byte("version: v2beta1"), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"list", "deployments"}, executor.Result{Stdout: "NAME  TYPE  DEPLOY\napi   helm  true"}).
+		On("devspace", []string{"analyze"}, executor.Result{}).
+		On("devspace", []string{"list", "sync"}, executor.Result{Stderr: "no dev config", ExitCode: 1}).
+		On("devspace", []string{"list", "ports"}, executor.Result{Stdout: "8080:80"})
+
+	result, err := DevspaceStatusHandler(ctx, newRequest(map[string]any{
+		"working_dir": dir,
+		"namespace":   "dev",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := resultText(result)
+	for _, want := range []string{
+		"✅ devspace.yaml found",
+		"api   helm  true",
+		"✅ No issues detected",
+		"⚠️  Could not fetch sync paths: no dev config",
+		"8080:80",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("status output should contain %q, got:\n%s", want, text)
+		}
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("expected 4 invocations, got %d", len(calls))
+	}
+	for _, c := range calls {
+		if c.Dir != dir {
+			t.Errorf("%s ran in %q, want %q", c, c.Dir, dir)
+		}
+	}
+}
+
+func TestDevspaceStatusHandlerMissingConfig(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+
+	result, _ := DevspaceStatusHandler(ctx, newRequest(map[string]any{
+		"working_dir": t.TempDir(),
+	}))
+	if !strings.Contains(resultText(result), "❌") {
+		t.Errorf("expected missing config marker, got %q", resultText(result))
+	}
+	if len(fake.Calls()) != 0 {
+		t.Error("no commands should run without a devspace.yaml")
 	}
 }
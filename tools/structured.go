@@ -0,0 +1,158 @@
+package tools
+
+import (
+	"regexp"
+	"strings"
+
+	"devspace-mcp/executor"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Structured results returned next to the text content. Every tool declares
+// one of these types as its output schema via mcp.WithOutputSchema.
+
+// commandOutput is the result of tools that run a single CLI command
+type commandOutput struct {
+	Command  string `json:"command" jsonschema:"description=The executed command line"`
+	ExitCode int    `json:"exit_code"`
+	Output   string `json:"output" jsonschema:"description=Combined stdout and stderr"`
+}
+
+// newCommandOutput describes a devspace invocation and its result
+func newCommandOutput(args []string, result executor.Result) commandOutput {
+	return commandOutput{
+		Command:  executor.Command{Binary: executor.DevspaceBinary, Args: args}.String(),
+		ExitCode: result.ExitCode,
+		Output:   result.FormatOutput(),
+	}
+}
+
+// versionPattern matches a semantic version in 'devspace version' output
+var versionPattern = regexp.MustCompile(`v?\d+\.\d+\.\d+[\w.+-]*`)
+
+// versionOutput is the result of devspace_version
+type versionOutput struct {
+	Version string `json:"version"`
+	Output  string `json:"output"`
+}
+
+// namespacesOutput is the result of devspace_list_namespaces
+type namespacesOutput struct {
+	Namespaces []namespaceInfo `json:"namespaces"`
+}
+
+// contextsOutput is the result of devspace_list_contexts
+type contextsOutput struct {
+	Contexts []contextInfo `json:"contexts"`
+}
+
+// deploymentsOutput is the result of devspace_list_deployments
+type deploymentsOutput struct {
+	Deployments []deploymentInfo `json:"deployments"`
+}
+
+// profilesOutput is the result of devspace_list_profiles
+type profilesOutput struct {
+	Profiles []profileInfo `json:"profiles"`
+	Source   string        `json:"source" jsonschema:"description=cli when the devspace CLI listed the profiles or native when devspace.yaml was parsed locally"`
+}
+
+// varsOutput is the result of devspace_list_vars
+type varsOutput struct {
+	Vars   []varInfo `json:"vars"`
+	Source string    `json:"source" jsonschema:"description=cli when the devspace CLI resolved the variables or native when devspace.yaml was parsed locally"`
+}
+
+// podsOutput is the result of devspace_list_pods
+type podsOutput struct {
+	Pods []podInfo `json:"pods"`
+}
+
+// portsOutput is the result of devspace_list_ports
+type portsOutput struct {
+	Ports          []portInfo        `json:"ports"`
+	ActiveForwards []portForwardInfo `json:"active_forwards"`
+}
+
+// printOutput is the result of devspace_print
+type printOutput struct {
+	Config map[string]any `json:"config,omitempty" jsonschema:"description=The resolved configuration if it could be parsed"`
+	Output string         `json:"output"`
+	Source string         `json:"source" jsonschema:"description=cli when the devspace CLI printed the configuration or native when devspace.yaml was parsed locally"`
+}
+
+// parsePrintedConfig extracts the configuration from 'devspace print'
+// output, skipping the variable table printed before it unless skip_info is set
+func parsePrintedConfig(output string) map[string]any {
+	var config map[string]any
+	if err := yaml.Unmarshal([]byte(output), &config); err == nil && config["version"] != nil {
+		return config
+	}
+	if i := strings.Index(output, "\nversion:"); i >= 0 {
+		config = nil
+		if err := yaml.Unmarshal([]byte(output[i+1:]), &config); err == nil {
+			return config
+		}
+	}
+	return nil
+}
+
+// logsOutput is the result of devspace_logs
+type logsOutput struct {
+	Lines   []string    `json:"lines"`
+	Count   int         `json:"count"`
+	Backend string      `json:"backend" jsonschema:"description=Command that served the request: devspace or kubectl"`
+	Sources []logSource `json:"sources,omitempty" jsonschema:"description=Containers of an aggregated request"`
+}
+
+// splitLines splits output into lines, dropping a trailing empty line
+func splitLines(output string) []string {
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		return []string{}
+	}
+	return strings.Split(output, "\n")
+}
+
+// execOutput is the result of devspace_exec
+type execOutput struct {
+	Command  string `json:"command"`
+	ExitCode int    `json:"exit_code"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+}
+
+// statusOutput is the result of devspace_status
+type statusOutput struct {
+	ConfigFound bool             `json:"config_found"`
+	Deployments []deploymentInfo `json:"deployments"`
+	Analysis    string           `json:"analysis" jsonschema:"description=Output of devspace analyze; empty when no issues were detected"`
+	SyncPaths   []syncPathInfo   `json:"sync_paths"`
+	Ports       []portInfo       `json:"ports"`
+	Warnings    []string         `json:"warnings,omitempty" jsonschema:"description=Checks that could not be completed"`
+}
+
+// renderOutput is the result of devspace_render
+type renderOutput struct {
+	Resources []renderedResource `json:"resources"`
+}
+
+// devSessionsOutput is the result of devspace_dev_status
+type devSessionsOutput struct {
+	Sessions []devSessionInfo `json:"sessions"`
+}
+
+// devOutputResult is the result of devspace_dev_output
+type devOutputResult struct {
+	SessionID  string          `json:"session_id"`
+	State      string          `json:"state"`
+	NextCursor int64           `json:"next_cursor"`
+	Dropped    int64           `json:"dropped" jsonschema:"description=Lines discarded from the buffer before the requested cursor"`
+	Lines      []executor.Line `json:"lines"`
+}
+
+// portForwardsOutput is the result of devspace_port_forward_list
+type portForwardsOutput struct {
+	Forwards []portForwardInfo `json:"forwards"`
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func TestParsePrintedConfig(t *testing.T) {
+	withInfo := "-------------------\n\nVars:\n\n NAME    VALUE\n IMAGE   api\n\n-------------------\n\nLoaded path: devspace.yaml\n\n-------------------\n\nversion: v2beta1\nname: api\ndeployments:\n  api:\n    helm: {}\n"
+
+	config := parsePrintedConfig(withInfo)
+	if config["name"] != "api" {
+		t.Errorf("expected config name 'api', got %v", config)
+	}
+	if _, ok := config["deployments"].(map[string]any); !ok {
+		t.Errorf("expected deployments section, got %v", config["deployments"])
+	}
+
+	if config := parsePrintedConfig("version: v2beta1\nname: web\n"); config["name"] != "web" {
+		t.Errorf("expected skip_info output to parse, got %v", config)
+	}
+	if config := parsePrintedConfig("fatal: unable to load config"); config != nil {
+		t.Errorf("expected nil for unparsable output, got %v", config)
+	}
+}
+
+func TestNewCommandOutput(t *testing.T) {
+	out := newCommandOutput([]string{"deploy", "--namespace", "dev"}, executor.Result{Stdout: "done", Stderr: "warn", ExitCode: 0})
+
+	if out.Command != "devspace deploy --namespace dev" {
+		t.Errorf("Command = %q", out.Command)
+	}
+	if out.Output != "done\nwarn" {
+		t.Errorf("Output = %q", out.Output)
+	}
+}
@@ -0,0 +1,248 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// syncInitialStrategies are the values accepted by --initial-sync
+var syncInitialStrategies = []string{"mirrorLocal", "mirrorRemote", "preferLocal", "preferRemote", "preferNewest", "keepAll"}
+
+// maxSyncFilesListed caps the number of file names reported per direction
+const maxSyncFilesListed = 50
+
+var (
+	syncUploadFilePattern   = regexp.MustCompile(`(?i)upstream - upload file '([^']+)'`)
+	syncDownloadFilePattern = regexp.MustCompile(`(?i)downstream - download file '([^']+)'`)
+	syncUploadCountPattern  = regexp.MustCompile(`(?i)upstream - upload (\d+) create change`)
+	syncDownloadPattern     = regexp.MustCompile(`(?i)downstream - download (\d+) create change`)
+	syncDeletePattern       = regexp.MustCompile(`(?i)(upstream - (?:delete|remove)|downstream - (?:delete|remove)) '([^']+)'`)
+	syncSkipPattern         = regexp.MustCompile(`(?i)\bskip(?:ping|ped)?\b[^']*'([^']+)'`)
+)
+
+// syncSummary is the structured result of a one-shot sync
+type syncSummary struct {
+	Uploaded        int      `json:"uploaded"`
+	Downloaded      int      `json:"downloaded"`
+	Deleted         int      `json:"deleted"`
+	Skipped         int      `json:"skipped"`
+	UploadedFiles   []string `json:"uploaded_files,omitempty"`
+	DownloadedFiles []string `json:"downloaded_files,omitempty"`
+	DeletedFiles    []string `json:"deleted_files,omitempty"`
+	SkippedFiles    []string `json:"skipped_files,omitempty"`
+}
+
+// parseSyncSummary extracts file counts from devspace sync output. devspace
+// reports batches ("Upload 3 create change(s)") and, depending on the log
+// level, individual files; per-file lines take precedence over batch counts.
+func parseSyncSummary(output string) syncSummary {
+	var s syncSummary
+	uploadBatches, downloadBatches := 0, 0
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := syncUploadFilePattern.FindStringSubmatch(line); m != nil {
+			s.UploadedFiles = appendCapped(s.UploadedFiles, m[1])
+			s.Uploaded++
+		} else if m := syncDownloadFilePattern.FindStringSubmatch(line); m != nil {
+			s.DownloadedFiles = appendCapped(s.DownloadedFiles, m[1])
+			s.Downloaded++
+		} else if m := syncUploadCountPattern.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			uploadBatches += n
+		} else if m := syncDownloadPattern.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			downloadBatches += n
+		} else if m := syncDeletePattern.FindStringSubmatch(line); m != nil {
+			s.DeletedFiles = appendCapped(s.DeletedFiles, m[2])
+			s.Deleted++
+		} else if m := syncSkipPattern.FindStringSubmatch(line); m != nil {
+			s.SkippedFiles = appendCapped(s.SkippedFiles, m[1])
+			s.Skipped++
+		}
+	}
+
+	if s.Uploaded == 0 {
+		s.Uploaded = uploadBatches
+	}
+	if s.Downloaded == 0 {
+		s.Downloaded = downloadBatches
+	}
+	return s
+}
+
+// appendCapped appends name unless the list already holds maxSyncFilesListed entries
+func appendCapped(list []string, name string) []string {
+	if len(list) >= maxSyncFilesListed {
+		return list
+	}
+	return append(list, name)
+}
+
+// format renders the summary as a markdown section
+func (s syncSummary) format() string {
+	var b strings.Builder
+	b.WriteString("## Sync Summary\n")
+	fmt.Fprintf(&b, "Uploaded: %d\n", s.Uploaded)
+	fmt.Fprintf(&b, "Downloaded: %d\n", s.Downloaded)
+	fmt.Fprintf(&b, "Deleted: %d\n", s.Deleted)
+	fmt.Fprintf(&b, "Skipped: %d\n", s.Skipped)
+
+	for _, section := range []struct {
+		title string
+		files []string
+		total int
+	}{
+		{"Uploaded files", s.UploadedFiles, s.Uploaded},
+		{"Downloaded files", s.DownloadedFiles, s.Downloaded},
+		{"Deleted files", s.DeletedFiles, s.Deleted},
+		{"Skipped files", s.SkippedFiles, s.Skipped},
+	} {
+		if len(section.files) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n", section.title)
+		for _, f := range section.files {
+			b.WriteString("- " + f + "\n")
+		}
+		if section.total > len(section.files) {
+			fmt.Fprintf(&b, "- ... and %d more\n", section.total-len(section.files))
+		}
+	}
+	return b.String()
+}
+
+// DevspaceSyncTool returns the tool definition for a one-shot file sync
+func DevspaceSyncTool() mcp.Tool {
+	return mcp.NewTool("devspace_sync",
+		mcp.WithDescription("Run a single file synchronization between a local path and a container path using 'devspace sync --no-watch', then exit. Useful for pushing a hot-fix into a running container without starting a dev session. Reports how many files were uploaded, downloaded, deleted and skipped."),
+		mcp.WithOutputSchema[syncSummary](),
+		withCategory(serverconfig.CategoryMutate),
+		mcp.WithString("local_path",
+			mcp.Required(),
+			mcp.Description("Local path to sync, relative to working_dir (e.g., './src')"),
+		),
+		mcp.WithString("container_path",
+			mcp.Required(),
+			mcp.Description("Path inside the container (e.g., '/app/src')"),
+		),
+		mcp.WithBoolean("upload_only",
+			mcp.Description("Only upload local changes to the container"),
+		),
+		mcp.WithBoolean("download_only",
+			mcp.Description("Only download changes from the container"),
+		),
+		mcp.WithString("initial_sync",
+			mcp.Description("Initial sync strategy: mirrorLocal, mirrorRemote, preferLocal, preferRemote, preferNewest, or keepAll"),
+		),
+		mcp.WithString("exclude",
+			mcp.Description("Comma-separated paths to exclude (e.g., 'node_modules,.git')"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Kubernetes namespace"),
+		),
+		mcp.WithString("kube_context",
+			mcp.Description("Kubernetes context to use"),
+		),
+		mcp.WithString("pod",
+			mcp.Description("Specific pod name to sync to"),
+		),
+		mcp.WithString("container",
+			mcp.Description("Container name within the pod"),
+		),
+		mcp.WithString("label_selector",
+			mcp.Description("Label selector to filter pods (e.g., 'app=myapp')"),
+		),
+		mcp.WithString("image_selector",
+			mcp.Description("Image selector to filter by container image (e.g., 'nginx:latest')"),
+		),
+		mcp.WithString("working_dir",
+			mcp.Description("Project directory or a subdirectory of it; devspace.yaml is searched upwards from here"),
+		),
+	)
+}
+
+// DevspaceSyncHandler handles the one-shot sync command
+func DevspaceSyncHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	localPath := req.GetString("local_path", "")
+	containerPath := req.GetString("container_path", "")
+	if localPath == "" || containerPath == "" {
+		return mcp.NewToolResultError("local_path and container_path parameters are required"), nil
+	}
+	if err := ValidateStringParam("local_path", localPath); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if err := ValidateStringParam("container_path", containerPath); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if strings.Contains(localPath, ":") {
+		return mcp.NewToolResultError("invalid local_path: value cannot contain ':'"), nil
+	}
+
+	args := []string{"sync", "--no-watch", "--pick=false", "--path", localPath + ":" + containerPath}
+
+	uploadOnly := req.GetBool("upload_only", false)
+	downloadOnly := req.GetBool("download_only", false)
+	if uploadOnly && downloadOnly {
+		return mcp.NewToolResultError("upload_only and download_only cannot both be set"), nil
+	}
+	if uploadOnly {
+		args = append(args, "--upload-only")
+	}
+	if downloadOnly {
+		args = append(args, "--download-only")
+	}
+
+	if strategy := req.GetString("initial_sync", ""); strategy != "" {
+		valid := false
+		for _, s := range syncInitialStrategies {
+			if s == strategy {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid initial_sync %q: must be one of %s", strategy, strings.Join(syncInitialStrategies, ", "))), nil
+		}
+		args = append(args, "--initial-sync", strategy)
+	}
+
+	var err error
+	if args, err = appendListFlag(args, "exclude", "--exclude", req.GetString("exclude", "")); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	for _, p := range []struct{ param, flag string }{
+		{"namespace", "--namespace"},
+		{"kube_context", "--kube-context"},
+		{"pod", "--pod"},
+		{"container", "--container"},
+		{"label_selector", "--label-selector"},
+		{"image_selector", "--image-selector"},
+	} {
+		if value := req.GetString(p.param, ""); value != "" {
+			if err := ValidateStringParam(p.param, value); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			args = append(args, p.flag, value)
+		}
+	}
+
+	workingDir := req.GetString("working_dir", "")
+
+	// Initial sync of large trees can take a while
+	result := executeDevspaceStreaming(ctx, req, longRunningTimeout("devspace_sync"), workingDir, args...)
+
+	if !result.Success() {
+		return errorResult(req, result), nil
+	}
+
+	summary := parseSyncSummary(result.Stdout + "\n" + result.Stderr)
+	return mcp.NewToolResultStructured(summary, summary.format()+"\n## Output\n"+result.FormatOutput()), nil
+}
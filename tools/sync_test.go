@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func TestParseSyncSummary(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    syncSummary
+	}{
+		{
+			fixture: "sync_no_watch.txt",
+			want: syncSummary{
+				Uploaded:        2,
+				Downloaded:      1,
+				Deleted:         1,
+				Skipped:         1,
+				UploadedFiles:   []string{"main.go", "handlers/users.go"},
+				DownloadedFiles: []string{"./generated/schema.graphql"},
+				DeletedFiles:    []string{"old_handler.go"},
+				SkippedFiles:    []string{"tmp/cache.bin"},
+			},
+		},
+		{
+			fixture: "sync_batches_only.txt",
+			want:    syncSummary{Uploaded: 14, Downloaded: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := parseSyncSummary(readFixture(t, tt.fixture))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSyncSummary() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDevspaceSyncHandler(t *testing.T) {
+	fake, ctx := useFakeRunner(t)
+	fake.On("devspace", []string{"sync"}, executor.Result{Stdout: readFixture(t, "sync_no_watch.txt")})
+
+	result, err := DevspaceSyncHandler(ctx, newRequest(map[string]any{
+		"local_path":     "./src",
+		"container_path": "/app/src",
+		"upload_only":    true,
+		"initial_sync":   "preferLocal",
+		"exclude":        "node_modules, .git",
+		"namespace":      "dev",
+		"label_selector": "app=api",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", resultText(result))
+	}
+
+	want := []string{"sync", "--no-watch", "--pick=false", "--path", "./src:/app/src", "--upload-only",
+		"--initial-sync", "preferLocal", "--exclude", "node_modules", "--exclude", ".git",
+		"--namespace", "dev", "--label-selector", "app=api"}
+	if got := fake.Calls()[0].Args; !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %v, want %v", got, want)
+	}
+
+	text := resultText(result)
+	for _, s := range []string{"Uploaded: 2", "Downloaded: 1", "- handlers/users.go", "## Output"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("output should contain %q, got:\n%s", s, text)
+		}
+	}
+}
+
+func TestDevspaceSyncHandlerValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]any
+	}{
+		{"missing container path", map[string]any{"local_path": "."}},
+		{"conflicting directions", map[string]any{"local_path": ".", "container_path": "/app", "upload_only": true, "download_only": true}},
+		{"unknown strategy", map[string]any{"local_path": ".", "container_path": "/app", "initial_sync": "newest"}},
+		{"colon in local path", map[string]any{"local_path": "a:b", "container_path": "/app"}},
+		{"flag injection in exclude", map[string]any{"local_path": ".", "container_path": "/app", "exclude": "tmp,--pod=x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, ctx := useFakeRunner(t)
+			result, _ := DevspaceSyncHandler(ctx, newRequest(tt.args))
+			if !result.IsError {
+				t.Error("expected validation error")
+			}
+			if len(fake.Calls()) != 0 {
+				t.Error("devspace should not be invoked")
+			}
+		})
+	}
+}
@@ -0,0 +1,590 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ansiPattern        = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	columnGapPattern   = regexp.MustCompile(`\S+(?: \S+)*`)
+	tableRulePattern   = regexp.MustCompile(`^[\s\-+|=─┼│]*$`)
+	leadingDigitsRegex = regexp.MustCompile(`^\d+`)
+)
+
+// tableColumn is a column of a parsed table header
+type tableColumn struct {
+	key   string
+	start int
+}
+
+// parseTable parses the column aligned tables printed by devspace and
+// kubectl. The header is the first line with a column named like one of the
+// anchors; rows follow until the next blank line. Whitespace aligned tables
+// are split at the header column positions so empty cells are kept, '|'
+// separated tables are split at the separators. Rows are keyed by the
+// normalized header name ("Ports (Local:Remote)" becomes "portslocalremote").
+func parseTable(output string, anchors ...string) []map[string]string {
+	lines := strings.Split(ansiPattern.ReplaceAllString(output, ""), "\n")
+
+	header := -1
+	var columns []tableColumn
+	for i, line := range lines {
+		columns = tableHeader(line)
+		if hasColumn(columns, anchors) {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil
+	}
+	piped := strings.Contains(lines[header], "|")
+
+	var rows []map[string]string
+	for _, line := range lines[header+1:] {
+		line = strings.TrimRight(line, " \r")
+		if strings.TrimSpace(line) == "" {
+			if len(rows) > 0 {
+				break
+			}
+			continue
+		}
+		if tableRulePattern.MatchString(line) {
+			continue
+		}
+
+		row := make(map[string]string, len(columns))
+		if piped {
+			cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+			for i, c := range columns {
+				if i < len(cells) {
+					row[c.key] = strings.TrimSpace(cells[i])
+				}
+			}
+		} else {
+			runes := []rune(strings.ReplaceAll(line, "\t", "  "))
+			for i, c := range columns {
+				end := len(runes)
+				if i+1 < len(columns) && columns[i+1].start < end {
+					end = columns[i+1].start
+				}
+				if c.start < end {
+					row[c.key] = strings.TrimSpace(string(runes[c.start:end]))
+				}
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// tableHeader splits a header line into columns. Columns are separated by
+// '|' or by at least two spaces, so multi-word headers like "NOMINATED NODE"
+// stay together.
+func tableHeader(line string) []tableColumn {
+	line = strings.TrimRight(line, " \r")
+	var columns []tableColumn
+
+	if strings.Contains(line, "|") {
+		for _, cell := range strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|") {
+			columns = append(columns, tableColumn{key: normalizeColumn(cell)})
+		}
+		return columns
+	}
+
+	runes := []rune(strings.ReplaceAll(line, "\t", "  "))
+	text := string(runes)
+	for _, loc := range columnGapPattern.FindAllStringIndex(text, -1) {
+		columns = append(columns, tableColumn{
+			key:   normalizeColumn(text[loc[0]:loc[1]]),
+			start: len([]rune(text[:loc[0]])),
+		})
+	}
+	return columns
+}
+
+// hasColumn reports whether the header contains one of the anchor columns
+func hasColumn(columns []tableColumn, anchors []string) bool {
+	if len(columns) < 2 {
+		return false
+	}
+	for _, c := range columns {
+		for _, anchor := range anchors {
+			if c.key == normalizeColumn(anchor) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeColumn lowercases a header and drops everything but letters and digits
+func normalizeColumn(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// cell returns the first non-empty value of the given columns. A column
+// matches exactly or, failing that, by substring ("labelselector" matches
+// "podlabelselector").
+func cell(row map[string]string, names ...string) string {
+	for _, name := range names {
+		if v := row[name]; v != "" {
+			return v
+		}
+	}
+	for _, name := range names {
+		for key, v := range row {
+			if v != "" && strings.Contains(key, name) {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// isTrue interprets the boolean-ish cells devspace prints
+func isTrue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "*", "x", "✓", "✔":
+		return true
+	}
+	return false
+}
+
+// deploymentInfo is a row of 'devspace list deployments'
+type deploymentInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Deployed bool   `json:"deployed"`
+	Status   string `json:"status"`
+}
+
+// parseDeployments parses 'devspace list deployments' output
+func parseDeployments(output string) []deploymentInfo {
+	deployments := []deploymentInfo{}
+	for _, row := range parseTable(output, "name") {
+		deployments = append(deployments, deploymentInfo{
+			Name:     cell(row, "name"),
+			Type:     cell(row, "type"),
+			Deployed: isTrue(cell(row, "deployed", "deploy")),
+			Status:   cell(row, "status"),
+		})
+	}
+	return deployments
+}
+
+// namespaceInfo is a row of 'devspace list namespaces'
+type namespaceInfo struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+	Exists  bool   `json:"exists"`
+}
+
+// parseNamespaces parses 'devspace list namespaces' output
+func parseNamespaces(output string) []namespaceInfo {
+	namespaces := []namespaceInfo{}
+	for _, row := range parseTable(output, "name") {
+		namespaces = append(namespaces, namespaceInfo{
+			Name:    cell(row, "name"),
+			Default: isTrue(cell(row, "default")),
+			Exists:  isTrue(cell(row, "exists")),
+		})
+	}
+	return namespaces
+}
+
+// contextInfo is a row of 'devspace list contexts'
+type contextInfo struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+// parseContexts parses 'devspace list contexts' output
+func parseContexts(output string) []contextInfo {
+	contexts := []contextInfo{}
+	for _, row := range parseTable(output, "name") {
+		contexts = append(contexts, contextInfo{
+			Name:   cell(row, "name"),
+			Active: isTrue(cell(row, "active", "current")),
+		})
+	}
+	return contexts
+}
+
+// profileInfo is a row of 'devspace list profiles'
+type profileInfo struct {
+	Name        string `json:"name"`
+	Active      bool   `json:"active"`
+	Description string `json:"description,omitempty"`
+}
+
+// parseProfiles parses 'devspace list profiles' output
+func parseProfiles(output string) []profileInfo {
+	profiles := []profileInfo{}
+	for _, row := range parseTable(output, "name") {
+		profiles = append(profiles, profileInfo{
+			Name:        cell(row, "name"),
+			Active:      isTrue(cell(row, "active")),
+			Description: cell(row, "description"),
+		})
+	}
+	return profiles
+}
+
+// varInfo is a row of 'devspace list vars'
+type varInfo struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Unresolved explains why a locally parsed variable has no value
+	Unresolved string `json:"unresolved,omitempty"`
+}
+
+// parseVars parses 'devspace list vars' output
+func parseVars(output string) []varInfo {
+	vars := []varInfo{}
+	for _, row := range parseTable(output, "variable", "name") {
+		vars = append(vars, varInfo{
+			Name:  cell(row, "variable", "name"),
+			Value: cell(row, "value"),
+		})
+	}
+	return vars
+}
+
+// portInfo is a single local to remote port mapping of 'devspace list ports'
+type portInfo struct {
+	Local    int    `json:"local"`
+	Remote   int    `json:"remote"`
+	Selector string `json:"selector,omitempty"`
+}
+
+// parsePorts parses 'devspace list ports' output. A row may hold several
+// comma separated mappings, each becomes its own entry.
+func parsePorts(output string) []portInfo {
+	ports := []portInfo{}
+	for _, row := range parseTable(output, "ports", "portslocalremote", "port") {
+		selector := cell(row, "labelselector", "imageselector", "image", "selector")
+		ports = append(ports, parsePortMappings(cell(row, "ports", "port"), selector)...)
+	}
+	return ports
+}
+
+// parsePortMappings parses "local:remote" mappings separated by commas or
+// spaces. A single port forwards the same local and remote port.
+func parsePortMappings(mappings, selector string) []portInfo {
+	var ports []portInfo
+	for _, mapping := range strings.FieldsFunc(mappings, func(r rune) bool {
+		return r == ',' || r == ' '
+	}) {
+		localPart, remotePart, found := strings.Cut(mapping, ":")
+		if !found {
+			remotePart = localPart
+		}
+		local, err := strconv.Atoi(localPart)
+		if err != nil {
+			continue
+		}
+		remote, err := strconv.Atoi(remotePart)
+		if err != nil {
+			continue
+		}
+		ports = append(ports, portInfo{Local: local, Remote: remote, Selector: selector})
+	}
+	return ports
+}
+
+// parsePortsJSON extracts port mappings from 'devspace list ports -o json'.
+// Entries are recognized by a "port" mapping string ("8080:80") or by
+// local/remote port numbers; the selector is taken from the entry or its parent.
+func parsePortsJSON(output string) []portInfo {
+	var decoded any
+	if err := yaml.Unmarshal([]byte(output), &decoded); err != nil {
+		return []portInfo{}
+	}
+	ports := []portInfo{}
+	collectPorts(decoded, "", &ports)
+	return ports
+}
+
+// collectPorts walks decoded JSON and appends every port mapping it finds
+func collectPorts(value any, selector string, ports *[]portInfo) {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			collectPorts(item, selector, ports)
+		}
+	case map[string]any:
+		if s := jsonSelector(v); s != "" {
+			selector = s
+		}
+		if mapping, ok := v["port"].(string); ok {
+			*ports = append(*ports, parsePortMappings(mapping, selector)...)
+			return
+		}
+		local, hasLocal := jsonInt(v, "local", "localPort", "port")
+		remote, hasRemote := jsonInt(v, "remote", "remotePort", "containerPort")
+		if hasLocal || hasRemote {
+			if !hasRemote {
+				remote = local
+			} else if !hasLocal {
+				local = remote
+			}
+			*ports = append(*ports, portInfo{Local: local, Remote: remote, Selector: selector})
+			return
+		}
+		for _, child := range v {
+			collectPorts(child, selector, ports)
+		}
+	}
+}
+
+// jsonSelector renders a label or image selector of a decoded JSON entry
+func jsonSelector(entry map[string]any) string {
+	for _, key := range []string{"labelSelector", "selector", "imageSelector"} {
+		switch s := entry[key].(type) {
+		case string:
+			if s != "" {
+				return s
+			}
+		case map[string]any:
+			pairs := make([]string, 0, len(s))
+			for k, v := range s {
+				pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+			}
+			sort.Strings(pairs)
+			if len(pairs) > 0 {
+				return strings.Join(pairs, ",")
+			}
+		}
+	}
+	return ""
+}
+
+// jsonInt returns the first of the given keys holding a number
+func jsonInt(entry map[string]any, keys ...string) (int, bool) {
+	for _, key := range keys {
+		switch n := entry[key].(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// syncPathInfo is a row of 'devspace list sync'
+type syncPathInfo struct {
+	Selector      string `json:"selector,omitempty"`
+	LocalPath     string `json:"local_path"`
+	ContainerPath string `json:"container_path"`
+	ExcludedPaths string `json:"excluded_paths,omitempty"`
+}
+
+// parseSyncPaths parses 'devspace list sync' output
+func parseSyncPaths(output string) []syncPathInfo {
+	paths := []syncPathInfo{}
+	for _, row := range parseTable(output, "localpath") {
+		paths = append(paths, syncPathInfo{
+			Selector:      cell(row, "labelselector", "imageselector", "selector"),
+			LocalPath:     cell(row, "localpath"),
+			ContainerPath: cell(row, "containerpath"),
+			ExcludedPaths: cell(row, "excludedpaths", "excluded"),
+		})
+	}
+	return paths
+}
+
+// podInfo is a pod as shown by 'kubectl get pods'
+type podInfo struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Phase     string `json:"phase,omitempty" jsonschema:"description=Pod status as displayed by kubectl such as Running or CrashLoopBackOff"`
+	Ready     string `json:"ready,omitempty" jsonschema:"description=Ready containers out of total (e.g. 1/2)"`
+	Restarts  int    `json:"restarts"`
+	Age       string `json:"age,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	Node      string `json:"node,omitempty"`
+}
+
+// parsePods parses 'kubectl get pods' output in any of the supported formats
+func parsePods(output, format string) []podInfo {
+	switch format {
+	case "json", "yaml":
+		return parsePodObjects(output, time.Now())
+	case "name":
+		pods := []podInfo{}
+		for _, line := range strings.Split(output, "\n") {
+			if name := strings.TrimSpace(line); name != "" {
+				pods = append(pods, podInfo{Name: strings.TrimPrefix(name, "pod/")})
+			}
+		}
+		return pods
+	}
+
+	pods := []podInfo{}
+	for _, row := range parseTable(output, "name") {
+		restarts, _ := strconv.Atoi(leadingDigitsRegex.FindString(cell(row, "restarts")))
+		node := row["node"]
+		if node == "<none>" {
+			node = ""
+		}
+		ip := row["ip"]
+		if ip == "<none>" {
+			ip = ""
+		}
+		pods = append(pods, podInfo{
+			Namespace: row["namespace"],
+			Name:      row["name"],
+			Phase:     row["status"],
+			Ready:     row["ready"],
+			Restarts:  restarts,
+			Age:       row["age"],
+			IP:        ip,
+			Node:      node,
+		})
+	}
+	return pods
+}
+
+// podObject holds the fields of a Pod resource needed for podInfo. The yaml
+// decoder reads kubectl's JSON output as well.
+type podObject struct {
+	Metadata struct {
+		Name              string            `yaml:"name"`
+		Namespace         string            `yaml:"namespace"`
+		CreationTimestamp time.Time         `yaml:"creationTimestamp"`
+		DeletionTimestamp string            `yaml:"deletionTimestamp"`
+		Labels            map[string]string `yaml:"labels"`
+		Annotations       map[string]string `yaml:"annotations"`
+		OwnerReferences   []struct {
+			Kind string `yaml:"kind"`
+			Name string `yaml:"name"`
+		} `yaml:"ownerReferences"`
+	} `yaml:"metadata"`
+	Spec struct {
+		NodeName   string `yaml:"nodeName"`
+		Containers []struct {
+			Name string `yaml:"name"`
+		} `yaml:"containers"`
+	} `yaml:"spec"`
+	Status struct {
+		Phase      string `yaml:"phase"`
+		Reason     string `yaml:"reason"`
+		Message    string `yaml:"message"`
+		PodIP      string `yaml:"podIP"`
+		Conditions []struct {
+			Type    string `yaml:"type"`
+			Status  string `yaml:"status"`
+			Reason  string `yaml:"reason"`
+			Message string `yaml:"message"`
+		} `yaml:"conditions"`
+		InitContainerStatuses []containerStatus `yaml:"initContainerStatuses"`
+		ContainerStatuses     []containerStatus `yaml:"containerStatuses"`
+	} `yaml:"status"`
+}
+
+// containerStatus is the status of a container in a Pod resource
+type containerStatus struct {
+	Name         string         `yaml:"name"`
+	Image        string         `yaml:"image"`
+	Ready        bool           `yaml:"ready"`
+	RestartCount int            `yaml:"restartCount"`
+	State        containerState `yaml:"state"`
+	LastState    containerState `yaml:"lastState"`
+}
+
+// containerState is the current or last state of a container
+type containerState struct {
+	Waiting *struct {
+		Reason  string `yaml:"reason"`
+		Message string `yaml:"message"`
+	} `yaml:"waiting"`
+	Terminated *struct {
+		Reason   string `yaml:"reason"`
+		Message  string `yaml:"message"`
+		ExitCode int    `yaml:"exitCode"`
+	} `yaml:"terminated"`
+}
+
+// parsePodObjects parses a Pod or a List of pods in JSON or YAML
+func parsePodObjects(output string, now time.Time) []podInfo {
+	var list struct {
+		Kind  string      `yaml:"kind"`
+		Items []podObject `yaml:"items"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &list); err != nil {
+		return []podInfo{}
+	}
+	if list.Kind == "Pod" {
+		var pod podObject
+		if err := yaml.Unmarshal([]byte(output), &pod); err == nil {
+			list.Items = []podObject{pod}
+		}
+	}
+
+	pods := []podInfo{}
+	for _, p := range list.Items {
+		info := podInfo{
+			Namespace: p.Metadata.Namespace,
+			Name:      p.Metadata.Name,
+			Phase:     p.Status.Phase,
+			IP:        p.Status.PodIP,
+			Node:      p.Spec.NodeName,
+		}
+		if p.Status.Reason != "" {
+			info.Phase = p.Status.Reason
+		}
+
+		ready := 0
+		for _, c := range p.Status.ContainerStatuses {
+			if c.Ready {
+				ready++
+			}
+			info.Restarts += c.RestartCount
+			// Mirror kubectl, which shows the container reason instead of the phase
+			if c.State.Waiting != nil && c.State.Waiting.Reason != "" {
+				info.Phase = c.State.Waiting.Reason
+			} else if c.State.Terminated != nil && c.State.Terminated.Reason != "" {
+				info.Phase = c.State.Terminated.Reason
+			}
+		}
+		if p.Metadata.DeletionTimestamp != "" {
+			info.Phase = "Terminating"
+		}
+		info.Ready = fmt.Sprintf("%d/%d", ready, len(p.Status.ContainerStatuses))
+		if !p.Metadata.CreationTimestamp.IsZero() {
+			info.Age = formatAge(now.Sub(p.Metadata.CreationTimestamp))
+		}
+		pods = append(pods, info)
+	}
+	return pods
+}
+
+// formatAge renders a duration the way kubectl prints ages (e.g. 45s, 12m, 3h, 5d)
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		anchors []string
+		want    []map[string]string
+	}{
+		{
+			name:    "whitespace aligned with empty cell",
+			output:  "info Loading config\n NAME   VALUE   NOTE\n a      1       x\n b              y\n",
+			anchors: []string{"name"},
+			want: []map[string]string{
+				{"name": "a", "value": "1", "note": "x"},
+				{"name": "b", "value": "", "note": "y"},
+			},
+		},
+		{
+			name:    "pipe separated with rule",
+			output:  "| Name | Active |\n|------+--------|\n| dev  | true   |\n",
+			anchors: []string{"name"},
+			want:    []map[string]string{{"name": "dev", "active": "true"}},
+		},
+		{
+			name:    "stops at blank line",
+			output:  "NAME  TYPE\napi   helm\n\nsome trailing log line\n",
+			anchors: []string{"name"},
+			want:    []map[string]string{{"name": "api", "type": "helm"}},
+		},
+		{
+			name:    "no header",
+			output:  "error loading config\n",
+			anchors: []string{"name"},
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTable(tt.output, tt.anchors...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDeployments(t *testing.T) {
+	got := parseDeployments(readFixture(t, "list_deployments.txt"))
+	want := []deploymentInfo{
+		{Name: "backend", Type: "helm", Deployed: true, Status: "Deployed"},
+		{Name: "frontend", Type: "kubectl", Deployed: true, Status: "Deployed"},
+		{Name: "worker", Type: "helm", Deployed: false, Status: "Skipped"},
+		{Name: "db", Type: "helm", Deployed: true, Status: "Failed: timed out"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDeployments() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseNamespaces(t *testing.T) {
+	got := parseNamespaces(readFixture(t, "list_namespaces.txt"))
+	want := []namespaceInfo{
+		{Name: "default", Exists: true},
+		{Name: "dev", Default: true, Exists: true},
+		{Name: "feature-x"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNamespaces() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseContexts(t *testing.T) {
+	got := parseContexts(readFixture(t, "list_contexts.txt"))
+	want := []contextInfo{
+		{Name: "docker-desktop"},
+		{Name: "kind-dev", Active: true},
+		{Name: "arn:aws:eks:eu-west-1:123456789012:cluster/dev-east"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseContexts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	got := parseProfiles(readFixture(t, "list_profiles.txt"))
+	want := []profileInfo{
+		{Name: "staging", Description: "Deploy against the staging cluster"},
+		{Name: "debug", Active: true},
+		{Name: "production", Description: "Production settings"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProfiles() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	got := parseVars(readFixture(t, "list_vars.txt"))
+	want := []varInfo{
+		{Name: "IMAGE", Value: "registry.example.com/api"},
+		{Name: "REPLICAS", Value: "2"},
+		{Name: "DB_PASSWORD"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseVars() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	got := parsePorts(readFixture(t, "list_ports.txt"))
+	want := []portInfo{
+		{Local: 8080, Remote: 80, Selector: "app=api"},
+		{Local: 9229, Remote: 9229, Selector: "app=api"},
+		{Local: 3000, Remote: 3000, Selector: "registry.example.com/web:dev"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePorts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSyncPaths(t *testing.T) {
+	got := parseSyncPaths(readFixture(t, "list_sync.txt"))
+	want := []syncPathInfo{
+		{Selector: "app=api", LocalPath: "./src", ContainerPath: "/app/src", ExcludedPaths: "node_modules,.git"},
+		{Selector: "app=web", LocalPath: "./web", ContainerPath: "/usr/share/web"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSyncPaths() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePodsWide(t *testing.T) {
+	got := parsePods(readFixture(t, "kubectl_pods_wide.txt"), "wide")
+	want := []podInfo{
+		{Name: "api-7d9f8b6c5d-x2kqp", Phase: "Running", Ready: "1/1", Restarts: 0, Age: "3d", IP: "10.244.0.12", Node: "kind-worker"},
+		{Name: "worker-5b6f7c8d9-abcde", Phase: "CrashLoopBackOff", Ready: "0/1", Restarts: 12, Age: "45m", IP: "10.244.1.7", Node: "kind-worker2"},
+		{Name: "migrate-job-zk2lp", Phase: "Pending", Ready: "0/1", Restarts: 0, Age: "5s"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePods() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePodObjects(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	got := parsePodObjects(readFixture(t, "kubectl_pods.json"), now)
+	want := []podInfo{
+		{Namespace: "dev", Name: "api-7d9f8b6c5d-x2kqp", Phase: "Running", Ready: "2/2", Restarts: 1, Age: "3d", IP: "10.244.0.12", Node: "kind-worker"},
+		{Namespace: "dev", Name: "worker-5b6f7c8d9-abcde", Phase: "CrashLoopBackOff", Ready: "0/1", Restarts: 12, Age: "45m", IP: "10.244.1.7", Node: "kind-worker2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePodObjects() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePodsName(t *testing.T) {
+	got := parsePods("pod/api-1\npod/api-2\n", "name")
+	want := []podInfo{{Name: "api-1"}, {Name: "api-2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePods() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsePortsJSON(t *testing.T) {
+	output := `[
+  {"labelSelector": {"app": "api"}, "ports": [{"port": "8080:80"}, {"port": "9229"}]},
+  {"imageSelector": "registry.example.com/web:dev", "localPort": 3000, "remotePort": 80}
+]`
+	got := parsePortsJSON(output)
+	want := []portInfo{
+		{Local: 8080, Remote: 80, Selector: "app=api"},
+		{Local: 9229, Remote: 9229, Selector: "app=api"},
+		{Local: 3000, Remote: 80, Selector: "registry.example.com/web:dev"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePortsJSON() = %+v, want %+v", got, want)
+	}
+
+	if got := parsePortsJSON("not json"); len(got) != 0 {
+		t.Errorf("expected no ports for invalid output, got %+v", got)
+	}
+}
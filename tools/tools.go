@@ -1,51 +1,162 @@
 package tools
 
 import (
+	"context"
+	"time"
+
+	"devspace-mcp/executor"
+
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-// RegisterAll registers all devspace tools with the MCP server
-func RegisterAll(s *server.MCPServer) {
+// runnerKey is the context key of the runner of a tool call
+type runnerKey struct{}
+
+// defaultRunner runs the commands of calls whose context carries no runner
+var defaultRunner executor.Runner = executor.NewExecRunner()
+
+// WithRunner returns a context whose tool calls execute devspace and kubectl
+// through r
+func WithRunner(ctx context.Context, r executor.Runner) context.Context {
+	return context.WithValue(ctx, runnerKey{}, r)
+}
+
+// runnerFrom returns the runner of a tool call
+func runnerFrom(ctx context.Context) executor.Runner {
+	if r, ok := ctx.Value(runnerKey{}).(executor.Runner); ok {
+		return r
+	}
+	return defaultRunner
+}
+
+// RegisterAll registers all devspace tools with the MCP server. Their
+// commands are executed by r, or by the operating system when r is nil.
+func RegisterAll(s *server.MCPServer, r executor.Runner) {
+	if r == nil {
+		r = defaultRunner
+	}
+	toolStatuses = nil
+
 	// Version tool
-	s.AddTool(DevspaceVersionTool(), DevspaceVersionHandler)
+	addTool(s, r, DevspaceVersionTool(), DevspaceVersionHandler)
 
 	// List tools
-	s.AddTool(DevspaceListNamespacesTool(), DevspaceListNamespacesHandler)
-	s.AddTool(DevspaceListContextsTool(), DevspaceListContextsHandler)
-	s.AddTool(DevspaceListDeploymentsTool(), DevspaceListDeploymentsHandler)
-	s.AddTool(DevspaceListProfilesTool(), DevspaceListProfilesHandler)
-	s.AddTool(DevspaceListVarsTool(), DevspaceListVarsHandler)
+	addTool(s, r, DevspaceListNamespacesTool(), DevspaceListNamespacesHandler)
+	addTool(s, r, DevspaceListContextsTool(), DevspaceListContextsHandler)
+	addTool(s, r, DevspaceListDeploymentsTool(), DevspaceListDeploymentsHandler)
+	addTool(s, r, DevspaceListProfilesTool(), DevspaceListProfilesHandler)
+	addTool(s, r, DevspaceListVarsTool(), DevspaceListVarsHandler)
 
 	// Print tool
-	s.AddTool(DevspacePrintTool(), DevspacePrintHandler)
+	addTool(s, r, DevspacePrintTool(), DevspacePrintHandler)
+
+	// Config inspection tools
+	addTool(s, r, DevspaceDiffProfilesTool(), DevspaceDiffProfilesHandler)
+	addTool(s, r, DevspaceLintConfigTool(), DevspaceLintConfigHandler)
 
 	// Analyze tool
-	s.AddTool(DevspaceAnalyzeTool(), DevspaceAnalyzeHandler)
+	addTool(s, r, DevspaceAnalyzeTool(), DevspaceAnalyzeHandler)
 
 	// Logs tool
-	s.AddTool(DevspaceLogsTool(), DevspaceLogsHandler)
+	addTool(s, r, DevspaceLogsTool(), DevspaceLogsHandler)
 
 	// Build tool
-	s.AddTool(DevspaceBuildTool(), DevspaceBuildHandler)
+	addTool(s, r, DevspaceBuildTool(), DevspaceBuildHandler)
 
 	// Deploy tool
-	s.AddTool(DevspaceDeployTool(), DevspaceDeployHandler)
+	addTool(s, r, DevspaceDeployTool(), DevspaceDeployHandler)
 
 	// Purge tool
-	s.AddTool(DevspacePurgeTool(), DevspacePurgeHandler)
+	addTool(s, r, DevspacePurgeTool(), DevspacePurgeHandler)
 
 	// Run tool
-	s.AddTool(DevspaceRunTool(), DevspaceRunHandler)
+	addTool(s, r, DevspaceRunTool(), DevspaceRunHandler)
+
+	// Pipeline tool
+	addTool(s, r, DevspaceRunPipelineTool(), DevspaceRunPipelineHandler)
+
+	// Render tool
+	addTool(s, r, DevspaceRenderTool(), DevspaceRenderHandler)
 
 	// Exec tool
-	s.AddTool(DevspaceExecTool(), DevspaceExecHandler)
+	addTool(s, r, DevspaceExecTool(), DevspaceExecHandler)
 
 	// Pods tool (kubectl wrapper)
-	s.AddTool(DevspaceListPodsTool(), DevspaceListPodsHandler)
+	addTool(s, r, DevspaceListPodsTool(), DevspaceListPodsHandler)
+	addTool(s, r, DevspaceEventsTool(), DevspaceEventsHandler)
+	addTool(s, r, DevspaceDiagnoseTool(), DevspaceDiagnoseHandler)
 
 	// Status tool (composite)
-	s.AddTool(DevspaceStatusTool(), DevspaceStatusHandler)
+	addTool(s, r, DevspaceStatusTool(), DevspaceStatusHandler)
 
 	// Ports tool
-	s.AddTool(DevspaceListPortsTool(), DevspaceListPortsHandler)
+	addTool(s, r, DevspaceListPortsTool(), DevspaceListPortsHandler)
+
+	// Sync tool (one-shot)
+	addTool(s, r, DevspaceSyncTool(), DevspaceSyncHandler)
+
+	// Dev session tools (background devspace dev)
+	addTool(s, r, DevspaceDevStartTool(), DevspaceDevStartHandler)
+	addTool(s, r, DevspaceDevStopTool(), DevspaceDevStopHandler)
+	addTool(s, r, DevspaceDevStatusTool(), DevspaceDevStatusHandler)
+	addTool(s, r, DevspaceDevOutputTool(), DevspaceDevOutputHandler)
+
+	// Port forward tools (background kubectl port-forward)
+	addTool(s, r, DevspacePortForwardTool(), DevspacePortForwardHandler)
+	addTool(s, r, DevspacePortForwardListTool(), DevspacePortForwardListHandler)
+	addTool(s, r, DevspacePortForwardStopTool(), DevspacePortForwardStopHandler)
+
+	// Project registry tool
+	addTool(s, r, DevspaceListProjectsTool(), DevspaceListProjectsHandler)
+
+	// Session context tools
+	addTool(s, r, DevspaceSetContextTool(), DevspaceSetContextHandler)
+	addTool(s, r, DevspaceGetContextTool(), DevspaceGetContextHandler)
+
+	// Server info tool
+	addTool(s, r, DevspaceServerInfoTool(), DevspaceServerInfoHandler)
+	addTool(s, r, DevspaceListErrorPatternsTool(), DevspaceListErrorPatternsHandler)
+}
+
+// addTool registers a tool unless the configuration filters it out. Calls
+// get the session or configured defaults and run in the directory of the
+// located devspace config. They are checked against the server policy and
+// have secrets masked and their output size limited, and execute their
+// commands through r.
+func addTool(s *server.MCPServer, r executor.Runner, tool mcp.Tool, handler server.ToolHandlerFunc) {
+	selected := toolSelected(tool)
+	toolStatuses = append(toolStatuses, toolStatus{Name: tool.Name, Category: toolCategory(tool), Registered: selected})
+	if !selected {
+		return
+	}
+	tool, handler = guardTool(tool, handler)
+	tool, handler = redactTool(tool, handler)
+	tool, handler = limitOutput(tool, handler)
+	tool, handler = withProject(tool, handler)
+	if !managesContext(tool) {
+		tool, handler = withDefaults(tool, handler)
+	}
+	tool, handler = withProjectParam(tool, handler)
+	s.AddTool(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handler(WithRunner(ctx, r), req)
+	})
+}
+
+// Shutdown stops all background processes started by the tools. It should be
+// called once the server stops serving requests.
+func Shutdown() {
+	devSessions.stopAll()
+	portForwards.stopAll()
+}
+
+// executeDevspace runs a devspace command through the runner of the call
+func executeDevspace(ctx context.Context, timeout time.Duration, workingDir string, args ...string) executor.Result {
+	return runnerFrom(ctx).Run(ctx, executor.Command{
+		Binary:  executor.DevspaceBinary,
+		Args:    args,
+		Dir:     workingDir,
+		Env:     devspaceEnv(ctx),
+		Timeout: timeout,
+	})
 }
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// useFakeRunner returns a fake runner and a context whose tool calls use it
+func useFakeRunner(t *testing.T) (*executor.FakeRunner, context.Context) {
+	t.Helper()
+	fake := executor.NewFakeRunner()
+	return fake, WithRunner(context.Background(), fake)
+}
+
+// newRequest builds a tool call request with the given arguments
+func newRequest(args map[string]any) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	return req
+}
+
+// resultText returns the concatenated text content of a tool result
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, c := range result.Content {
+		if tc, ok := c.(mcp.TextContent); ok {
+			parts = append(parts, tc.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func TestRegisterAll(t *testing.T) {
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	fake := executor.NewFakeRunner()
+	fake.On("devspace", []string{"version"}, executor.Result{Stdout: "devspace version : 6.3.12"})
+	RegisterAll(s, fake)
+
+	if _, err := s.GetTool("devspace_version").Handler(context.Background(), newRequest(map[string]any{})); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("registered tools should run commands through the given runner, got %v", fake.Calls())
+	}
+
+	for _, name := range []string{"devspace_version", "devspace_deploy", "devspace_status", "devspace_list_pods"} {
+		if s.GetTool(name) == nil {
+			t.Errorf("expected tool %s to be registered", name)
+		}
+	}
+}
+
+func TestRegisterAllKeepsRunnersApart(t *testing.T) {
+	servers := make([]*server.MCPServer, 2)
+	fakes := make([]*executor.FakeRunner, 2)
+	for i := range servers {
+		servers[i] = server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+		fakes[i] = executor.NewFakeRunner()
+		RegisterAll(servers[i], fakes[i])
+	}
+
+	for i, s := range servers {
+		if _, err := s.GetTool("devspace_version").Handler(context.Background(), newRequest(map[string]any{})); err != nil {
+			t.Fatal(err)
+		}
+		for j, fake := range fakes {
+			want := 0
+			if j <= i {
+				want = 1
+			}
+			if got := len(fake.Calls()); got != want {
+				t.Errorf("after calling server %d, runner %d has %d calls, want %d", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestRegisterAllDeclaresOutputSchemas(t *testing.T) {
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	RegisterAll(s, executor.NewFakeRunner())
+
+	for name, tool := range s.ListTools() {
+		schema := tool.Tool.OutputSchema
+		if schema.Type != "object" || len(schema.Properties) == 0 {
+			t.Errorf("tool %s should declare an object output schema, got %+v", name, schema)
+		}
+	}
+}
+
+func TestRegisterAllAssignsCategories(t *testing.T) {
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	RegisterAll(s, executor.NewFakeRunner())
+
+	for name, tool := range s.ListTools() {
+		category := toolCategory(tool.Tool)
+		if !slices.Contains(serverconfig.Categories, category) {
+			t.Errorf("tool %s has no valid category, got %q", name, category)
+		}
+		readOnly := tool.Tool.Annotations.ReadOnlyHint != nil && *tool.Tool.Annotations.ReadOnlyHint
+		if readOnly != (category == serverconfig.CategoryRead) {
+			t.Errorf("tool %s: read-only hint %v does not match category %s", name, readOnly, category)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around a change
+const diffContext = 3
+
+// lineOp is a line of an edit script: ' ' keeps, '-' deletes and '+'
+// inserts a line
+type lineOp struct {
+	kind byte
+	text string
+}
+
+// maxDiffEdits bounds the edits diffLines searches for. The saved search
+// states grow with its square, so larger changes are reported as one
+// replacement instead.
+const maxDiffEdits = 1000
+
+// diffLines returns an edit script turning a into b. Lines the texts share
+// at their start and end are kept; the rest is diffed with Myers' algorithm.
+func diffLines(a, b []string) []lineOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]lineOp, 0, len(a)+len(b)-prefix-suffix)
+	for _, line := range a[:prefix] {
+		ops = append(ops, lineOp{' ', line})
+	}
+	ops = append(ops, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, lineOp{' ', line})
+	}
+	return ops
+}
+
+// myersDiff returns the shortest edit script turning a into b, or deletes a
+// and inserts b when that takes more than maxDiffEdits edits
+func myersDiff(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds the diagonals -d-1 to d+1 of v before step d, the
+	// only ones the walk back reads
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		if d > maxDiffEdits {
+			return replaceLines(a, b)
+		}
+		trace = append(trace, slices.Clone(v[offset-d-1:offset+d+2]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk back through the saved states to recover the edits
+	var ops []lineOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, base := trace[d], d+1
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[base+k-1] < v[base+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[base+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, lineOp{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, lineOp{'+', b[y-1]})
+			} else {
+				ops = append(ops, lineOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(ops)
+	return ops
+}
+
+// replaceLines returns the edit script deleting all of a and inserting all
+// of b
+func replaceLines(a, b []string) []lineOp {
+	ops := make([]lineOp, 0, len(a)+len(b))
+	for _, line := range a {
+		ops = append(ops, lineOp{'-', line})
+	}
+	for _, line := range b {
+		ops = append(ops, lineOp{'+', line})
+	}
+	return ops
+}
+
+// unifiedDiff renders the differences between two texts in unified diff
+// format, or returns "" when they are equal
+func unifiedDiff(fromName, toName, from, to string) string {
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	// Each hunk covers the changed lines plus their context; hunks whose
+	// context overlaps are merged
+	type span struct{ start, end int }
+	var hunks []span
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := max(i-diffContext, 0), min(i+diffContext+1, len(ops))
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+			continue
+		}
+		hunks = append(hunks, span{start, end})
+	}
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	// Line numbers in a and b before each op
+	aLine, bLine := make([]int, len(ops)), make([]int, len(ops))
+	for i, a, b := 0, 0, 0; i < len(ops); i++ {
+		aLine[i], bLine[i] = a, b
+		if ops[i].kind != '+' {
+			a++
+		}
+		if ops[i].kind != '-' {
+			b++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for _, h := range hunks {
+		var aCount, bCount int
+		for _, op := range ops[h.start:h.end] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aLine[h.start], aCount), hunkRange(bLine[h.start], bCount))
+		for _, op := range ops[h.start:h.end] {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.text)
+		}
+	}
+	return out.String()
+}
+
+// hunkRange formats the start line and length of a hunk side. An empty side
+// refers to the line before the hunk, as in GNU diff.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
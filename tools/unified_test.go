@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name, from, to, want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"changed line", "a\nb\nc\n", "a\nB\nc\n", "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"},
+		{"added to empty", "", "a\n", "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n"},
+		{
+			"separate hunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			"--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			"merged hunks",
+			"1\n2\n3\n4\n5\n",
+			"1\nx\n3\n4\ny\n",
+			"--- old\n+++ new\n@@ -1,5 +1,5 @@\n 1\n-2\n+x\n 3\n 4\n-5\n+y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", tt.from, tt.to); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// applyOps returns the texts an edit script turns into each other
+func applyOps(ops []lineOp) (from, to []string) {
+	for _, op := range ops {
+		if op.kind != '+' {
+			from = append(from, op.text)
+		}
+		if op.kind != '-' {
+			to = append(to, op.text)
+		}
+	}
+	return from, to
+}
+
+func TestDiffLinesEditScript(t *testing.T) {
+	var a, b []string
+	for i := range 300 {
+		a = append(a, fmt.Sprintf("line %d", i))
+		if i%7 != 0 {
+			b = append(b, fmt.Sprintf("line %d", i))
+		}
+		if i%11 == 0 {
+			b = append(b, fmt.Sprintf("new %d", i))
+		}
+	}
+	ops := diffLines(a, b)
+	from, to := applyOps(ops)
+	if fmt.Sprint(from) != fmt.Sprint(a) || fmt.Sprint(to) != fmt.Sprint(b) {
+		t.Fatal("edit script does not turn a into b")
+	}
+	edits := 0
+	for _, op := range ops {
+		if op.kind != ' ' {
+			edits++
+		}
+	}
+	if want := 43 + 28; edits != want {
+		t.Errorf("edits = %d, want %d", edits, want)
+	}
+}
+
+func TestDiffLinesLargeChange(t *testing.T) {
+	var a, b []string
+	for i := range 4000 {
+		a = append(a, fmt.Sprintf("old %d", i))
+		b = append(b, fmt.Sprintf("new %d", i))
+	}
+	a = append([]string{"header"}, append(a, "footer")...)
+	b = append([]string{"header"}, append(b, "footer")...)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	ops := diffLines(a, b)
+	runtime.ReadMemStats(&after)
+
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 64<<20 {
+		t.Errorf("diff allocated %d MiB", alloc>>20)
+	}
+	from, to := applyOps(ops)
+	if fmt.Sprint(from) != fmt.Sprint(a) || fmt.Sprint(to) != fmt.Sprint(b) {
+		t.Fatal("edit script does not turn a into b")
+	}
+	if ops[0] != (lineOp{' ', "header"}) || ops[1].kind != '-' || ops[4001].kind != '+' || ops[len(ops)-1] != (lineOp{' ', "footer"}) {
+		t.Errorf("expected the changed section to be replaced whole, got %v ... %v", ops[:3], ops[len(ops)-2:])
+	}
+}
@@ -2,8 +2,6 @@ package tools
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 )
 
@@ -19,6 +17,22 @@ func ValidateStringParam(name, value string) error {
 	return nil
 }
 
+// appendListFlag splits a comma-separated parameter value and appends flag
+// once per entry, validating each entry
+func appendListFlag(args []string, name, flag, value string) ([]string, error) {
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if err := ValidateStringParam(name, v); err != nil {
+			return args, err
+		}
+		args = append(args, flag, v)
+	}
+	return args, nil
+}
+
 // ValidateCommandName validates that a command name contains only safe characters
 func ValidateCommandName(name string) error {
 	if name == "" {
@@ -45,23 +59,10 @@ func isValidCommandChar(r rune) bool {
 		r == '-' || r == '_' || r == ':'
 }
 
-// ValidateDevspaceYaml checks if devspace.yaml exists in the specified directory
-// If workingDir is empty, uses current directory
+// ValidateDevspaceYaml checks that a devspace config exists in the specified
+// directory or one of its parents. If workingDir is empty, uses current directory.
+// It does not look at the content; devspace_lint_config validates that.
 func ValidateDevspaceYaml(workingDir string) error {
-	if workingDir == "" {
-		var err error
-		workingDir, err = os.Getwd()
-		if err != nil {
-			return fmt.Errorf("could not determine current directory: %w", err)
-		}
-	}
-
-	configPath := filepath.Join(workingDir, "devspace.yaml")
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return fmt.Errorf("devspace.yaml not found in %s. Use working_dir parameter to specify the project location", workingDir)
-	} else if err != nil {
-		return fmt.Errorf("error checking devspace.yaml: %w", err)
-	}
-
-	return nil
+	_, err := LocateDevspaceConfig(workingDir, "")
+	return err
 }
@@ -3,6 +3,7 @@ package tools
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -233,3 +234,18 @@ func TestValidateDevspaceYamlWithCurrentDir(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendListFlag(t *testing.T) {
+	args, err := appendListFlag([]string{"deploy"}, "dependency", "--dependency", " db, ,cache ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"deploy", "--dependency", "db", "--dependency", "cache"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Errorf("appendListFlag() = %v, want %v", args, want)
+	}
+
+	if _, err := appendListFlag(nil, "tag", "--tag", "v1,-x"); err == nil {
+		t.Error("expected error for entry starting with '-'")
+	}
+}
@@ -3,7 +3,7 @@ package tools
 import (
 	"context"
 
-	"devspace-mcp/executor"
+	"devspace-mcp/serverconfig"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -12,16 +12,22 @@ import (
 func DevspaceVersionTool() mcp.Tool {
 	return mcp.NewTool("devspace_version",
 		mcp.WithDescription("Get the devspace CLI version"),
+		mcp.WithOutputSchema[versionOutput](),
+		withCategory(serverconfig.CategoryRead),
 	)
 }
 
 // DevspaceVersionHandler handles the devspace version command
 func DevspaceVersionHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	result := executor.Execute(ctx, "version")
+	result := executeDevspace(ctx, commandTimeout("devspace_version"), "", "version")
 
 	if !result.Success() {
 		return mcp.NewToolResultError(result.FormatOutput()), nil
 	}
 
-	return mcp.NewToolResultText(result.FormatOutput()), nil
+	output := versionOutput{
+		Version: versionPattern.FindString(result.Stdout),
+		Output:  result.FormatOutput(),
+	}
+	return mcp.NewToolResultStructured(output, result.FormatOutput()), nil
 }
@@ -0,0 +1,148 @@
+// Package transport serves an MCP server over stdio, SSE or streamable
+// HTTP. The network transports let one server instance be shared, for
+// example inside a devcontainer or by a remote agent.
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Supported transports
+const (
+	Stdio = "stdio"
+	SSE   = "sse"
+	HTTP  = "http"
+)
+
+// DefaultAddr is the listen address of the network transports
+const DefaultAddr = "127.0.0.1:8080"
+
+// HTTPEndpoint is the path of the streamable HTTP endpoint
+const HTTPEndpoint = "/mcp"
+
+// ShutdownTimeout bounds how long open connections may take to finish
+// after a shutdown was requested
+const ShutdownTimeout = 10 * time.Second
+
+// Options configures how the server is served
+type Options struct {
+	// Transport is one of Stdio, SSE or HTTP
+	Transport string
+	// Addr is the listen address of the network transports
+	Addr string
+	// AuthToken, when set, must be sent as "Authorization: Bearer <token>"
+	// on every request to the network transports
+	AuthToken string
+}
+
+// Serve serves s until ctx is cancelled or the transport fails. Network
+// transports are shut down gracefully when ctx is cancelled.
+func Serve(ctx context.Context, s *server.MCPServer, opts Options) error {
+	switch opts.Transport {
+	case "", Stdio:
+		err := server.NewStdioServer(s).Listen(ctx, os.Stdin, os.Stdout)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	case SSE, HTTP:
+		addr := opts.Addr
+		if addr == "" {
+			addr = DefaultAddr
+		}
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		return serveListener(ctx, s, opts, ln)
+	default:
+		return fmt.Errorf("unknown transport %q: must be %s, %s or %s", opts.Transport, Stdio, SSE, HTTP)
+	}
+}
+
+// shutdowner is implemented by the mcp-go SSE and streamable HTTP servers
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// serveListener serves a network transport on ln
+func serveListener(ctx context.Context, s *server.MCPServer, opts Options, ln net.Listener) error {
+	httpServer := &http.Server{ReadHeaderTimeout: 10 * time.Second}
+
+	var mcpServer shutdowner
+	switch opts.Transport {
+	case SSE:
+		sse := server.NewSSEServer(s, server.WithHTTPServer(httpServer))
+		httpServer.Handler = requireToken(opts.AuthToken, sse)
+		mcpServer = sse
+	default:
+		streamable := server.NewStreamableHTTPServer(s,
+			server.WithEndpointPath(HTTPEndpoint),
+			server.WithStreamableHTTPServer(httpServer),
+		)
+		mux := http.NewServeMux()
+		mux.Handle(HTTPEndpoint, requireToken(opts.AuthToken, streamable))
+		httpServer.Handler = mux
+		mcpServer = streamable
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.Serve(ln)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := mcpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown failed: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// requireToken rejects requests without the bearer token. An empty token
+// disables authentication.
+func requireToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="devspace-mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsLoopback reports whether addr only listens on the loopback interface
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
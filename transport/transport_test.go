@@ -0,0 +1,156 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`
+
+func TestRequireToken(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	handler := requireToken("s3cret", ok)
+
+	tests := []struct {
+		header string
+		want   int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"s3cret", http.StatusUnauthorized},
+		{"Bearer s3cret", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Authorization %q: status = %d, want %d", tt.header, rec.Code, tt.want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	requireToken("", ok).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("an empty token should disable authentication, got %d", rec.Code)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		"0.0.0.0:8080":   false,
+		":8080":          false,
+		"10.0.0.5:8080":  false,
+	}
+	for addr, want := range tests {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+// startServer serves a new MCP server on a random local port
+func startServer(t *testing.T, opts Options) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	s := server.NewMCPServer("test", "0.0.0", server.WithToolCapabilities(true))
+	go func() { done <- serveListener(ctx, s, opts, ln) }()
+	return "http://" + ln.Addr().String(), cancel, done
+}
+
+// waitShutdown waits for the server to stop after cancel
+func waitShutdown(t *testing.T, cancel context.CancelFunc, done <-chan error) {
+	t.Helper()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serve returned %v after shutdown", err)
+		}
+	case <-time.After(ShutdownTimeout + time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	url, cancel, done := startServer(t, Options{Transport: HTTP, AuthToken: "s3cret"})
+
+	post := func(token string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, url+HTTPEndpoint, strings.NewReader(initializeRequest))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := post(""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("request without token: status = %d", resp.StatusCode)
+	}
+	resp := post("s3cret")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("initialize: status = %d", resp.StatusCode)
+	}
+	if resp.Header.Get(server.HeaderKeySessionID) == "" {
+		t.Error("expected a session ID header")
+	}
+
+	waitShutdown(t, cancel, done)
+}
+
+func TestServeSSE(t *testing.T) {
+	url, cancel, done := startServer(t, Options{Transport: SSE})
+
+	ctx, stopStream := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url+"/sse", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Errorf("unexpected SSE response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	buf := make([]byte, 256)
+	n, _ := resp.Body.Read(buf)
+	if !strings.Contains(string(buf[:n]), "event: endpoint") {
+		t.Errorf("expected the endpoint event, got %q", buf[:n])
+	}
+
+	// Open streams must not block the shutdown
+	waitShutdown(t, cancel, done)
+	stopStream()
+}
+
+func TestServeUnknownTransport(t *testing.T) {
+	s := server.NewMCPServer("test", "0.0.0")
+	err := Serve(context.Background(), s, Options{Transport: "websocket"})
+	if err == nil || !strings.Contains(err.Error(), "unknown transport") {
+		t.Errorf("expected an unknown transport error, got %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+// Package workspace keeps a registry of the devspace projects found under a
+// set of root directories, such as the services of a monorepo. The registry
+// is rescanned periodically so added, removed and edited configs are picked
+// up while the server runs.
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileNames are the devspace config file names looked up in each
+// directory, in order of preference
+var ConfigFileNames = []string{"devspace.yaml", "devspace.yml"}
+
+// DefaultRefreshInterval is how often Watch rescans the roots by default
+const DefaultRefreshInterval = 10 * time.Second
+
+// skippedDirs are never searched for projects
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+}
+
+// Project is a devspace project found under a root
+type Project struct {
+	Name         string   `json:"name"`
+	Dir          string   `json:"dir"`
+	ConfigPath   string   `json:"config_path"`
+	Profiles     []string `json:"profiles"`
+	Pipelines    []string `json:"pipelines"`
+	Dependencies []string `json:"dependencies"`
+	// Error is set when the config could not be parsed
+	Error string `json:"error,omitempty"`
+
+	modTime time.Time
+}
+
+// Registry holds the projects found under its roots
+type Registry struct {
+	roots []string
+
+	mu       sync.RWMutex
+	projects map[string]Project // by config path
+	scanned  time.Time
+}
+
+// NewRegistry creates a registry of the projects under roots. It is empty
+// until Scan is called.
+func NewRegistry(roots []string) *Registry {
+	abs := make([]string, 0, len(roots))
+	for _, root := range roots {
+		if dir, err := filepath.Abs(root); err == nil {
+			abs = append(abs, dir)
+		}
+	}
+	return &Registry{roots: abs, projects: make(map[string]Project)}
+}
+
+// Roots returns the absolute root directories
+func (r *Registry) Roots() []string {
+	return append([]string{}, r.roots...)
+}
+
+// Scan searches the roots for devspace configs. Only configs that were added
+// or modified since the last scan are parsed. It reports whether the
+// registry changed. A root that cannot be read is reported as an error after
+// the remaining roots were scanned.
+func (r *Registry) Scan() (bool, error) {
+	found := make(map[string]time.Time)
+	var errs []string
+	for _, root := range r.roots {
+		if err := findConfigs(root, found); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	r.mu.RLock()
+	previous := r.projects
+	r.mu.RUnlock()
+
+	changed := len(found) != len(previous)
+	projects := make(map[string]Project, len(found))
+	for path, modTime := range found {
+		if p, ok := previous[path]; ok && p.modTime.Equal(modTime) {
+			projects[path] = p
+			continue
+		}
+		changed = true
+		projects[path] = load(path, modTime)
+	}
+
+	r.mu.Lock()
+	r.projects = projects
+	r.scanned = time.Now()
+	r.mu.Unlock()
+
+	if len(errs) > 0 {
+		return changed, fmt.Errorf("failed to scan project roots: %s", strings.Join(errs, "; "))
+	}
+	return changed, nil
+}
+
+// Watch rescans the roots every interval until ctx is done
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = r.Scan()
+		}
+	}
+}
+
+// Scanned returns the time of the last scan
+func (r *Registry) Scanned() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.scanned
+}
+
+// Projects returns all projects sorted by name and directory
+func (r *Registry) Projects() []Project {
+	r.mu.RLock()
+	projects := make([]Project, 0, len(r.projects))
+	for _, p := range r.projects {
+		projects = append(projects, p)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Name != projects[j].Name {
+			return projects[i].Name < projects[j].Name
+		}
+		return projects[i].Dir < projects[j].Dir
+	})
+	return projects
+}
+
+// Lookup finds a project by name or by its directory relative to a root
+func (r *Registry) Lookup(name string) (Project, error) {
+	var matches []Project
+	for _, p := range r.Projects() {
+		if p.Name == name || r.relativeDir(p.Dir) == filepath.Clean(name) {
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return Project{}, fmt.Errorf("project %q not found; use devspace_list_projects to see the available projects", name)
+	default:
+		dirs := make([]string, len(matches))
+		for i, p := range matches {
+			dirs[i] = r.relativeDir(p.Dir)
+		}
+		return Project{}, fmt.Errorf("project name %q is ambiguous, use one of the directories: %s", name, strings.Join(dirs, ", "))
+	}
+}
+
+// relativeDir returns dir relative to the root containing it
+func (r *Registry) relativeDir(dir string) string {
+	for _, root := range r.roots {
+		if rel, err := filepath.Rel(root, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return dir
+}
+
+// findConfigs records the path and modification time of every devspace
+// config under root, one per directory
+func findConfigs(root string, found map[string]time.Time) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			// Unreadable subdirectories are skipped
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && (strings.HasPrefix(d.Name(), ".") || skippedDirs[d.Name()]) {
+			return filepath.SkipDir
+		}
+		for _, name := range ConfigFileNames {
+			info, err := os.Stat(filepath.Join(path, name))
+			if err == nil && !info.IsDir() {
+				found[filepath.Join(path, name)] = info.ModTime()
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// load reads the project summary from a devspace config
+func load(path string, modTime time.Time) Project {
+	dir := filepath.Dir(path)
+	p := Project{
+		Name:         filepath.Base(dir),
+		Dir:          dir,
+		ConfigPath:   path,
+		Profiles:     []string{},
+		Pipelines:    []string{},
+		Dependencies: []string{},
+		modTime:      modTime,
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	var config struct {
+		Name     string `yaml:"name"`
+		Profiles []struct {
+			Name string `yaml:"name"`
+		} `yaml:"profiles"`
+		Pipelines    map[string]yaml.Node `yaml:"pipelines"`
+		Dependencies map[string]yaml.Node `yaml:"dependencies"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		p.Error = err.Error()
+		return p
+	}
+
+	if config.Name != "" {
+		p.Name = config.Name
+	}
+	for _, profile := range config.Profiles {
+		p.Profiles = append(p.Profiles, profile.Name)
+	}
+	p.Pipelines = sortedKeys(config.Pipelines)
+	p.Dependencies = sortedKeys(config.Dependencies)
+	return p
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]yaml.Node) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
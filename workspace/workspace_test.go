@@ -0,0 +1,158 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const apiConfig = `version: v2beta1
+name: api
+dependencies:
+  db:
+    path: ../db
+  auth:
+    git: https://github.com/example/auth
+pipelines:
+  dev:
+    run: run_dependencies --all && start_dev app
+  deploy:
+    run: create_deployments --all
+profiles:
+  - name: staging
+  - name: debug
+`
+
+// writeFile creates a file and its parent directories
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "services", "api", "devspace.yaml"), apiConfig)
+	writeFile(t, filepath.Join(root, "services", "db", "devspace.yml"), "version: v2beta1\n")
+	writeFile(t, filepath.Join(root, "services", "broken", "devspace.yaml"), "name: [unclosed\n")
+	writeFile(t, filepath.Join(root, "web", "node_modules", "pkg", "devspace.yaml"), "name: ignored\n")
+	writeFile(t, filepath.Join(root, ".devspace", "devspace.yaml"), "name: hidden\n")
+
+	r := NewRegistry([]string{root})
+	changed, err := r.Scan()
+	if err != nil || !changed {
+		t.Fatalf("Scan() = %v, %v", changed, err)
+	}
+
+	projects := r.Projects()
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "api,broken,db" {
+		t.Fatalf("projects = %s", got)
+	}
+
+	api := projects[0]
+	if api.Dir != filepath.Join(root, "services", "api") || api.ConfigPath != filepath.Join(api.Dir, "devspace.yaml") {
+		t.Errorf("unexpected location: %+v", api)
+	}
+	if strings.Join(api.Profiles, ",") != "staging,debug" || strings.Join(api.Pipelines, ",") != "deploy,dev" || strings.Join(api.Dependencies, ",") != "auth,db" {
+		t.Errorf("unexpected summary: %+v", api)
+	}
+	if projects[1].Error == "" {
+		t.Error("broken config should report a parse error")
+	}
+	if projects[2].ConfigPath != filepath.Join(root, "services", "db", "devspace.yml") {
+		t.Errorf("db should be found by its devspace.yml, got %s", projects[2].ConfigPath)
+	}
+
+	if changed, _ := r.Scan(); changed {
+		t.Error("rescan without changes should report no change")
+	}
+}
+
+func TestScanPicksUpChanges(t *testing.T) {
+	root := t.TempDir()
+	config := filepath.Join(root, "api", "devspace.yaml")
+	writeFile(t, config, "name: api\n")
+	r := NewRegistry([]string{root})
+	if _, err := r.Scan(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, config, "name: api-v2\n")
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(config, later, later); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "worker", "devspace.yaml"), "name: worker\n")
+
+	if changed, _ := r.Scan(); !changed {
+		t.Fatal("expected a change")
+	}
+	if _, err := r.Lookup("api-v2"); err != nil {
+		t.Errorf("renamed project not found: %v", err)
+	}
+	if _, err := r.Lookup("worker"); err != nil {
+		t.Errorf("added project not found: %v", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, "worker")); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _ := r.Scan(); !changed || len(r.Projects()) != 1 {
+		t.Errorf("removed project should be dropped, got %d projects", len(r.Projects()))
+	}
+}
+
+func TestLookup(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a", "devspace.yaml"), "name: shared\n")
+	writeFile(t, filepath.Join(root, "b", "devspace.yaml"), "name: shared\n")
+	writeFile(t, filepath.Join(root, "c", "devspace.yaml"), "version: v2beta1\n")
+	r := NewRegistry([]string{root})
+	if _, err := r.Scan(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, wantDir, wantErr string
+	}{
+		{name: "c", wantDir: filepath.Join(root, "c")},
+		{name: "a", wantDir: filepath.Join(root, "a")},
+		{name: "shared", wantErr: "ambiguous"},
+		{name: "missing", wantErr: "not found"},
+	}
+	for _, tt := range tests {
+		p, err := r.Lookup(tt.name)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Lookup(%s) error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || p.Dir != tt.wantDir {
+			t.Errorf("Lookup(%s) = %s, %v", tt.name, p.Dir, err)
+		}
+	}
+}
+
+func TestScanMissingRoot(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "api", "devspace.yaml"), "name: api\n")
+	r := NewRegistry([]string{filepath.Join(root, "missing"), root})
+
+	if _, err := r.Scan(); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an error naming the missing root, got %v", err)
+	}
+	if len(r.Projects()) != 1 {
+		t.Errorf("other roots should still be scanned, got %d projects", len(r.Projects()))
+	}
+}